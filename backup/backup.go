@@ -0,0 +1,503 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FormatVersion is the archive layout version written to the manifest.
+// Bump it whenever the archive layout changes in an incompatible way.
+const FormatVersion = 1
+
+// manifestName is the name of the manifest entry inside the archive
+const manifestName = "manifest.json"
+
+// redactedValue replaces secrets in redacted archives
+const redactedValue = "[REDACTED]"
+
+// Manifest describes the contents of a backup archive
+type Manifest struct {
+	FormatVersion int              `json:"formatVersion"`
+	Database      string           `json:"database"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	Redacted      bool             `json:"redacted"`
+	Collections   map[string]int64 `json:"collections"`
+}
+
+// Mode controls how Restore writes documents back to the database
+type Mode string
+
+const (
+	// ModeDryRun reads the archive and reports what would change without writing
+	ModeDryRun Mode = "dry-run"
+	// ModeMerge upserts archived documents by _id and leaves other documents untouched
+	ModeMerge Mode = "merge"
+	// ModeReplace empties each archived collection before inserting its documents.
+	// It refuses redacted archives and only appends to append-only collections.
+	ModeReplace Mode = "replace"
+)
+
+// ParseMode validates a restore mode string
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case ModeDryRun, ModeMerge, ModeReplace:
+		return Mode(s), nil
+	}
+	return "", fmt.Errorf("unknown restore mode %q (expected dry-run, merge or replace)", s)
+}
+
+// ExportOptions configures Export
+type ExportOptions struct {
+	// Redact replaces password hashes and other secrets with a placeholder
+	Redact bool
+}
+
+// RestoreOptions configures Restore
+type RestoreOptions struct {
+	Mode Mode
+}
+
+// CollectionReport summarises the restore of a single collection
+type CollectionReport struct {
+	Documents int64 `json:"documents"`
+	Inserted  int64 `json:"inserted"`
+	Updated   int64 `json:"updated"`
+	Deleted   int64 `json:"deleted"`
+	// Skipped counts documents of append-only collections that already exist
+	Skipped int64 `json:"skipped"`
+}
+
+// RestoreReport summarises a restore run
+type RestoreReport struct {
+	Mode        Mode                         `json:"mode"`
+	Manifest    Manifest                     `json:"manifest"`
+	Collections map[string]*CollectionReport `json:"collections"`
+	// Contacts reports the normalization of restored team registrations
+	Contacts *models.ContactReport `json:"contacts,omitempty"`
+}
+
+// collections returns the collections included in a backup, keyed by archive name
+func collections(db *models.DatabaseService) map[string]*mongo.Collection {
+	return map[string]*mongo.Collection{
+		"users":             db.UserCollection,
+		"teamregistrations": db.TeamCollection,
+		"videos":            db.Videos,
+		"events":            db.Events,
+		"evaluations":       db.Evaluations,
+		"catalog":           db.Catalog,
+		"certificates":      db.Certificates,
+		"changerequests":    db.ChangeRequests,
+		"audit_log":         db.AuditLog,
+		"counters":          db.Counters,
+	}
+}
+
+// appendOnly lists the collections restores only add missing documents to: the audit
+// log is never emptied nor rewritten, whatever the mode
+var appendOnly = map[string]bool{
+	"audit_log": true,
+}
+
+// secretFields lists, per collection, the fields replaced when redacting
+var secretFields = map[string][]string{
+	"users": {"password"},
+}
+
+// Export writes every backed-up collection to a gzip-compressed tar archive at path.
+// Each collection is stored as <name>.jsonl with one canonical Extended JSON document per line.
+func Export(db *models.DatabaseService, path string, opts ExportOptions) (*Manifest, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create archive: %v", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		Database:      db.Database.Name(),
+		CreatedAt:     time.Now().UTC(),
+		Redacted:      opts.Redact,
+		Collections:   make(map[string]int64),
+	}
+
+	colls := collections(db)
+	for _, name := range slices.Sorted(maps.Keys(colls)) {
+		data, count, err := dumpCollection(colls[name], name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %v", name, err)
+		}
+		if err := writeEntry(tw, name+".jsonl", data); err != nil {
+			return nil, err
+		}
+		manifest.Collections[name] = count
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, manifestName, data); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, f.Close()
+}
+
+// dumpCollection serialises all documents of a collection as Extended JSON lines
+func dumpCollection(coll *mongo.Collection, name string, opts ExportOptions) ([]byte, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	cursor, err := coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var buf bytes.Buffer
+	var count int64
+	for cursor.Next(ctx) {
+		var doc bson.D
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, 0, err
+		}
+		if opts.Redact {
+			doc = redact(doc, secretFields[name])
+		}
+		line, err := bson.MarshalExtJSON(doc, true, false)
+		if err != nil {
+			return nil, 0, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), count, nil
+}
+
+// redact replaces the given top-level fields with a placeholder
+func redact(doc bson.D, fields []string) bson.D {
+	for i, elem := range doc {
+		for _, f := range fields {
+			if elem.Key == f {
+				doc[i].Value = redactedValue
+			}
+		}
+	}
+	return doc
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// readArchive loads the manifest and the raw collection entries from an archive
+func readArchive(path string) (*Manifest, map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open archive: %v", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read archive: %v", err)
+	}
+	defer gz.Close()
+
+	var manifest *Manifest
+	entries := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("read archive: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if hdr.Name == manifestName {
+			manifest = &Manifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, nil, fmt.Errorf("invalid manifest: %v", err)
+			}
+			continue
+		}
+		entries[hdr.Name] = data
+	}
+
+	if manifest == nil {
+		return nil, nil, errors.New("archive has no manifest")
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, nil, fmt.Errorf("archive format version %d is newer than supported version %d", manifest.FormatVersion, FormatVersion)
+	}
+	return manifest, entries, nil
+}
+
+// Restore loads an archive created by Export back into the database.
+// Redacted archives never overwrite secrets of existing documents, so they
+// can only be restored in merge mode. Every collection listed in the manifest must
+// have its entry, and the audit log is only ever appended to.
+func Restore(db *models.DatabaseService, path string, opts RestoreOptions) (*RestoreReport, error) {
+	manifest, entries, err := readArchive(path)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RestoreReport{
+		Mode:        opts.Mode,
+		Manifest:    *manifest,
+		Collections: make(map[string]*CollectionReport),
+	}
+
+	// Replacing a collection from a redacted archive would wipe every password
+	if manifest.Redacted && opts.Mode == ModeReplace {
+		return nil, errors.New("redacted archives can't be restored in replace mode (use merge to keep existing secrets)")
+	}
+
+	// Decode every collection before writing anything, so a corrupt archive
+	// never leaves the database half restored
+	colls := collections(db)
+	parsed := make(map[string][]bson.D, len(manifest.Collections))
+	for name := range manifest.Collections {
+		if _, ok := colls[name]; !ok {
+			return nil, fmt.Errorf("archive contains unknown collection %q", name)
+		}
+		data, ok := entries[name+".jsonl"]
+		if !ok {
+			return nil, fmt.Errorf("archive has no %s.jsonl entry for collection %q listed in its manifest", name, name)
+		}
+		docs, err := parseLines(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %v", name, err)
+		}
+		if manifest.Redacted {
+			for i := range docs {
+				docs[i] = stripRedacted(docs[i])
+			}
+		}
+		parsed[name] = docs
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(parsed)) {
+		docs := parsed[name]
+		mode := opts.Mode
+		if appendOnly[name] && mode != ModeDryRun {
+			mode = modeAppend
+		}
+		res, err := restoreCollection(colls[name], docs, mode, appendOnly[name])
+		if err != nil {
+			return nil, fmt.Errorf("restore %s: %v", name, err)
+		}
+		report.Collections[name] = res
+	}
+
+	// Archives from older versions may hold contacts in any format
+	if _, ok := manifest.Collections["teamregistrations"]; ok && opts.Mode != ModeDryRun {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		if report.Contacts, err = db.NormalizeTeamContacts(ctx); err != nil {
+			return nil, fmt.Errorf("normalize contacts: %v", err)
+		}
+	}
+	return report, nil
+}
+
+// parseLines decodes Extended JSON lines into documents
+func parseLines(data []byte) ([]bson.D, error) {
+	var docs []bson.D
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var doc bson.D
+		if err := bson.UnmarshalExtJSON(scanner.Bytes(), true, &doc); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, scanner.Err()
+}
+
+// stripRedacted removes placeholder values so they are never written back
+func stripRedacted(doc bson.D) bson.D {
+	out := doc[:0]
+	for _, elem := range doc {
+		if s, ok := elem.Value.(string); ok && s == redactedValue {
+			continue
+		}
+		out = append(out, elem)
+	}
+	return out
+}
+
+func documentID(doc bson.D) (interface{}, bool) {
+	for _, elem := range doc {
+		if elem.Key == "_id" {
+			return elem.Value, true
+		}
+	}
+	return nil, false
+}
+
+// withoutID returns the fields of doc other than _id
+func withoutID(doc bson.D) bson.D {
+	fields := bson.D{}
+	for _, elem := range doc {
+		if elem.Key != "_id" {
+			fields = append(fields, elem)
+		}
+	}
+	return fields
+}
+
+// modeAppend inserts the archived documents missing from a collection and leaves the
+// existing ones untouched; Restore uses it for append-only collections
+const modeAppend Mode = "append"
+
+// restoreCollection writes docs to coll in mode. A dry run of an append-only collection
+// reports existing documents as skipped rather than updated.
+func restoreCollection(coll *mongo.Collection, docs []bson.D, mode Mode, appendOnly bool) (*CollectionReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	res := &CollectionReport{Documents: int64(len(docs))}
+
+	switch mode {
+	case ModeDryRun:
+		for _, doc := range docs {
+			id, ok := documentID(doc)
+			if !ok {
+				res.Inserted++
+				continue
+			}
+			n, err := coll.CountDocuments(ctx, bson.M{"_id": id})
+			if err != nil {
+				return nil, err
+			}
+			if n > 0 && appendOnly {
+				res.Skipped++
+			} else if n > 0 {
+				res.Updated++
+			} else {
+				res.Inserted++
+			}
+		}
+		return res, nil
+
+	case ModeReplace:
+		deleted, err := coll.DeleteMany(ctx, bson.M{})
+		if err != nil {
+			return nil, err
+		}
+		res.Deleted = deleted.DeletedCount
+		if len(docs) == 0 {
+			return res, nil
+		}
+		batch := make([]interface{}, len(docs))
+		for i, doc := range docs {
+			batch[i] = doc
+		}
+		inserted, err := coll.InsertMany(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		res.Inserted = int64(len(inserted.InsertedIDs))
+		return res, nil
+
+	case modeAppend:
+		for _, doc := range docs {
+			id, ok := documentID(doc)
+			if !ok {
+				if _, err := coll.InsertOne(ctx, doc); err != nil {
+					return nil, err
+				}
+				res.Inserted++
+				continue
+			}
+			// $setOnInsert leaves documents already in the collection as they are
+			upserted, err := coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$setOnInsert": withoutID(doc)}, options.Update().SetUpsert(true))
+			if err != nil {
+				return nil, err
+			}
+			if upserted.UpsertedCount > 0 {
+				res.Inserted++
+			} else {
+				res.Skipped++
+			}
+		}
+		return res, nil
+
+	case ModeMerge:
+		for _, doc := range docs {
+			id, ok := documentID(doc)
+			if !ok {
+				if _, err := coll.InsertOne(ctx, doc); err != nil {
+					return nil, err
+				}
+				res.Inserted++
+				continue
+			}
+			// $set keeps fields missing from the archive (e.g. redacted secrets) intact
+			updated, err := coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": withoutID(doc)}, options.Update().SetUpsert(true))
+			if err != nil {
+				return nil, err
+			}
+			if updated.UpsertedCount > 0 {
+				res.Inserted++
+			} else {
+				res.Updated++
+			}
+		}
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("unknown restore mode %q", mode)
+}
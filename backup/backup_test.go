@@ -0,0 +1,249 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// testDatabase is the database name of the mock deployment
+const testDatabase = "igc_test"
+
+func newTestDB(mt *mtest.T) *models.DatabaseService {
+	return models.NewDatabaseService(mt.Client, testDatabase, "videos")
+}
+
+// archived lists the backed-up collections in the order Export and Restore visit them
+func archived(mt *mtest.T) []string {
+	return slices.Sorted(maps.Keys(collections(newTestDB(mt))))
+}
+
+// exportReplies answers the find of every collection Export dumps, returning docs
+// for the collections in docs and nothing for the others
+func exportReplies(mt *mtest.T, docs map[string][]bson.D) {
+	for _, name := range archived(mt) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, testDatabase+"."+name, mtest.FirstBatch, docs[name]...))
+	}
+}
+
+// commands returns the started commands sent to the mock deployment, by name
+func commands(mt *mtest.T, name string) []*event.CommandStartedEvent {
+	var found []*event.CommandStartedEvent
+	for _, e := range mt.GetAllStartedEvents() {
+		if e.CommandName == name {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// writeArchive writes an archive with the given manifest and entries
+func writeArchive(t *testing.T, manifest Manifest, entries map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "igc-backup.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, data := range entries {
+		if err := writeEntry(tw, name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := json.Marshal(manifest)
+	if err := writeEntry(tw, manifestName, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func testDocuments() (user, entry bson.D) {
+	user = bson.D{
+		{Key: "_id", Value: primitive.NewObjectID()},
+		{Key: "username", Value: "judge1"},
+		{Key: "password", Value: "$2a$10$hash"},
+	}
+	entry = bson.D{
+		{Key: "_id", Value: primitive.NewObjectID()},
+		{Key: "action", Value: string(models.AuditUserCreate)},
+		{Key: "createdAt", Value: primitive.NewDateTimeFromTime(time.Now())},
+	}
+	return user, entry
+}
+
+func TestRoundTrip(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("replace restores the export", func(mt *mtest.T) {
+		user, entry := testDocuments()
+		path := filepath.Join(mt.TempDir(), "igc-backup.tar.gz")
+		exportReplies(mt, map[string][]bson.D{"users": {user}, "audit_log": {entry}})
+
+		manifest, err := Export(newTestDB(mt), path, ExportOptions{})
+		if err != nil {
+			mt.Fatalf("Export: %v", err)
+		}
+		if manifest.Collections["users"] != 1 || manifest.Collections["audit_log"] != 1 || manifest.Collections["events"] != 0 {
+			mt.Errorf("manifest = %v, want one user and one audit entry", manifest.Collections)
+		}
+		mt.ClearEvents()
+
+		for _, name := range archived(mt) {
+			switch name {
+			case "audit_log":
+				mt.AddMockResponses(mtest.CreateSuccessResponse(
+					bson.E{Key: "n", Value: 1},
+					bson.E{Key: "upserted", Value: bson.A{bson.D{{Key: "index", Value: 0}, {Key: "_id", Value: entry[0].Value}}}},
+				))
+			case "users":
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}), mtest.CreateSuccessResponse())
+			default:
+				mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+			}
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, testDatabase+".teamregistrations", mtest.FirstBatch))
+
+		report, err := Restore(newTestDB(mt), path, RestoreOptions{Mode: ModeReplace})
+		if err != nil {
+			mt.Fatalf("Restore: %v", err)
+		}
+		if got := report.Collections["users"]; got.Deleted != 2 || got.Inserted != 1 {
+			mt.Errorf("users report = %+v, want 2 deleted and 1 inserted", got)
+		}
+		if got := report.Collections["audit_log"]; got.Deleted != 0 || got.Inserted != 1 {
+			mt.Errorf("audit log report = %+v, want 1 appended", got)
+		}
+
+		inserts := commands(mt, "insert")
+		if len(inserts) != 1 || inserts[0].Command.Lookup("insert").StringValue() != "users" {
+			mt.Fatalf("inserts = %v, want the users only", inserts)
+		}
+		restored := inserts[0].Command.Lookup("documents").Array().Index(0).Value().Document()
+		if restored.Lookup("password").StringValue() != "$2a$10$hash" || restored.Lookup("_id").ObjectID() != user[0].Value {
+			mt.Errorf("restored user = %v, want the exported one", restored)
+		}
+		for _, e := range commands(mt, "delete") {
+			if e.Command.Lookup("delete").StringValue() == "audit_log" {
+				mt.Error("replace emptied the audit log")
+			}
+		}
+		appended := commands(mt, "update")
+		if len(appended) != 1 || appended[0].Command.Lookup("update").StringValue() != "audit_log" {
+			mt.Fatalf("updates = %v, want the audit log append only", appended)
+		}
+		upsert := appended[0].Command.Lookup("updates").Array().Index(0).Value().Document()
+		if upsert.Lookup("u", "$setOnInsert", "action").StringValue() != string(models.AuditUserCreate) || !upsert.Lookup("upsert").Boolean() {
+			mt.Errorf("audit append = %v, want the entry inserted only if missing", upsert)
+		}
+	})
+}
+
+func TestRestoreMissingEntry(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	for _, mode := range []Mode{ModeDryRun, ModeMerge, ModeReplace} {
+		mt.Run(string(mode), func(mt *mtest.T) {
+			user, _ := testDocuments()
+			line, _ := bson.MarshalExtJSON(user, true, false)
+			path := writeArchive(mt.T, Manifest{
+				FormatVersion: FormatVersion,
+				Collections:   map[string]int64{"users": 1, "events": 3},
+			}, map[string]string{"users.jsonl": string(line) + "\n"})
+
+			_, err := Restore(newTestDB(mt), path, RestoreOptions{Mode: mode})
+			if err == nil || !strings.Contains(err.Error(), "events.jsonl") {
+				mt.Fatalf("err = %v, want the missing events entry reported", err)
+			}
+			if started := mt.GetAllStartedEvents(); len(started) != 0 {
+				mt.Errorf("sent %s before failing", started[0].CommandName)
+			}
+		})
+	}
+}
+
+func TestRedactedArchive(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	export := func(mt *mtest.T) (string, bson.D) {
+		user, _ := testDocuments()
+		path := filepath.Join(mt.TempDir(), "igc-backup.tar.gz")
+		exportReplies(mt, map[string][]bson.D{"users": {user}})
+		manifest, err := Export(newTestDB(mt), path, ExportOptions{Redact: true})
+		if err != nil {
+			mt.Fatalf("Export: %v", err)
+		}
+		if !manifest.Redacted {
+			mt.Error("manifest doesn't mark the archive redacted")
+		}
+		_, entries, err := readArchive(path)
+		if err != nil {
+			mt.Fatalf("readArchive: %v", err)
+		}
+		if users := string(entries["users.jsonl"]); strings.Contains(users, "$2a$10$hash") || !strings.Contains(users, redactedValue) {
+			mt.Errorf("users.jsonl = %s, want the password redacted", users)
+		}
+		mt.ClearEvents()
+		return path, user
+	}
+
+	mt.Run("refused in replace mode", func(mt *mtest.T) {
+		path, _ := export(mt)
+
+		if _, err := Restore(newTestDB(mt), path, RestoreOptions{Mode: ModeReplace}); err == nil {
+			mt.Fatal("restored a redacted archive in replace mode")
+		}
+		if started := mt.GetAllStartedEvents(); len(started) != 0 {
+			mt.Errorf("sent %s before failing", started[0].CommandName)
+		}
+	})
+
+	mt.Run("merge keeps existing passwords", func(mt *mtest.T) {
+		path, user := export(mt)
+		// Merge only writes the archived documents: the user, then the contact normalization
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, testDatabase+".teamregistrations", mtest.FirstBatch),
+		)
+
+		if _, err := Restore(newTestDB(mt), path, RestoreOptions{Mode: ModeMerge}); err != nil {
+			mt.Fatalf("Restore: %v", err)
+		}
+		updates := commands(mt, "update")
+		if len(updates) != 1 {
+			mt.Fatalf("updates = %v, want the user only", updates)
+		}
+		update := updates[0].Command.Lookup("updates").Array().Index(0).Value().Document()
+		if update.Lookup("q", "_id").ObjectID() != user[0].Value {
+			mt.Errorf("update = %v, want the exported user", update)
+		}
+		set := update.Lookup("u", "$set").Document()
+		if set.Lookup("username").StringValue() != "judge1" {
+			mt.Errorf("set = %v, want the username restored", set)
+		}
+		if _, err := set.LookupErr("password"); err == nil {
+			mt.Errorf("set = %v, overwrites the password", set)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+// Package buildinfo reports the version of the running binary. The values are
+// injected at build time, e.g.
+//
+//	go build -ldflags "-X github.com/Mastermind730/igc-admin-backend/buildinfo.Version=v1.4.0 \
+//	  -X github.com/Mastermind730/igc-admin-backend/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/Mastermind730/igc-admin-backend/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them, the commit and time recorded by the Go toolchain are used.
+package buildinfo
+
+import "runtime/debug"
+
+var (
+	// Version is the release version
+	Version = "dev"
+	// Commit is the git commit the binary was built from
+	Commit = ""
+	// BuildTime is when the binary was built (RFC 3339)
+	BuildTime = ""
+)
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build information, falling back to the VCS stamp of the Go toolchain
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = s.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	return info
+}
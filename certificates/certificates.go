@@ -0,0 +1,214 @@
+package certificates
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"text/template"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/go-pdf/fpdf"
+)
+
+// Template describes the text printed on one kind of certificate.
+// Every field is a Go text/template evaluated against Data.
+type Template struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	Body     string `json:"body"`
+	Footer   string `json:"footer"`
+	Signer   string `json:"signer"`
+}
+
+// Data holds the values available to certificate templates
+type Data struct {
+	Name               string
+	Role               string
+	TeamName           string
+	RegistrationNumber string
+	Institution        string
+	Track              string
+	Award              string
+	Code               string
+	IssuedOn           string
+	VerifyURL          string
+}
+
+// DefaultTemplates are used for any certificate type missing from the templates file
+var DefaultTemplates = map[models.CertificateType]Template{
+	models.CertificateParticipation: {
+		Title:    "Certificate of Participation",
+		Subtitle: "PCCOE International Green Coding Challenge",
+		Body:     "This is to certify that {{.Name}} of team {{.TeamName}}, {{.Institution}}, participated in the {{.Track}} track.",
+		Footer:   "Issued on {{.IssuedOn}} - Verification code {{.Code}}",
+		Signer:   "Event Coordinator",
+	},
+	models.CertificateWinner: {
+		Title:    "Certificate of Excellence",
+		Subtitle: "PCCOE International Green Coding Challenge",
+		Body:     "This is to certify that {{.Name}} of team {{.TeamName}}, {{.Institution}}, has been awarded {{.Award}} in the {{.Track}} track.",
+		Footer:   "Issued on {{.IssuedOn}} - Verification code {{.Code}}",
+		Signer:   "Event Coordinator",
+	},
+}
+
+// Renderer renders certificates to PDF
+type Renderer struct {
+	templates     map[models.CertificateType]Template
+	verifyBaseURL string
+}
+
+// NewRenderer creates a Renderer. templatesPath may point to a JSON file mapping
+// certificate types to templates; types not present fall back to DefaultTemplates.
+// verifyBaseURL is prefixed to certificate codes to build the verification link.
+func NewRenderer(templatesPath, verifyBaseURL string) (*Renderer, error) {
+	templates := make(map[models.CertificateType]Template)
+	for k, v := range DefaultTemplates {
+		templates[k] = v
+	}
+
+	if templatesPath != "" {
+		data, err := os.ReadFile(templatesPath)
+		if err != nil {
+			return nil, fmt.Errorf("read certificate templates: %v", err)
+		}
+		var custom map[models.CertificateType]Template
+		if err := json.Unmarshal(data, &custom); err != nil {
+			return nil, fmt.Errorf("parse certificate templates: %v", err)
+		}
+		for k, v := range custom {
+			templates[k] = v
+		}
+	}
+
+	// Fail early on broken templates instead of on the first render
+	for k, t := range templates {
+		for _, s := range []string{t.Title, t.Subtitle, t.Body, t.Footer, t.Signer} {
+			if _, err := template.New(string(k)).Parse(s); err != nil {
+				return nil, fmt.Errorf("invalid %s certificate template: %v", k, err)
+			}
+		}
+	}
+
+	return &Renderer{templates: templates, verifyBaseURL: verifyBaseURL}, nil
+}
+
+// DataFor builds the template data for a certificate
+func (r *Renderer) DataFor(cert *models.Certificate) Data {
+	return Data{
+		Name:               cert.RecipientName,
+		Role:               string(cert.RecipientRole),
+		TeamName:           cert.TeamName,
+		RegistrationNumber: cert.RegistrationNumber,
+		Institution:        cert.Institution,
+		Track:              string(cert.Track),
+		Award:              cert.Award,
+		Code:               cert.Code,
+		IssuedOn:           cert.IssuedAt.Format("02 January 2006"),
+		VerifyURL:          r.verifyBaseURL + cert.Code,
+	}
+}
+
+// Render writes the certificate as a PDF to w
+func (r *Renderer) Render(w io.Writer, cert *models.Certificate) error {
+	tmpl, ok := r.templates[cert.Type]
+	if !ok {
+		return fmt.Errorf("no template for certificate type %q", cert.Type)
+	}
+	data := r.DataFor(cert)
+
+	text := func(s string) (string, error) {
+		t, err := template.New("cert").Parse(s)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	var fields [5]string
+	for i, s := range []string{tmpl.Title, tmpl.Subtitle, tmpl.Body, tmpl.Footer, tmpl.Signer} {
+		out, err := text(s)
+		if err != nil {
+			return err
+		}
+		fields[i] = out
+	}
+	title, subtitle, body, footer, signer := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+	pdf := fpdf.New("L", "mm", "A4", "")
+	// Core fonts are cp1252; translate so accented names render correctly
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle(title, true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+
+	pw, ph := pdf.GetPageSize()
+	pdf.SetDrawColor(34, 110, 60)
+	pdf.SetLineWidth(2)
+	pdf.Rect(10, 10, pw-20, ph-20, "D")
+	pdf.SetLineWidth(0.5)
+	pdf.Rect(14, 14, pw-28, ph-28, "D")
+
+	pdf.SetY(35)
+	pdf.SetTextColor(34, 110, 60)
+	pdf.SetFont("Helvetica", "B", 32)
+	pdf.CellFormat(0, 14, tr(title), "", 1, "C", false, 0, "")
+	pdf.SetTextColor(60, 60, 60)
+	pdf.SetFont("Helvetica", "", 14)
+	pdf.CellFormat(0, 10, tr(subtitle), "", 1, "C", false, 0, "")
+
+	pdf.Ln(12)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont("Helvetica", "B", 26)
+	pdf.CellFormat(0, 14, tr(data.Name), "", 1, "C", false, 0, "")
+
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "", 14)
+	pdf.SetX(35)
+	pdf.MultiCell(pw-70, 8, tr(body), "", "C", false)
+
+	pdf.SetY(ph - 55)
+	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetX(pw - 100)
+	pdf.CellFormat(70, 6, "______________________", "", 2, "C", false, 0, "")
+	pdf.CellFormat(70, 6, tr(signer), "", 1, "C", false, 0, "")
+
+	pdf.SetY(ph - 30)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.SetTextColor(90, 90, 90)
+	pdf.CellFormat(0, 5, tr(footer), "", 1, "C", false, 0, "")
+	pdf.CellFormat(0, 5, tr("Verify at "+data.VerifyURL), "", 1, "C", false, 0, "")
+
+	return pdf.Output(w)
+}
+
+// codeAlphabet avoids characters that are easily confused when typed (0/O, 1/I)
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// NewCode generates a random verification code such as IGC-7KQ2-M9XA
+func NewCode() (string, error) {
+	b := make([]byte, 8)
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = codeAlphabet[n.Int64()]
+	}
+	return fmt.Sprintf("IGC-%s-%s", b[:4], b[4:]), nil
+}
+
+// FileName returns the file name used for a certificate PDF
+func FileName(cert *models.Certificate) string {
+	return fmt.Sprintf("%s_%s_%s.pdf", cert.RegistrationNumber, cert.Type, cert.Code)
+}
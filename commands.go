@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/backup"
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// runCommand dispatches a CLI subcommand and returns the process exit code
+func runCommand(name string, args []string) int {
+	switch name {
+	case "serve":
+		return runServe(args)
+	case "migrate":
+		return runMigrate(args)
+	case "create-admin":
+		return runCreateAdmin(args)
+	case "reset-password":
+		return runResetPassword(args)
+	case "seed":
+		return runSeed(args)
+	case "export", "backup":
+		return runBackup(args)
+	case "import", "restore":
+		return runRestore(args)
+	case "reindex":
+		return runReindex(args)
+	case "help", "-h", "--help":
+		printUsage()
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+	printUsage()
+	return 2
+}
+
+func printUsage() {
+	fmt.Println("Usage: igc-admin-backend [command] [flags]")
+	fmt.Println()
+	fmt.Println("Without a command the API server is started.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  serve           Start the API server")
+	fmt.Println("  migrate         Create indexes, seed the catalogue, prepare the default event and normalize contacts")
+	fmt.Println("  create-admin    Create an admin (or judge) account")
+	fmt.Println("  reset-password  Set a new password for an account")
+	fmt.Println("  seed            Insert fake teams and judges into an event for local testing")
+	fmt.Println("  export          Export the database to a compressed archive (alias: backup)")
+	fmt.Println("  import          Load an export archive (modes: dry-run, merge, replace) (alias: restore)")
+	fmt.Println("  reindex         Create missing indexes, or rebuild all of them with -drop")
+	fmt.Println()
+	fmt.Println("Run 'igc-admin-backend <command> -h' for command flags.")
+}
+
+// connectDatabase opens the MongoDB connection used by CLI commands
+func connectDatabase() (*models.DatabaseService, error) {
+	cfg := loadConfig()
+	client, err := SetupMongoDB(cfg.Database.URI)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewDatabaseService(client, cfg.Database.Name, cfg.Database.VideoCollection), nil
+}
+
+// generatePassword returns a random password for accounts created without one
+func generatePassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Parse(args)
+
+	db, err := connectDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to MongoDB:", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.EnsureIndexes(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to create indexes:", err)
+		return 1
+	}
+	fmt.Println("✅ Indexes created")
+
+	if err := db.EnsureCatalog(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to seed tracks and programs:", err)
+		return 1
+	}
+	fmt.Println("✅ Track and program catalogue ready")
+
+	event, err := db.EnsureDefaultEvent(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to prepare default event:", err)
+		return 1
+	}
+	fmt.Printf("✅ Active event: %s (%s)\n", event.Name, event.Slug)
+
+	contacts, err := db.NormalizeTeamContacts(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to normalize contacts:", err)
+		return 1
+	}
+	fmt.Printf("✅ Contacts normalized (%d of %d registrations updated)\n", contacts.Updated, contacts.Scanned)
+	if len(contacts.Invalid) > 0 {
+		fmt.Printf("⚠️  %d phone numbers could not be read and were left unchanged:\n", len(contacts.Invalid))
+		for _, c := range contacts.Invalid {
+			fmt.Printf("   %s %-20s %s\n", c.TeamID, c.Field, c.Message)
+		}
+	}
+	return 0
+}
+
+func runCreateAdmin(args []string) int {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	username := fs.String("username", "", "Username of the new account (required)")
+	password := fs.String("password", "", "Password (at least 6 characters); a random one is generated when empty")
+	role := fs.String("role", "admin", "Role of the new account: admin or judge")
+	fs.Parse(args)
+
+	if *username == "" {
+		fmt.Fprintln(os.Stderr, "create-admin: -username is required")
+		fs.Usage()
+		return 2
+	}
+	if *role != "admin" && *role != "judge" {
+		fmt.Fprintln(os.Stderr, "create-admin: -role must be admin or judge")
+		return 2
+	}
+	if *password != "" && len(*password) < 6 {
+		fmt.Fprintln(os.Stderr, "create-admin: -password must be at least 6 characters")
+		return 2
+	}
+
+	generated := *password == ""
+	if generated {
+		var err error
+		if *password, err = generatePassword(); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to generate password:", err)
+			return 1
+		}
+	}
+
+	db, err := connectDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to MongoDB:", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if existing, _ := db.GetUserByUsername(ctx, *username); existing != nil {
+		fmt.Fprintf(os.Stderr, "User %q already exists; use reset-password to change its password\n", *username)
+		return 1
+	}
+
+	user := models.NewUser(*username, *password)
+	user.Role = *role
+	created, err := db.CreateUser(ctx, user)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to create user:", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Created %s %s (%s)\n", created.Role, created.Username, created.ID.Hex())
+	if generated {
+		fmt.Printf("   Password: %s\n", *password)
+	}
+	return 0
+}
+
+func runResetPassword(args []string) int {
+	fs := flag.NewFlagSet("reset-password", flag.ExitOnError)
+	username := fs.String("username", "", "Username of the account (required)")
+	password := fs.String("password", "", "New password (at least 6 characters); a random one is generated when empty")
+	fs.Parse(args)
+
+	if *username == "" {
+		fmt.Fprintln(os.Stderr, "reset-password: -username is required")
+		fs.Usage()
+		return 2
+	}
+	if *password != "" && len(*password) < 6 {
+		fmt.Fprintln(os.Stderr, "reset-password: -password must be at least 6 characters")
+		return 2
+	}
+
+	generated := *password == ""
+	if generated {
+		var err error
+		if *password, err = generatePassword(); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to generate password:", err)
+			return 1
+		}
+	}
+
+	db, err := connectDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to MongoDB:", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	user, err := db.GetUserByUsername(ctx, *username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "User %q not found\n", *username)
+		return 1
+	}
+	if _, err := db.UpdateUser(ctx, user.ID.Hex(), user.Version, bson.M{"password": *password}); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to reset password:", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Password of %s reset\n", user.Username)
+	if generated {
+		fmt.Printf("   Password: %s\n", *password)
+	}
+	return 0
+}
+
+func runReindex(args []string) int {
+	fs := flag.NewFlagSet("reindex", flag.ExitOnError)
+	drop := fs.Bool("drop", false, "Drop every index (except _id) first and rebuild the required ones")
+	fs.Parse(args)
+
+	db, err := connectDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to MongoDB:", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if *drop {
+		err = db.RebuildIndexes(ctx)
+	} else {
+		err = db.EnsureIndexes(ctx)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Reindex failed:", err)
+		return 1
+	}
+
+	missing, err := db.MissingIndexes(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to verify indexes:", err)
+		return 1
+	}
+	if len(missing) > 0 {
+		fmt.Fprintln(os.Stderr, "Indexes still missing:", strings.Join(missing, ", "))
+		return 1
+	}
+	fmt.Println("✅ All required indexes exist")
+	return 0
+}
+
+func runBackup(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	out := fs.String("out", fmt.Sprintf("igc-backup-%s.tar.gz", time.Now().Format("20060102-150405")), "Path of the archive to write")
+	redact := fs.Bool("redact", false, "Replace password hashes and other secrets with a placeholder")
+	fs.Parse(args)
+
+	db, err := connectDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to MongoDB:", err)
+		return 1
+	}
+	defer db.Close()
+
+	manifest, err := backup.Export(db, *out, backup.ExportOptions{Redact: *redact})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Backup failed:", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Backup written to %s\n", *out)
+	for name, count := range manifest.Collections {
+		fmt.Printf("   %-18s %d documents\n", name, count)
+	}
+	if manifest.Redacted {
+		fmt.Println("   Secrets were redacted; restore it with -mode merge to keep current passwords.")
+	}
+	return 0
+}
+
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	in := fs.String("in", "", "Path of the archive to restore (required)")
+	modeFlag := fs.String("mode", string(backup.ModeDryRun), "Restore mode: dry-run, merge or replace")
+	fs.Parse(args)
+
+	if *in == "" {
+		fmt.Fprintln(os.Stderr, "import: -in is required")
+		fs.Usage()
+		return 2
+	}
+	mode, err := backup.ParseMode(*modeFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "import:", err)
+		return 2
+	}
+
+	db, err := connectDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to MongoDB:", err)
+		return 1
+	}
+	defer db.Close()
+
+	report, err := backup.Restore(db, *in, backup.RestoreOptions{Mode: mode})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Restore failed:", err)
+		return 1
+	}
+
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+	if mode == backup.ModeDryRun {
+		fmt.Println("Dry run only, no changes were written. Re-run with -mode merge or -mode replace.")
+	}
+	return 0
+}
@@ -0,0 +1,318 @@
+// Package config loads the application configuration from defaults, an optional
+// YAML file and environment variables (including a .env file), in increasing precedence.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the complete application configuration. Each field can be set in the
+// YAML file under its yaml key or through the environment variable in its env tag.
+type Config struct {
+	Server       Server       `yaml:"server"`
+	Database     Database     `yaml:"database"`
+	Auth         Auth         `yaml:"auth"`
+	CORS         CORS         `yaml:"cors"`
+	Pagination   Pagination   `yaml:"pagination"`
+	Features     Features     `yaml:"features"`
+	SMTP         SMTP         `yaml:"smtp"`
+	Certificates Certificates `yaml:"certificates"`
+	Portal       Portal       `yaml:"portal"`
+	Workers      Workers      `yaml:"workers"`
+	Log          Log          `yaml:"log"`
+	Metrics      Metrics      `yaml:"metrics"`
+	Tracing      Tracing      `yaml:"tracing"`
+}
+
+// Server configures the HTTP server
+type Server struct {
+	Port              int           `yaml:"port" env:"PORT"`
+	ReadTimeout       time.Duration `yaml:"readTimeout" env:"HTTP_READ_TIMEOUT"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	// WriteTimeout is generous because certificate ZIP downloads stream large responses
+	WriteTimeout   time.Duration `yaml:"writeTimeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout    time.Duration `yaml:"idleTimeout" env:"HTTP_IDLE_TIMEOUT"`
+	MaxHeaderBytes int           `yaml:"maxHeaderBytes" env:"HTTP_MAX_HEADER_BYTES"`
+	// ShutdownTimeout bounds how long in-flight requests may take to drain
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT"`
+	// VerifyRateLimit is the per-IP request budget per minute of public verification
+	VerifyRateLimit int `yaml:"verifyRateLimitPerMinute" env:"VERIFY_RATE_LIMIT_PER_MINUTE"`
+	// TrustedProxies lists the reverse proxies (IPs or CIDRs) whose X-Forwarded-For
+	// header names the client. Without any, the client is the connection's peer.
+	TrustedProxies []string `yaml:"trustedProxies" env:"TRUSTED_PROXIES"`
+}
+
+// Database configures the MongoDB connection
+type Database struct {
+	URI             string `yaml:"uri" env:"MONGODB_URI"`
+	Name            string `yaml:"name" env:"DATABASE_NAME"`
+	VideoCollection string `yaml:"videoCollection" env:"VIDEO_COLLECTION_NAME"`
+}
+
+// Auth configures tokens and the bootstrap admin account
+type Auth struct {
+	JWTSecret           string        `yaml:"jwtSecret" env:"JWT_SECRET"`
+	AdminTokenTTL       time.Duration `yaml:"adminTokenTTL" env:"ADMIN_TOKEN_TTL"`
+	ParticipantTokenTTL time.Duration `yaml:"participantTokenTTL" env:"PARTICIPANT_TOKEN_TTL"`
+	MagicLinkTTL        time.Duration `yaml:"magicLinkTTL" env:"MAGIC_LINK_TTL"`
+	// AdminUsername and AdminPassword are used to create the first admin when no users exist
+	AdminUsername string `yaml:"adminUsername" env:"ADMIN_USERNAME"`
+	AdminPassword string `yaml:"adminPassword" env:"ADMIN_PASSWORD"`
+}
+
+// CORS configures cross-origin access
+type CORS struct {
+	AllowedOrigins []string `yaml:"allowedOrigins" env:"CORS_ALLOWED_ORIGINS"`
+}
+
+// Pagination configures page-based list endpoints
+type Pagination struct {
+	DefaultLimit int `yaml:"defaultLimit" env:"PAGINATION_DEFAULT_LIMIT"`
+	MaxLimit     int `yaml:"maxLimit" env:"PAGINATION_MAX_LIMIT"`
+}
+
+// Features switches optional parts of the API on or off
+type Features struct {
+	ParticipantPortal  bool `yaml:"participantPortal" env:"FEATURE_PARTICIPANT_PORTAL"`
+	PublicVerification bool `yaml:"publicVerification" env:"FEATURE_PUBLIC_VERIFICATION"`
+	WaitlistReconciler bool `yaml:"waitlistReconciler" env:"FEATURE_WAITLIST_RECONCILER"`
+	Metrics            bool `yaml:"metrics" env:"FEATURE_METRICS"`
+	TrashPurge         bool `yaml:"trashPurge" env:"FEATURE_TRASH_PURGE"`
+}
+
+// SMTP configures outgoing email; without a host emails are only logged
+type SMTP struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+	From     string `yaml:"from" env:"SMTP_FROM"`
+	// DevOutbox is a file that logged emails are appended to in full, so login links
+	// can be followed in local development; the log itself stays redacted
+	DevOutbox string `yaml:"devOutbox" env:"SMTP_DEV_OUTBOX"`
+}
+
+// Certificates configures certificate rendering
+type Certificates struct {
+	// VerifyBaseURL prefixes the certificate code in the verification link printed on
+	// the PDF; defaults to this server
+	VerifyBaseURL string `yaml:"verifyBaseURL" env:"CERTIFICATE_VERIFY_BASE_URL"`
+	TemplatesPath string `yaml:"templatesPath" env:"CERTIFICATE_TEMPLATES_PATH"`
+}
+
+// Portal configures the participant portal frontend
+type Portal struct {
+	// URL is the frontend page that receives magic link tokens
+	URL string `yaml:"url" env:"PARTICIPANT_PORTAL_URL"`
+}
+
+// Workers configures background jobs
+type Workers struct {
+	WaitlistReconcileInterval time.Duration `yaml:"waitlistReconcileInterval" env:"WAITLIST_RECONCILE_INTERVAL"`
+	TrashPurgeInterval        time.Duration `yaml:"trashPurgeInterval" env:"TRASH_PURGE_INTERVAL"`
+	// TrashRetention is how long deleted users and teams can be restored before they're purged
+	TrashRetention time.Duration `yaml:"trashRetention" env:"TRASH_RETENTION"`
+}
+
+// Log configures logging
+type Log struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// Metrics configures the Prometheus endpoint
+type Metrics struct {
+	// Token, when set, must be sent by scrapers as a bearer token
+	Token string `yaml:"token" env:"METRICS_TOKEN"`
+}
+
+// Tracing configures OpenTelemetry; the OTLP endpoint uses the standard
+// OTEL_EXPORTER_OTLP_* variables
+type Tracing struct {
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+}
+
+// Default returns the configuration used for values that aren't set
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Port:              8080,
+			ReadTimeout:       30 * time.Second,
+			ReadHeaderTimeout: 10 * time.Second,
+			WriteTimeout:      2 * time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			MaxHeaderBytes:    64 << 10,
+			ShutdownTimeout:   30 * time.Second,
+			VerifyRateLimit:   30,
+		},
+		Database: Database{
+			Name:            "pccoe_IGC",
+			VideoCollection: "videos",
+		},
+		Auth: Auth{
+			AdminTokenTTL:       24 * time.Hour,
+			ParticipantTokenTTL: 12 * time.Hour,
+			MagicLinkTTL:        15 * time.Minute,
+			AdminUsername:       "admin",
+		},
+		CORS: CORS{
+			AllowedOrigins: []string{"http://localhost:3000", "http://127.0.0.1:3000"},
+		},
+		Pagination: Pagination{
+			DefaultLimit: 10,
+			MaxLimit:     100,
+		},
+		Features: Features{
+			ParticipantPortal:  true,
+			PublicVerification: true,
+			WaitlistReconciler: true,
+			Metrics:            true,
+			TrashPurge:         true,
+		},
+		SMTP: SMTP{
+			Port: 587,
+		},
+		Portal: Portal{
+			URL: "http://localhost:3000/participant/login",
+		},
+		Workers: Workers{
+			WaitlistReconcileInterval: 5 * time.Minute,
+			TrashPurgeInterval:        time.Hour,
+			TrashRetention:            30 * 24 * time.Hour,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: Tracing{
+			Exporter: "none",
+		},
+	}
+}
+
+// Load builds the configuration. A .env file in the working directory is first loaded
+// into the environment (without overriding variables already set). Then the YAML file
+// named by CONFIG_FILE, if any, is applied over the defaults, and environment
+// variables over that. The returned error lists every missing or invalid value.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read .env: %w", err)
+	}
+
+	cfg := Default()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		// Unknown keys are refused so that a misspelt setting doesn't silently keep its default
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	problems := applyEnv(cfg)
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &Error{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// Addr is the address the HTTP server listens on
+func (c *Config) Addr() string {
+	return fmt.Sprintf(":%d", c.Server.Port)
+}
+
+// Error reports every problem found in the configuration
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, p := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// validate checks required values and ranges
+func (c *Config) validate() []string {
+	var problems []string
+	require := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	require(c.Server.Port > 0 && c.Server.Port < 65536, "PORT (server.port) must be between 1 and 65535, got %d", c.Server.Port)
+	require(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT (server.shutdownTimeout) must be positive")
+	require(c.Server.MaxHeaderBytes > 0, "HTTP_MAX_HEADER_BYTES (server.maxHeaderBytes) must be positive")
+	require(c.Server.VerifyRateLimit > 0, "VERIFY_RATE_LIMIT_PER_MINUTE (server.verifyRateLimitPerMinute) must be positive")
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		require(cidrErr == nil || net.ParseIP(proxy) != nil,
+			"TRUSTED_PROXIES (server.trustedProxies) entry %q must be an IP address or CIDR", proxy)
+	}
+
+	require(c.Database.URI != "", "MONGODB_URI (database.uri) is required")
+	require(c.Database.Name != "", "DATABASE_NAME (database.name) is required")
+	require(c.Database.VideoCollection != "", "VIDEO_COLLECTION_NAME (database.videoCollection) is required")
+
+	require(c.Auth.JWTSecret != "", "JWT_SECRET (auth.jwtSecret) is required")
+	require(c.Auth.JWTSecret == "" || len(c.Auth.JWTSecret) >= 16, "JWT_SECRET (auth.jwtSecret) must be at least 16 characters")
+	require(c.Auth.AdminTokenTTL > 0, "ADMIN_TOKEN_TTL (auth.adminTokenTTL) must be positive")
+	require(c.Auth.ParticipantTokenTTL > 0, "PARTICIPANT_TOKEN_TTL (auth.participantTokenTTL) must be positive")
+	require(c.Auth.MagicLinkTTL > 0, "MAGIC_LINK_TTL (auth.magicLinkTTL) must be positive")
+	require(c.Auth.AdminPassword == "" || len(c.Auth.AdminPassword) >= 6, "ADMIN_PASSWORD (auth.adminPassword) must be at least 6 characters")
+
+	require(len(c.CORS.AllowedOrigins) > 0, "CORS_ALLOWED_ORIGINS (cors.allowedOrigins) needs at least one origin")
+	for _, origin := range c.CORS.AllowedOrigins {
+		// Credentials are allowed, so wildcard origins aren't
+		require(strings.HasPrefix(origin, "http://") || strings.HasPrefix(origin, "https://"),
+			"CORS_ALLOWED_ORIGINS (cors.allowedOrigins) entry %q must start with http:// or https://", origin)
+	}
+
+	require(c.Pagination.DefaultLimit > 0, "PAGINATION_DEFAULT_LIMIT (pagination.defaultLimit) must be positive")
+	require(c.Pagination.MaxLimit >= c.Pagination.DefaultLimit, "PAGINATION_MAX_LIMIT (pagination.maxLimit) must be at least the default limit")
+
+	require(c.SMTP.Port > 0, "SMTP_PORT (smtp.port) must be positive")
+	require(c.SMTP.Host == "" || c.SMTP.From != "" || c.SMTP.Username != "", "SMTP_FROM (smtp.from) or SMTP_USERNAME is required when SMTP_HOST is set")
+	require(c.SMTP.Host == "" || c.SMTP.DevOutbox == "", "SMTP_DEV_OUTBOX (smtp.devOutbox) is only for development without SMTP_HOST")
+
+	require(c.Workers.WaitlistReconcileInterval > 0, "WAITLIST_RECONCILE_INTERVAL (workers.waitlistReconcileInterval) must be positive")
+	require(c.Workers.TrashPurgeInterval > 0, "TRASH_PURGE_INTERVAL (workers.trashPurgeInterval) must be positive")
+	require(c.Workers.TrashRetention > 0, "TRASH_RETENTION (workers.trashRetention) must be positive")
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL (log.level) must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "json", "text":
+	default:
+		problems = append(problems, fmt.Sprintf("LOG_FORMAT (log.format) must be json or text, got %q", c.Log.Format))
+	}
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case "none", "otlp", "stdout", "console":
+	default:
+		problems = append(problems, fmt.Sprintf("OTEL_TRACES_EXPORTER (tracing.exporter) must be otlp, stdout or none, got %q", c.Tracing.Exporter))
+	}
+	return problems
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolateEnv clears every configuration variable for the duration of the test, so
+// only the values the test sets apply
+func isolateEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	walkEnv(reflect.ValueOf(Default()).Elem(), func(_ reflect.Value, key string) {
+		t.Setenv(key, "")
+	})
+}
+
+// valid returns a configuration that passes validation
+func valid() *Config {
+	cfg := Default()
+	cfg.Database.URI = "mongodb://localhost:27017"
+	cfg.Auth.JWTSecret = "0123456789abcdef"
+	return cfg
+}
+
+func TestLoad(t *testing.T) {
+	required := map[string]string{
+		"MONGODB_URI": "mongodb://localhost:27017",
+		"JWT_SECRET":  "0123456789abcdef",
+	}
+	tests := []struct {
+		name string
+		env  map[string]string
+		yaml string
+		// check inspects the loaded configuration; nil when loading must fail
+		check    func(t *testing.T, cfg *Config)
+		problems []string
+	}{
+		{
+			name: "defaults",
+			env:  required,
+			check: func(t *testing.T, cfg *Config) {
+				want := valid()
+				if !reflect.DeepEqual(cfg, want) {
+					t.Errorf("Load = %+v, want %+v", cfg, want)
+				}
+				if len(cfg.Server.TrustedProxies) != 0 {
+					t.Errorf("trusted proxies = %q, want none", cfg.Server.TrustedProxies)
+				}
+			},
+		},
+		{
+			name: "environment overrides defaults",
+			env: map[string]string{
+				"PORT":                        "9090",
+				"WAITLIST_RECONCILE_INTERVAL": "1m",
+				"TRASH_RETENTION":             "48h",
+				"FEATURE_METRICS":             "false",
+				"FEATURE_TRASH_PURGE":         "false",
+				"CORS_ALLOWED_ORIGINS":        "https://a.example, ,https://b.example",
+				"TRUSTED_PROXIES":             "10.0.0.0/8, 192.0.2.10",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != 9090 {
+					t.Errorf("port = %d, want 9090", cfg.Server.Port)
+				}
+				if cfg.Workers.WaitlistReconcileInterval != time.Minute {
+					t.Errorf("reconcile interval = %v, want 1m", cfg.Workers.WaitlistReconcileInterval)
+				}
+				if cfg.Features.Metrics {
+					t.Error("metrics are enabled, want disabled")
+				}
+				if cfg.Workers.TrashRetention != 48*time.Hour {
+					t.Errorf("trash retention = %v, want 48h", cfg.Workers.TrashRetention)
+				}
+				if cfg.Features.TrashPurge {
+					t.Error("trash purge is enabled, want disabled")
+				}
+				if want := []string{"https://a.example", "https://b.example"}; !slices.Equal(cfg.CORS.AllowedOrigins, want) {
+					t.Errorf("allowed origins = %q, want %q", cfg.CORS.AllowedOrigins, want)
+				}
+				if want := []string{"10.0.0.0/8", "192.0.2.10"}; !slices.Equal(cfg.Server.TrustedProxies, want) {
+					t.Errorf("trusted proxies = %q, want %q", cfg.Server.TrustedProxies, want)
+				}
+			},
+		},
+		{
+			name: "file overrides defaults and environment overrides file",
+			env:  map[string]string{"DATABASE_NAME": "from-env"},
+			yaml: "server:\n  port: 7070\ndatabase:\n  name: from-file\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != 7070 {
+					t.Errorf("port = %d, want 7070", cfg.Server.Port)
+				}
+				if cfg.Database.Name != "from-env" {
+					t.Errorf("database name = %q, want from-env", cfg.Database.Name)
+				}
+			},
+		},
+		{
+			name: "unparsable values",
+			env:  map[string]string{"PORT": "eighty", "SHUTDOWN_TIMEOUT": "30", "FEATURE_METRICS": "maybe"},
+			problems: []string{
+				`PORT: invalid integer "eighty"`,
+				`SHUTDOWN_TIMEOUT: invalid duration "30" (use e.g. 30s, 15m, 24h)`,
+				`FEATURE_METRICS: invalid boolean "maybe" (use true or false)`,
+			},
+		},
+		{
+			name: "missing required values",
+			env:  map[string]string{"MONGODB_URI": "", "JWT_SECRET": ""},
+			problems: []string{
+				"MONGODB_URI (database.uri) is required",
+				"JWT_SECRET (auth.jwtSecret) is required",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t)
+			for key, value := range required {
+				t.Setenv(key, value)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if tt.yaml != "" {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				if err := os.WriteFile(path, []byte(tt.yaml), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				t.Setenv("CONFIG_FILE", path)
+			}
+
+			cfg, err := Load()
+			if tt.check != nil {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				tt.check(t, cfg)
+				return
+			}
+			var cfgErr *Error
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("Load error = %v, want a configuration error", err)
+			}
+			for _, p := range tt.problems {
+				if !slices.Contains(cfgErr.Problems, p) {
+					t.Errorf("problems %q don't include %q", cfgErr.Problems, p)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err := Load(); err == nil {
+		t.Error("Load with a missing config file returned no error")
+	}
+}
+
+func TestLoadUnknownKey(t *testing.T) {
+	isolateEnv(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  trustedProxy: [10.0.0.1]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "trustedProxy") {
+		t.Errorf("Load error = %v, want the unknown trustedProxy key reported", err)
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv("CONFIG_FILE", filepath.Join("..", "config.example.yaml"))
+	t.Setenv("MONGODB_URI", "mongodb://localhost:27017")
+	t.Setenv("JWT_SECRET", "0123456789abcdef")
+	if _, err := Load(); err != nil {
+		t.Errorf("Load config.example.yaml: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *Config)
+		want   []string
+	}{
+		{"valid", func(cfg *Config) {}, nil},
+		{
+			name:   "port out of range",
+			change: func(cfg *Config) { cfg.Server.Port = 70000 },
+			want:   []string{"PORT (server.port) must be between 1 and 65535, got 70000"},
+		},
+		{
+			name:   "short JWT secret",
+			change: func(cfg *Config) { cfg.Auth.JWTSecret = "short" },
+			want:   []string{"JWT_SECRET (auth.jwtSecret) must be at least 16 characters"},
+		},
+		{
+			name:   "short admin password",
+			change: func(cfg *Config) { cfg.Auth.AdminPassword = "12345" },
+			want:   []string{"ADMIN_PASSWORD (auth.adminPassword) must be at least 6 characters"},
+		},
+		{
+			name:   "wildcard origin",
+			change: func(cfg *Config) { cfg.CORS.AllowedOrigins = []string{"*"} },
+			want:   []string{`CORS_ALLOWED_ORIGINS (cors.allowedOrigins) entry "*" must start with http:// or https://`},
+		},
+		{
+			name:   "no origins",
+			change: func(cfg *Config) { cfg.CORS.AllowedOrigins = nil },
+			want:   []string{"CORS_ALLOWED_ORIGINS (cors.allowedOrigins) needs at least one origin"},
+		},
+		{
+			name:   "invalid trusted proxy",
+			change: func(cfg *Config) { cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "proxy.internal"} },
+			want:   []string{`TRUSTED_PROXIES (server.trustedProxies) entry "proxy.internal" must be an IP address or CIDR`},
+		},
+		{
+			name:   "max limit below default",
+			change: func(cfg *Config) { cfg.Pagination.MaxLimit = 5 },
+			want:   []string{"PAGINATION_MAX_LIMIT (pagination.maxLimit) must be at least the default limit"},
+		},
+		{
+			name:   "SMTP host without sender",
+			change: func(cfg *Config) { cfg.SMTP.Host = "smtp.example.org" },
+			want:   []string{"SMTP_FROM (smtp.from) or SMTP_USERNAME is required when SMTP_HOST is set"},
+		},
+		{
+			name: "dev outbox with SMTP host",
+			change: func(cfg *Config) {
+				cfg.SMTP.Host, cfg.SMTP.From, cfg.SMTP.DevOutbox = "smtp.example.org", "igc@example.org", "outbox.txt"
+			},
+			want: []string{"SMTP_DEV_OUTBOX (smtp.devOutbox) is only for development without SMTP_HOST"},
+		},
+		{
+			name:   "non-positive worker durations",
+			change: func(cfg *Config) { cfg.Workers.WaitlistReconcileInterval = -time.Minute },
+			want:   []string{"WAITLIST_RECONCILE_INTERVAL (workers.waitlistReconcileInterval) must be positive"},
+		},
+		{
+			name:   "non-positive trash durations",
+			change: func(cfg *Config) { cfg.Workers.TrashPurgeInterval = 0; cfg.Workers.TrashRetention = -time.Hour },
+			want: []string{
+				"TRASH_PURGE_INTERVAL (workers.trashPurgeInterval) must be positive",
+				"TRASH_RETENTION (workers.trashRetention) must be positive",
+			},
+		},
+		{
+			name:   "unknown log level",
+			change: func(cfg *Config) { cfg.Log.Level = "verbose" },
+			want:   []string{`LOG_LEVEL (log.level) must be debug, info, warn or error, got "verbose"`},
+		},
+		{
+			name:   "case-insensitive enums",
+			change: func(cfg *Config) { cfg.Log.Level = "DEBUG"; cfg.Log.Format = "Text"; cfg.Tracing.Exporter = "OTLP" },
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.change(cfg)
+			if got := cfg.validate(); !slices.Equal(got, tt.want) {
+				t.Errorf("validate = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv overrides fields with the non-empty environment variables named by their
+// env tags and returns a problem for each value that can't be parsed
+func applyEnv(cfg *Config) []string {
+	var problems []string
+	walkEnv(reflect.ValueOf(cfg).Elem(), func(field reflect.Value, key string) {
+		value := strings.TrimSpace(os.Getenv(key))
+		if value == "" {
+			return
+		}
+		if err := setField(field, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+		}
+	})
+	return problems
+}
+
+// walkEnv calls fn for every field with an env tag, descending into nested structs
+func walkEnv(v reflect.Value, fn func(field reflect.Value, key string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if key := t.Field(i).Tag.Get("env"); key != "" {
+			fn(field, key)
+		} else if field.Kind() == reflect.Struct {
+			walkEnv(field, fn)
+		}
+	}
+}
+
+// setField parses value into field according to the field's type
+func setField(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (use e.g. 30s, 15m, 24h)", value)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q (use true or false)", value)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditHandler serves the audit log of administrative and judging actions
+type AuditHandler struct {
+	DB *models.DatabaseService
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(db *models.DatabaseService) *AuditHandler {
+	return &AuditHandler{DB: db}
+}
+
+// AuditQuery filters the audit log
+type AuditQuery struct {
+	EventID    string `form:"eventId" json:"eventId" binding:"omitempty,mongodb"`
+	ActorID    string `form:"actorId" json:"actorId" binding:"max=100"`
+	Actor      string `form:"actor" json:"actor" binding:"max=100"`
+	Action     string `form:"action" json:"action" binding:"max=100"`
+	TargetType string `form:"targetType" json:"targetType" binding:"max=100"`
+	TargetID   string `form:"targetId" json:"targetId" binding:"max=100"`
+	From       string `form:"from" json:"from" binding:"omitempty,datetime=2006-01-02"`
+	To         string `form:"to" json:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// auditFilter reads the audit log filters of the request; both dates are inclusive
+// days in UTC
+func auditFilter(c *gin.Context) (*models.AuditFilter, error) {
+	var q AuditQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		return nil, invalidRequest(err)
+	}
+	f := &models.AuditFilter{
+		ActorID:    q.ActorID,
+		Actor:      q.Actor,
+		Action:     models.AuditAction(q.Action),
+		TargetType: q.TargetType,
+		TargetID:   q.TargetID,
+	}
+	f.EventID, _ = primitive.ObjectIDFromHex(q.EventID)
+	if q.From != "" {
+		f.From, _ = time.Parse(time.DateOnly, q.From)
+	}
+	if q.To != "" {
+		to, _ := time.Parse(time.DateOnly, q.To)
+		f.To = to.AddDate(0, 0, 1)
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return nil, invalidField("to", models.FieldOutOfRange, "Must not be before from")
+	}
+	return f, nil
+}
+
+// recordAudit appends the change the request made to target to the audit log. before
+// and after are the target's state around the change; before is nil for created
+// records and after for deleted ones. The change has already been made, so failing to
+// record it is logged rather than returned.
+func recordAudit(c *gin.Context, db *models.DatabaseService, action models.AuditAction, targetType, targetID string, before, after interface{}) {
+	ctx := c.Request.Context()
+	changes, err := models.AuditDiff(before, after)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to compare audited record", "error", err, "action", action, "target_id", targetID)
+	}
+
+	entry := &models.AuditEntry{
+		Actor:     auditActor(c),
+		Action:    action,
+		Target:    models.AuditTarget{Type: targetType, ID: targetID},
+		Changes:   changes,
+		IP:        c.ClientIP(),
+		RequestID: c.GetString("request_id"),
+	}
+	if event, ok := c.Get(eventContextKey); ok {
+		if event, ok := event.(*models.Event); ok {
+			entry.EventID = event.ID
+		}
+	}
+	if err := db.RecordAudit(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "failed to record audit entry", "error", err, "action", action, "target_id", targetID)
+	}
+}
+
+// auditSnapshot captures the state of a record that is about to be modified in place
+func auditSnapshot(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return json.RawMessage(data)
+}
+
+// auditActor identifies the authenticated user of the request from their token
+func auditActor(c *gin.Context) models.AuditActor {
+	actor := models.AuditActor{
+		Username: c.GetString("username"),
+		Role:     c.GetString("role"),
+		ID:       c.GetString("user_id"),
+	}
+	if actor.ID == "" {
+		actor.ID = c.GetString("team_id")
+	}
+	return actor
+}
+
+// actorName is the username of the authenticated user, recorded on the records they change
+func actorName(c *gin.Context) string {
+	return c.GetString("username")
+}
+
+// GetAuditLog lists audit log entries
+// @Summary List audit log
+// @Description List the recorded administrative and judging actions, newest first by default (admin only)
+// @Tags audit
+// @Produce json
+// @Param eventId query string false "Filter by event ID"
+// @Param actorId query string false "Filter by actor user ID (team ID for participants)"
+// @Param actor query string false "Filter by actor username"
+// @Param action query string false "Filter by action, e.g. team.approve"
+// @Param targetType query string false "Filter by target type (user/team/changeRequest/event/catalog/certificate)"
+// @Param targetId query string false "Filter by target ID"
+// @Param from query string false "First day, YYYY-MM-DD (UTC)"
+// @Param to query string false "Last day, YYYY-MM-DD (UTC)"
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: -createdAt)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Success 200 {object} AuditLogResponse
+// @Failure 400 {object} Problem "Invalid filter or paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/audit-log/ [get]
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	filter, err := auditFilter(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	q, err := listQuery(c, models.AuditSortFields, "-createdAt")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, err := h.DB.ListAuditLog(c.Request.Context(), filter, q)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve audit log: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, AuditLogResponse{
+		Entries:    page.Items,
+		Pagination: pagination(q, page),
+	})
+}
+
+// ExportAuditLog downloads the matching audit log entries as CSV
+// @Summary Export audit log
+// @Description Download the audit log entries matching the filters as CSV, oldest first, one row per entry with the changes as JSON (admin only)
+// @Tags audit
+// @Produce text/csv
+// @Param eventId query string false "Filter by event ID"
+// @Param actorId query string false "Filter by actor user ID (team ID for participants)"
+// @Param actor query string false "Filter by actor username"
+// @Param action query string false "Filter by action, e.g. team.approve"
+// @Param targetType query string false "Filter by target type (user/team/changeRequest/event/catalog/certificate)"
+// @Param targetId query string false "Filter by target ID"
+// @Param from query string false "First day, YYYY-MM-DD (UTC)"
+// @Param to query string false "Last day, YYYY-MM-DD (UTC)"
+// @Success 200 {file} file "CSV of audit log entries"
+// @Failure 400 {object} Problem "Invalid filter"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/audit-log/export [get]
+func (h *AuditHandler) ExportAuditLog(c *gin.Context) {
+	filter, err := auditFilter(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"time", "eventId", "actorId", "actor", "role", "action", "targetType", "targetId", "ip", "requestId", "changes"})
+	err = h.DB.ExportAuditLog(c.Request.Context(), filter, func(e *models.AuditEntry) error {
+		changes := ""
+		if len(e.Changes) > 0 {
+			data, err := json.Marshal(e.Changes)
+			if err != nil {
+				return err
+			}
+			changes = string(data)
+		}
+		eventID := ""
+		if !e.EventID.IsZero() {
+			eventID = e.EventID.Hex()
+		}
+		return w.Write([]string{
+			e.CreatedAt.UTC().Format(time.RFC3339),
+			eventID,
+			e.Actor.ID,
+			e.Actor.Username,
+			e.Actor.Role,
+			string(e.Action),
+			e.Target.Type,
+			e.Target.ID,
+			e.IP,
+			e.RequestID,
+			changes,
+		})
+	})
+	if err == nil {
+		w.Flush()
+		err = w.Error()
+	}
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to export audit log: %w", err))
+		return
+	}
+
+	name := fmt.Sprintf("audit-log-%s.csv", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// asAdmin authenticates the request as an admin with a request ID
+func asAdmin(c *gin.Context) {
+	c.Set("user_id", "64b000000000000000000001")
+	c.Set("username", "admin")
+	c.Set("role", "admin")
+	c.Set("request_id", "req-1")
+}
+
+// auditEntries returns the entries the handler appended to the audit log
+func auditEntries(t *testing.T, mt *mtest.T) []models.AuditEntry {
+	t.Helper()
+	var entries []models.AuditEntry
+	for _, started := range mt.GetAllStartedEvents() {
+		if started.CommandName != "insert" || started.Command.Lookup("insert").StringValue() != "audit_log" {
+			continue
+		}
+		docs, err := started.Command.Lookup("documents").Array().Values()
+		if err != nil {
+			t.Fatalf("read inserted documents: %v", err)
+		}
+		for _, d := range docs {
+			var entry models.AuditEntry
+			if err := bson.Unmarshal(d.Document(), &entry); err != nil {
+				t.Fatalf("decode audit entry: %v", err)
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// change finds the recorded change of a field
+func change(entry models.AuditEntry, field string) (models.AuditChange, bool) {
+	for _, ch := range entry.Changes {
+		if ch.Field == field {
+			return ch, true
+		}
+	}
+	return models.AuditChange{}, false
+}
+
+func TestHandlersRecordAudit(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	userID := primitive.NewObjectID()
+	user := models.NewUser("judge1", "secret")
+	user.ID = userID
+	renamed := *user
+	renamed.Username = "judge2"
+	renamed.Version = 1
+	deletedAt := time.Now()
+	trashed := *user
+	trashed.DeletedAt = &deletedAt
+	trashed.DeletedBy = "admin"
+
+	event := models.NewEvent("igc-2026", "IGC 2026")
+	event.ID = primitive.NewObjectID()
+	team := models.NewTeamRegistration()
+	team.ID = primitive.NewObjectID()
+	team.EventID = event.ID
+	team.TeamName = "Rockets"
+	rejected := *team
+	rejected.RegistrationStatus = models.StatusRejected
+	rejected.RejectionReason = "Incomplete"
+	rejected.Version = 1
+
+	track := models.NewCatalogEntry(models.CatalogTrack, "", "Robotics")
+	track.ID = primitive.NewObjectID()
+
+	updated := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1})
+	deleted := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1})
+	none := func(collection string) bson.D {
+		return mtest.CreateCursorResponse(0, ns(collection), mtest.FirstBatch)
+	}
+	one := func(collection string, v interface{}) bson.D {
+		return mtest.CreateCursorResponse(0, ns(collection), mtest.FirstBatch, doc(mt, v))
+	}
+	count := func(collection string, n int) bson.D {
+		return mtest.CreateCursorResponse(0, ns(collection), mtest.FirstBatch, bson.D{{Key: "n", Value: n}})
+	}
+
+	tests := []struct {
+		name       string
+		handler    func(db *models.DatabaseService) gin.HandlerFunc
+		method     string
+		path       string
+		body       interface{}
+		setup      func(c *gin.Context)
+		responses  []bson.D
+		status     int
+		action     models.AuditAction
+		targetType string
+		targetID   string
+		eventID    primitive.ObjectID
+		check      func(t *testing.T, entry models.AuditEntry)
+	}{
+		{
+			name:    "create user",
+			method:  http.MethodPost,
+			path:    "/api/v1/users/",
+			body:    map[string]string{"username": "admin2", "password": "secret123", "role": "admin"},
+			handler: func(db *models.DatabaseService) gin.HandlerFunc { return NewUserHandler(db).CreateUser },
+			responses: []bson.D{
+				none("users"),
+				mtest.CreateSuccessResponse(),
+			},
+			status:     http.StatusCreated,
+			action:     models.AuditUserCreate,
+			targetType: models.AuditTargetUser,
+			check: func(t *testing.T, entry models.AuditEntry) {
+				if ch, ok := change(entry, "username"); !ok || ch.Before != "" || ch.After != `"admin2"` {
+					t.Errorf("username change = %+v, want created as admin2", ch)
+				}
+				if ch, ok := change(entry, "password"); !ok || !ch.Redacted || ch.After != "" {
+					t.Errorf("password change = %+v, want redacted", ch)
+				}
+			},
+		},
+		{
+			name:    "update user",
+			method:  http.MethodPut,
+			path:    "/api/v1/users/" + userID.Hex(),
+			body:    map[string]string{"username": "judge2"},
+			setup:   func(c *gin.Context) { c.Params = gin.Params{{Key: "id", Value: userID.Hex()}} },
+			handler: func(db *models.DatabaseService) gin.HandlerFunc { return NewUserHandler(db).UpdateUser },
+			responses: []bson.D{
+				one("users", user),
+				none("users"),
+				mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(mt, renamed)}),
+			},
+			status:     http.StatusOK,
+			action:     models.AuditUserUpdate,
+			targetType: models.AuditTargetUser,
+			targetID:   userID.Hex(),
+			check: func(t *testing.T, entry models.AuditEntry) {
+				if len(entry.Changes) != 1 {
+					t.Fatalf("changes = %+v, want only the username", entry.Changes)
+				}
+				if ch := entry.Changes[0]; ch.Field != "username" || ch.Before != `"judge1"` || ch.After != `"judge2"` {
+					t.Errorf("change = %+v, want username judge1 -> judge2", ch)
+				}
+			},
+		},
+		{
+			name:    "delete user",
+			method:  http.MethodDelete,
+			path:    "/api/v1/users/" + userID.Hex(),
+			setup:   func(c *gin.Context) { c.Params = gin.Params{{Key: "id", Value: userID.Hex()}} },
+			handler: func(db *models.DatabaseService) gin.HandlerFunc { return NewUserHandler(db).DeleteUser },
+			responses: []bson.D{
+				one("users", user),
+				mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(mt, trashed)}),
+			},
+			status:     http.StatusOK,
+			action:     models.AuditUserDelete,
+			targetType: models.AuditTargetUser,
+			targetID:   userID.Hex(),
+			check: func(t *testing.T, entry models.AuditEntry) {
+				if ch, ok := change(entry, "deletedBy"); !ok || ch.Before != "" || ch.After != `"admin"` {
+					t.Errorf("deletedBy change = %+v, want moved to the trash by admin", ch)
+				}
+				if _, ok := change(entry, "username"); ok {
+					t.Error("username recorded as changed by a soft delete")
+				}
+			},
+		},
+		{
+			name:    "restore user",
+			method:  http.MethodPost,
+			path:    "/api/v1/trash/users/" + userID.Hex() + "/restore",
+			setup:   func(c *gin.Context) { c.Params = gin.Params{{Key: "id", Value: userID.Hex()}} },
+			handler: func(db *models.DatabaseService) gin.HandlerFunc { return NewTrashHandler(db).RestoreUser },
+			responses: []bson.D{
+				one("users", trashed),
+				one("users", trashed),
+				none("users"),
+				updated,
+				one("users", user),
+			},
+			status:     http.StatusOK,
+			action:     models.AuditUserRestore,
+			targetType: models.AuditTargetUser,
+			targetID:   userID.Hex(),
+			check: func(t *testing.T, entry models.AuditEntry) {
+				if ch, ok := change(entry, "deletedBy"); !ok || ch.Before != `"admin"` || ch.After != "" {
+					t.Errorf("deletedBy change = %+v, want taken out of the trash", ch)
+				}
+			},
+		},
+		{
+			name:   "reject team",
+			method: http.MethodPut,
+			path:   "/api/v1/team-registrations/" + team.ID.Hex() + "/action",
+			body:   map[string]string{"action": "reject", "reason": "Incomplete"},
+			setup: func(c *gin.Context) {
+				c.Params = gin.Params{{Key: "id", Value: team.ID.Hex()}}
+				inEvent(event)(c)
+			},
+			handler: func(db *models.DatabaseService) gin.HandlerFunc {
+				return NewTeamRegistrationHandler(db).ApproveOrRejectTeamRegistration
+			},
+			responses: []bson.D{
+				one("teamregistrations", team),
+				one("teamregistrations", team),
+				mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(mt, rejected)}),
+			},
+			status:     http.StatusOK,
+			action:     models.AuditTeamReject,
+			targetType: models.AuditTargetTeam,
+			targetID:   team.ID.Hex(),
+			eventID:    event.ID,
+			check: func(t *testing.T, entry models.AuditEntry) {
+				if ch, ok := change(entry, "registrationStatus"); !ok || ch.Before != `"pending"` || ch.After != `"rejected"` {
+					t.Errorf("status change = %+v, want pending -> rejected", ch)
+				}
+			},
+		},
+		{
+			name:   "create track",
+			method: http.MethodPost,
+			path:   "/api/v1/tracks/",
+			body:   map[string]string{"name": "Robotics"},
+			handler: func(db *models.DatabaseService) gin.HandlerFunc {
+				return NewCatalogHandler(db, models.CatalogTrack).CreateEntry
+			},
+			responses: []bson.D{
+				count("catalog", 0),
+				mtest.CreateSuccessResponse(),
+				updated,
+			},
+			status:     http.StatusCreated,
+			action:     models.AuditCatalogCreate,
+			targetType: models.AuditTargetCatalog,
+			check: func(t *testing.T, entry models.AuditEntry) {
+				if ch, ok := change(entry, "name"); !ok || ch.After != `"Robotics"` {
+					t.Errorf("name change = %+v, want created as Robotics", ch)
+				}
+			},
+		},
+		{
+			name:   "delete track",
+			method: http.MethodDelete,
+			path:   "/api/v1/tracks/robotics",
+			setup:  func(c *gin.Context) { c.Params = gin.Params{{Key: "slug", Value: "robotics"}} },
+			handler: func(db *models.DatabaseService) gin.HandlerFunc {
+				return NewCatalogHandler(db, models.CatalogTrack).DeleteEntry
+			},
+			responses: []bson.D{
+				one("catalog", track),
+				one("catalog", track),
+				count("teamregistrations", 0),
+				deleted,
+			},
+			status:     http.StatusOK,
+			action:     models.AuditCatalogDelete,
+			targetType: models.AuditTargetCatalog,
+			targetID:   track.ID.Hex(),
+		},
+		{
+			name:    "activate event",
+			method:  http.MethodPost,
+			path:    "/api/v1/events/igc-2026/activate",
+			setup:   func(c *gin.Context) { c.Params = gin.Params{{Key: "event", Value: "igc-2026"}} },
+			handler: func(db *models.DatabaseService) gin.HandlerFunc { return NewEventHandler(db).ActivateEvent },
+			responses: []bson.D{
+				one("events", event),
+				updated,
+				updated,
+			},
+			status:     http.StatusOK,
+			action:     models.AuditEventActivate,
+			targetType: models.AuditTargetEvent,
+			targetID:   event.ID.Hex(),
+			check: func(t *testing.T, entry models.AuditEntry) {
+				if ch, ok := change(entry, "active"); !ok || ch.Before != "false" || ch.After != "true" {
+					t.Errorf("active change = %+v, want false -> true", ch)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			t := mt.T
+			db := newTestDB(mt)
+			mt.AddMockResponses(append(tt.responses, mtest.CreateSuccessResponse())...)
+
+			w := serve(tt.handler(db), tt.method, tt.path, tt.body, func(c *gin.Context) {
+				asAdmin(c)
+				if tt.setup != nil {
+					tt.setup(c)
+				}
+			})
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+
+			entries := auditEntries(t, mt)
+			if len(entries) != 1 {
+				t.Fatalf("recorded %d audit entries, want 1", len(entries))
+			}
+			entry := entries[0]
+			if entry.Action != tt.action {
+				t.Errorf("action = %q, want %q", entry.Action, tt.action)
+			}
+			if entry.Target.Type != tt.targetType {
+				t.Errorf("target type = %q, want %q", entry.Target.Type, tt.targetType)
+			}
+			if tt.targetID != "" && entry.Target.ID != tt.targetID {
+				t.Errorf("target ID = %q, want %q", entry.Target.ID, tt.targetID)
+			}
+			if entry.Target.ID == "" {
+				t.Error("target ID is empty")
+			}
+			want := models.AuditActor{ID: "64b000000000000000000001", Username: "admin", Role: "admin"}
+			if entry.Actor != want {
+				t.Errorf("actor = %+v, want %+v", entry.Actor, want)
+			}
+			if entry.RequestID != "req-1" {
+				t.Errorf("request ID = %q, want req-1", entry.RequestID)
+			}
+			if entry.IP == "" {
+				t.Error("IP is empty")
+			}
+			if entry.EventID != tt.eventID {
+				t.Errorf("event ID = %s, want %s", entry.EventID.Hex(), tt.eventID.Hex())
+			}
+			if entry.CreatedAt.IsZero() {
+				t.Error("createdAt is not set")
+			}
+			if tt.check != nil {
+				tt.check(t, entry)
+			}
+		})
+	}
+}
+
+func TestRecordAuditClientIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		want    string
+	}{
+		{name: "spoofed header from an untrusted peer", proxies: nil, want: "192.0.2.1"},
+		{name: "header from a trusted proxy", proxies: []string{"192.0.2.0/24"}, want: "203.0.113.9"},
+	}
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			db := newTestDB(mt)
+			mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+			router := gin.New()
+			if err := router.SetTrustedProxies(tt.proxies); err != nil {
+				mt.Fatalf("SetTrustedProxies: %v", err)
+			}
+			router.POST("/", func(c *gin.Context) {
+				recordAudit(c, db, models.AuditUserCreate, models.AuditTargetUser, "u1", nil, map[string]string{"username": "judge1"})
+			})
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.RemoteAddr = "192.0.2.1:4321"
+			req.Header.Set("X-Forwarded-For", "203.0.113.9")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			entries := auditEntries(mt.T, mt)
+			if len(entries) != 1 {
+				mt.Fatalf("recorded %d audit entries, want 1", len(entries))
+			}
+			if entries[0].IP != tt.want {
+				mt.Errorf("IP = %q, want %q", entries[0].IP, tt.want)
+			}
+		})
+	}
+}
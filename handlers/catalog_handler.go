@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogHandler handles the admin-managed catalogue of tracks or programs
+type CatalogHandler struct {
+	DB   *models.DatabaseService
+	Kind models.CatalogKind
+}
+
+// NewCatalogHandler creates a new CatalogHandler for one kind of catalogue entry
+func NewCatalogHandler(db *models.DatabaseService, kind models.CatalogKind) *CatalogHandler {
+	return &CatalogHandler{DB: db, Kind: kind}
+}
+
+// CatalogEntryRequest represents the track/program create and update payload
+type CatalogEntryRequest struct {
+	Slug        string `json:"slug,omitempty" binding:"max=100"`
+	Name        string `json:"name" binding:"required,max=200"`
+	Description string `json:"description,omitempty" binding:"max=2000"`
+	Active      *bool  `json:"active,omitempty"`
+	Capacity    int    `json:"capacity" binding:"min=0"`
+}
+
+// resolveCatalogName maps a submitted track or program (name, slug or previous name)
+// to its current catalogue name. Only active entries are accepted.
+func resolveCatalogName(ctx context.Context, db *models.DatabaseService, kind models.CatalogKind, value string) (string, error) {
+	entry, err := db.FindCatalogEntry(ctx, kind, value)
+	if errors.Is(err, models.ErrNotFound) {
+		return "", invalidField(string(kind), models.FieldInvalidChoice, fmt.Sprintf("Unknown %s %q", kind, value))
+	} else if err != nil {
+		return "", fmt.Errorf("failed to look up %s: %w", kind, err)
+	}
+	if !entry.Active {
+		return "", invalidField(string(kind), models.FieldInvalidChoice, fmt.Sprintf("The %s %q is no longer offered", kind, entry.Name))
+	}
+	return entry.Name, nil
+}
+
+// GetEntries lists the catalogue
+// @Summary List tracks or programs
+// @Description List the active tracks or programs (under "tracks" or "programs"); admins can include inactive ones
+// @Tags catalog
+// @Produce json
+// @Param includeInactive query bool false "Include inactive entries"
+// @Success 200 {object} CatalogListResponse
+// @Failure 500 {object} Problem
+// @Router /api/v1/tracks/ [get]
+// @Router /api/v1/programs/ [get]
+func (h *CatalogHandler) GetEntries(c *gin.Context) {
+	entries, err := h.DB.GetCatalogEntries(c.Request.Context(), h.Kind, c.Query("includeInactive") == "true")
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve %ss: %w", h.Kind, err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		string(h.Kind) + "s": entries,
+	})
+}
+
+// GetEntry retrieves a track or program by slug
+// @Summary Get track or program
+// @Description Get a track or program by slug (under "track" or "program")
+// @Tags catalog
+// @Produce json
+// @Param slug path string true "Slug"
+// @Success 200 {object} CatalogEntryEnvelope
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Router /api/v1/tracks/{slug} [get]
+// @Router /api/v1/programs/{slug} [get]
+func (h *CatalogHandler) GetEntry(c *gin.Context) {
+	entry, ok := h.loadEntry(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		string(h.Kind): entry,
+	})
+}
+
+// CreateEntry adds a track or program to the catalogue
+// @Summary Create track or program
+// @Description Add a track or program (admin only). The slug defaults to one derived from the name.
+// @Tags catalog
+// @Accept json
+// @Produce json
+// @Param entry body CatalogEntryRequest true "Entry data"
+// @Success 201 {object} CatalogEntryEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 409 {object} Problem "Slug or name already in use"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/tracks/ [post]
+// @Router /api/v1/programs/ [post]
+func (h *CatalogHandler) CreateEntry(c *gin.Context) {
+	var req CatalogEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	entry := models.NewCatalogEntry(h.Kind, strings.ToLower(strings.TrimSpace(req.Slug)), req.Name)
+	entry.Description = req.Description
+	entry.Capacity = req.Capacity
+	if req.Active != nil {
+		entry.Active = *req.Active
+	}
+	if err := entry.Validate(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	created, err := h.DB.CreateCatalogEntry(c.Request.Context(), entry)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to create %s: %w", h.Kind, err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditCatalogCreate, models.AuditTargetCatalog, created.ID.Hex(), nil, created)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      h.label() + " created successfully",
+		string(h.Kind): created,
+	})
+}
+
+// UpdateEntry updates a track or program. Renaming migrates existing team registrations.
+// @Summary Update track or program
+// @Description Update a track or program (admin only). Renaming moves the team registrations and
+// @Description events using the old name to the new one, except in read-only past events. Issued
+// @Description certificates keep the name they were issued with.
+// @Tags catalog
+// @Accept json
+// @Produce json
+// @Param slug path string true "Slug"
+// @Param entry body CatalogEntryRequest true "Entry data"
+// @Success 200 {object} CatalogEntryEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem "Slug or name already in use"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/tracks/{slug} [put]
+// @Router /api/v1/programs/{slug} [put]
+func (h *CatalogHandler) UpdateEntry(c *gin.Context) {
+	entry, ok := h.loadEntry(c)
+	if !ok {
+		return
+	}
+	before := auditSnapshot(entry)
+
+	var req CatalogEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	if slug := strings.ToLower(strings.TrimSpace(req.Slug)); slug != "" {
+		entry.Slug = slug
+	}
+	entry.Rename(req.Name)
+	entry.Description = req.Description
+	entry.Capacity = req.Capacity
+	if req.Active != nil {
+		entry.Active = *req.Active
+	}
+	if err := entry.Validate(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := h.DB.UpdateCatalogEntry(c.Request.Context(), entry)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to update %s: %w", h.Kind, err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditCatalogUpdate, models.AuditTargetCatalog, updated.ID.Hex(), before, updated)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      h.label() + " updated successfully",
+		string(h.Kind): updated,
+	})
+}
+
+// DeleteEntry removes an unused track or program
+// @Summary Delete track or program
+// @Description Delete a track or program no team registered for (admin only); deactivate used ones instead
+// @Tags catalog
+// @Produce json
+// @Param slug path string true "Slug"
+// @Success 200 {object} MessageResponse
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem "Entry is in use"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/tracks/{slug} [delete]
+// @Router /api/v1/programs/{slug} [delete]
+func (h *CatalogHandler) DeleteEntry(c *gin.Context) {
+	entry, ok := h.loadEntry(c)
+	if !ok {
+		return
+	}
+
+	err := h.DB.DeleteCatalogEntry(c.Request.Context(), h.Kind, entry.Slug)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to delete %s: %w", h.Kind, err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditCatalogDelete, models.AuditTargetCatalog, entry.ID.Hex(), entry, nil)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": h.label() + " deleted successfully",
+	})
+}
+
+// label returns the capitalised entry kind for messages
+func (h *CatalogHandler) label() string {
+	if h.Kind == models.CatalogProgram {
+		return "Program"
+	}
+	return "Track"
+}
+
+// loadEntry loads the entry named by the :slug path parameter
+func (h *CatalogHandler) loadEntry(c *gin.Context) (*models.CatalogEntry, bool) {
+	entry, err := h.DB.GetCatalogEntry(c.Request.Context(), h.Kind, c.Param("slug"))
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve %s: %w", h.Kind, err))
+		return nil, false
+	}
+	return entry, true
+}
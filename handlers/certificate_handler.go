@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/certificates"
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CertificateHandler handles certificate generation and download
+type CertificateHandler struct {
+	DB       *models.DatabaseService
+	Renderer *certificates.Renderer
+}
+
+// NewCertificateHandler creates a new CertificateHandler
+func NewCertificateHandler(db *models.DatabaseService, renderer *certificates.Renderer) *CertificateHandler {
+	return &CertificateHandler{DB: db, Renderer: renderer}
+}
+
+// BulkCertificateRequest represents the bulk certificate generation payload.
+// Teams are selected by registration status (the stage result) and optionally by track or IDs.
+type BulkCertificateRequest struct {
+	Type    string   `json:"type" binding:"required,oneof=participation winner"`
+	Award   string   `json:"award,omitempty" binding:"max=100"`
+	Status  string   `json:"status,omitempty" binding:"omitempty,oneof=pending approved rejected"`
+	Track   string   `json:"track,omitempty"`
+	TeamIDs []string `json:"teamIds,omitempty"`
+}
+
+// GenerateCertificates issues certificates for the leader and every member of the selected teams
+// @Summary Bulk generate certificates
+// @Description Issue participation or winner certificates for all teams of a stage result (admin only). Recipients already holding a certificate of the same type and award are skipped.
+// @Tags certificates
+// @Accept json
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param request body BulkCertificateRequest true "Selection"
+// @Success 201 {object} BulkCertificateResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 409 {object} Problem "Event is read-only"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/certificates/bulk [post]
+func (h *CertificateHandler) GenerateCertificates(c *gin.Context) {
+	var req BulkCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+	certType := models.CertificateType(req.Type)
+	award := strings.TrimSpace(req.Award)
+	if certType == models.CertificateWinner && award == "" {
+		respondError(c, badRequest("Award is required for winner certificates"))
+		return
+	}
+
+	filter := bson.M{"eventId": currentEvent(c).ID}
+	if req.Status != "" {
+		filter["registrationStatus"] = req.Status
+	} else {
+		filter["registrationStatus"] = models.StatusApproved
+	}
+	if req.Track != "" {
+		filter["track"] = req.Track
+	}
+	if len(req.TeamIDs) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(req.TeamIDs))
+		for _, id := range req.TeamIDs {
+			oid, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				respondError(c, models.ErrInvalidTeamID.With("id", id))
+				return
+			}
+			ids = append(ids, oid)
+		}
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
+	teams, err := h.DB.GetAllTeamRegistrations(c.Request.Context(), 0, 0, filter)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve team registrations: %w", err))
+		return
+	}
+
+	issuedBy, _ := c.Get("username")
+	issuedByName, _ := issuedBy.(string)
+
+	created := make([]*models.Certificate, 0)
+	existing := 0
+	for _, team := range teams {
+		for _, r := range certificateRecipients(team) {
+			name := strings.TrimSpace(r.name)
+			found, err := h.DB.FindCertificate(c.Request.Context(), team.ID, certType, name, award)
+			if err != nil {
+				respondError(c, fmt.Errorf("failed to check existing certificates: %w", err))
+				return
+			}
+			if found != nil {
+				existing++
+				continue
+			}
+
+			cert := models.NewCertificate(team, certType, name, r.role, award)
+			cert.IssuedBy = issuedByName
+			if cert, err = h.issue(c.Request.Context(), cert); err != nil {
+				respondError(c, fmt.Errorf("failed to create certificate: %w", err))
+				return
+			}
+			recordAudit(c, h.DB, models.AuditCertificateGenerate, models.AuditTargetCertificate, cert.ID.Hex(), nil, cert)
+			created = append(created, cert)
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      fmt.Sprintf("%d certificates generated", len(created)),
+		"teams":        len(teams),
+		"created":      len(created),
+		"existing":     existing,
+		"certificates": created,
+	})
+}
+
+// certificateRecipient is a person who receives a certificate for a team
+type certificateRecipient struct {
+	name string
+	role models.RecipientRole
+}
+
+// certificateRecipients returns the leader followed by every named member
+func certificateRecipients(team *models.TeamRegistration) []certificateRecipient {
+	recipients := []certificateRecipient{{team.LeaderName, models.RecipientLeader}}
+	for _, m := range team.Members {
+		if strings.TrimSpace(m.FullName) != "" {
+			recipients = append(recipients, certificateRecipient{m.FullName, models.RecipientMember})
+		}
+	}
+	return recipients
+}
+
+// issue assigns a unique verification code and stores the certificate
+func (h *CertificateHandler) issue(ctx context.Context, cert *models.Certificate) (*models.Certificate, error) {
+	// Codes are random; retry on the unlikely collision with the unique index
+	var lastErr error
+	for attempt := 0; attempt < 5; attempt++ {
+		code, err := certificates.NewCode()
+		if err != nil {
+			return nil, err
+		}
+		cert.Code = code
+		created, err := h.DB.CreateCertificate(ctx, cert)
+		if err == nil {
+			return created, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// GetCertificates lists issued certificates
+// @Summary List certificates
+// @Description List issued certificates filtered by team, type, track or award (admin only)
+// @Tags certificates
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param teamId query string false "Team registration ID"
+// @Param type query string false "Certificate type (participation/winner)"
+// @Param track query string false "Track"
+// @Param award query string false "Award"
+// @Success 200 {object} CertificateListResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/certificates/ [get]
+func (h *CertificateHandler) GetCertificates(c *gin.Context) {
+	filter, err := certificateFilter(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	certs, err := h.DB.GetCertificates(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve certificates: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"certificates": certs,
+		"total":        len(certs),
+	})
+}
+
+// DownloadCertificatesZIP sends a ZIP archive with the PDFs of all matching certificates.
+// The archive is built in memory before anything is written, so a render failure is
+// still reported as a problem response rather than a truncated download.
+// @Summary Download certificates as ZIP
+// @Description Download the PDFs of all certificates matching the filters (admin only)
+// @Tags certificates
+// @Produce application/zip
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param teamId query string false "Team registration ID"
+// @Param type query string false "Certificate type (participation/winner)"
+// @Param track query string false "Track"
+// @Param award query string false "Award"
+// @Success 200 {file} file "ZIP archive of certificate PDFs"
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event not found or no matching certificates"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/certificates/zip [get]
+func (h *CertificateHandler) DownloadCertificatesZIP(c *gin.Context) {
+	filter, err := certificateFilter(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	certs, err := h.DB.GetCertificates(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve certificates: %w", err))
+		return
+	}
+	if len(certs) == 0 {
+		respondError(c, models.NotFoundError(models.CodeCertificateNotFound, "No certificates match the given filters"))
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, cert := range certs {
+		f, err := zw.Create(certificates.FileName(cert))
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to build archive: %w", err))
+			return
+		}
+		if err := h.Renderer.Render(f, cert); err != nil {
+			respondError(c, fmt.Errorf("failed to render certificate: %w", err))
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		respondError(c, fmt.Errorf("failed to build archive: %w", err))
+		return
+	}
+
+	name := fmt.Sprintf("certificates-%s.zip", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// DownloadCertificatePDF renders a single certificate as PDF
+// @Summary Download certificate PDF
+// @Description Render a single certificate as PDF (admin only)
+// @Tags certificates
+// @Produce application/pdf
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param code path string true "Verification code"
+// @Success 200 {file} file "Certificate PDF"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or certificate not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/certificates/{code}/pdf [get]
+func (h *CertificateHandler) DownloadCertificatePDF(c *gin.Context) {
+	cert, err := h.DB.GetCertificateByCode(c.Request.Context(), c.Param("code"))
+	if err == nil && cert.EventID != currentEvent(c).ID {
+		err = models.ErrCertificateNotFound
+	}
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve certificate: %w", err))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.Renderer.Render(&buf, cert); err != nil {
+		respondError(c, fmt.Errorf("failed to render certificate: %w", err))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", certificates.FileName(cert)))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// certificateFilter builds a certificate query from the request's query parameters
+func certificateFilter(c *gin.Context) (bson.M, error) {
+	filter := bson.M{"eventId": currentEvent(c).ID}
+	if teamID := c.Query("teamId"); teamID != "" {
+		oid, err := primitive.ObjectIDFromHex(teamID)
+		if err != nil {
+			return nil, models.ErrInvalidTeamID
+		}
+		filter["teamRegistrationId"] = oid
+	}
+	if t := c.Query("type"); t != "" {
+		filter["type"] = t
+	}
+	if track := c.Query("track"); track != "" {
+		filter["track"] = track
+	}
+	if award := c.Query("award"); award != "" {
+		filter["award"] = award
+	}
+	return filter, nil
+}
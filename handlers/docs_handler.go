@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// docsPage renders the OpenAPI document with Swagger UI
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>IGC Admin Backend API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>
+`
+
+// DocsHandler serves the OpenAPI document and the interactive docs page
+type DocsHandler struct {
+	// Spec is the OpenAPI document encoded as JSON
+	Spec []byte
+}
+
+// NewDocsHandler creates a new DocsHandler
+func NewDocsHandler(spec []byte) *DocsHandler {
+	return &DocsHandler{Spec: spec}
+}
+
+// OpenAPI serves the OpenAPI document
+// @Summary OpenAPI document
+// @Description The OpenAPI 3 description of this API
+// @Tags docs
+// @Produce json
+// @Success 200 {object} object
+// @Router /api/v1/openapi.json [get]
+func (h *DocsHandler) OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.Spec)
+}
+
+// UI serves the interactive API documentation
+// @Summary API documentation
+// @Description Interactive documentation of the API (Swagger UI)
+// @Tags docs
+// @Produce html
+// @Success 200 {string} string
+// @Router /api/v1/docs [get]
+func (h *DocsHandler) UI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errInvalidCredentials      = models.UnauthorizedError(models.CodeInvalidCredentials, "Invalid credentials")
+	errInsufficientPermissions = models.ForbiddenError(models.CodeInsufficientPermissions, "Insufficient permissions")
+)
+
+// respondError hands err to middleware.ErrorHandler, which renders it as a problem
+// response, and stops the handler chain. Errors that aren't domain errors become a
+// generic 500; wrap them with the failed operation so the logs say what went wrong.
+func respondError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// badRequest is the error of a request that breaks a rule checked by the handler
+func badRequest(message string) *models.Error {
+	return models.ValidationError(models.CodeInvalidRequest, message)
+}
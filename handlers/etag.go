@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// etag is the entity tag of a version of a user or team registration
+func etag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// setETag sets the ETag of the record version in the response
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", etag(version))
+}
+
+// ifMatch reports whether the If-Match header of the request, if sent, matches the
+// current version of the record. Weak tags never match, as RFC 9110 requires.
+func ifMatch(c *gin.Context, version int64) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// respondStale refuses a write to a record that changed since the client read it
+// (ErrVersionMismatch) or while the write was made (ErrVersionConflict). The response
+// carries the current record and its ETag so the client can merge and retry.
+func respondStale(c *gin.Context, err *models.Error, version int64, current any) {
+	setETag(c, version)
+	respondError(c, err.With("current", current))
+}
+
+// respondTeamWriteError responds with the error of a failed write to a team
+// registration, adding the current registration to version conflicts
+func respondTeamWriteError(c *gin.Context, db *models.DatabaseService, id, operation string, err error) {
+	if errors.Is(err, models.ErrVersionConflict) {
+		if current, getErr := db.GetTeamRegistrationByID(c.Request.Context(), id); getErr == nil {
+			respondStale(c, models.ErrVersionConflict, current.Version, current)
+			return
+		}
+	}
+	respondError(c, fmt.Errorf("failed to %s: %w", operation, err))
+}
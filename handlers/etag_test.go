@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int64
+		want    bool
+	}{
+		{"no header", "", 3, true},
+		{"any version", "*", 3, true},
+		{"current version", `"3"`, 3, true},
+		{"older version", `"2"`, 3, false},
+		{"unquoted tag", "3", 3, false},
+		{"weak tag", `W/"3"`, 3, false},
+		{"list with current version", `"1", "3"`, 3, true},
+		{"list without current version", `"1","2"`, 3, false},
+		{"unversioned record", `"0"`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+			if got := ifMatch(c, tt.version); got != tt.want {
+				t.Errorf("ifMatch(%q, %d) = %v, want %v", tt.header, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+// withIfMatch sets the path ID and the If-Match header of the request
+func withIfMatch(id, tag string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		if tag != "" {
+			c.Request.Header.Set("If-Match", tag)
+		}
+	}
+}
+
+// stale checks a refused write: its status and code, the ETag of the current record
+// and the current record in the body
+func stale(t *testing.T, w *httptest.ResponseRecorder, status int, code, tag string) map[string]interface{} {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d: %s", w.Code, status, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("content type = %q, want application/problem+json", got)
+	}
+	if got := w.Header().Get("ETag"); got != tag {
+		t.Errorf("ETag = %q, want %q", got, tag)
+	}
+	body := decode(t, w)
+	if body["code"] != code {
+		t.Errorf("code = %v, want %s", body["code"], code)
+	}
+	current, ok := body["current"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("body = %v, want the current record", body)
+	}
+	return current
+}
+
+func TestUpdateUserVersions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	user := models.NewUser("judge1", "secret")
+	user.ID = primitive.NewObjectID()
+	user.Version = 3
+	renamed := *user
+	renamed.Username = "judge2"
+	renamed.Version = 4
+	body := map[string]string{"username": "judge2"}
+
+	mt.Run("stale If-Match", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch, doc(mt, user)))
+
+		w := serve(NewUserHandler(newTestDB(mt)).UpdateUser, http.MethodPut, "/api/v1/users/"+user.ID.Hex(), body, withIfMatch(user.ID.Hex(), `"2"`))
+
+		current := stale(mt.T, w, http.StatusPreconditionFailed, models.CodeVersionMismatch, `"3"`)
+		if current["username"] != "judge1" || current["id"] != user.ID.Hex() {
+			mt.Errorf("current = %v, want judge1", current)
+		}
+		if _, ok := current["password"]; ok {
+			mt.Error("the password is returned")
+		}
+		mt.GetStartedEvent()
+		if event := mt.GetStartedEvent(); event != nil {
+			mt.Errorf("sent %s after the precondition failed", event.CommandName)
+		}
+	})
+
+	mt.Run("changed while writing", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch, doc(mt, user)),
+			mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+			mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch, doc(mt, renamed)),
+			mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch, doc(mt, renamed)),
+		)
+
+		w := serve(NewUserHandler(newTestDB(mt)).UpdateUser, http.MethodPut, "/api/v1/users/"+user.ID.Hex(), body, withIfMatch(user.ID.Hex(), `"3"`))
+
+		current := stale(mt.T, w, http.StatusConflict, models.CodeVersionConflict, `"4"`)
+		if current["username"] != "judge2" {
+			mt.Errorf("current = %v, want the concurrent change", current)
+		}
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "findAndModify" {
+				query := event.Command.Lookup("query").Document()
+				if query.Lookup("version").AsInt64() != 3 {
+					mt.Errorf("write query = %v, want version 3", query)
+				}
+			}
+		}
+	})
+
+	mt.Run("current If-Match", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch, doc(mt, user)),
+			mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(mt, renamed)}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		w := serve(NewUserHandler(newTestDB(mt)).UpdateUser, http.MethodPut, "/api/v1/users/"+user.ID.Hex(), body, withIfMatch(user.ID.Hex(), `"3"`))
+
+		if w.Code != http.StatusOK {
+			mt.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("ETag"); got != `"4"` {
+			mt.Errorf("ETag = %q, want the updated version", got)
+		}
+	})
+}
+
+func TestTeamWriteVersions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	event := models.NewEvent("igc-2026", "IGC 2026")
+	event.ID = primitive.NewObjectID()
+	team := models.NewTeamRegistration()
+	team.ID = primitive.NewObjectID()
+	team.EventID = event.ID
+	team.TeamName = "Rockets"
+	team.Version = 5
+	setup := func(tag string) func(c *gin.Context) {
+		return func(c *gin.Context) {
+			withIfMatch(team.ID.Hex(), tag)(c)
+			c.Set(eventContextKey, event)
+		}
+	}
+
+	mt.Run("stale decision", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, team)))
+
+		w := serve(NewTeamRegistrationHandler(newTestDB(mt)).ApproveOrRejectTeamRegistration, http.MethodPut,
+			"/api/v1/team-registrations/"+team.ID.Hex()+"/action", map[string]string{"action": "approve"}, setup(`"4"`))
+
+		current := stale(mt.T, w, http.StatusPreconditionFailed, models.CodeVersionMismatch, `"5"`)
+		if current["teamName"] != "Rockets" || current["version"] != float64(5) {
+			mt.Errorf("current = %v, want the registration at version 5", current)
+		}
+	})
+
+	mt.Run("decision after a concurrent change", func(mt *mtest.T) {
+		changed := *team
+		changed.Version = 6
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, team)),
+			mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, changed)),
+			mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, changed)),
+		)
+
+		w := serve(NewTeamRegistrationHandler(newTestDB(mt)).ApproveOrRejectTeamRegistration, http.MethodPut,
+			"/api/v1/team-registrations/"+team.ID.Hex()+"/action", map[string]string{"action": "reject", "reason": "Incomplete"}, setup(`"5"`))
+
+		current := stale(mt.T, w, http.StatusConflict, models.CodeVersionConflict, `"6"`)
+		if current["version"] != float64(6) {
+			mt.Errorf("current = %v, want the registration at version 6", current)
+		}
+	})
+}
+
+func TestReviewChangeRequestConflict(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	event := models.NewEvent("igc-2026", "IGC 2026")
+	event.ID = primitive.NewObjectID()
+	team := models.NewTeamRegistration()
+	team.ID = primitive.NewObjectID()
+	team.EventID = event.ID
+	team.TeamName = "Rockets"
+	team.MentorName = "Dr. Rao"
+	team.Version = 2
+	mentor := "Dr. Iyer"
+	cr := models.NewChangeRequest(team, "leader@example.org", models.TeamChanges{MentorName: &mentor})
+	cr.ID = primitive.NewObjectID()
+	approved := *cr
+	approved.Status = models.ChangeRequestApproved
+	changed := *team
+	changed.MentorName = "Dr. Shah"
+	changed.Version = 3
+
+	mt.Run("requested field changed since", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns("changerequests"), mtest.FirstBatch, doc(mt, cr)),
+			mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, changed)),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(mt, approved)}),
+			mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, changed)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, changed)),
+		)
+
+		w := serve(NewParticipantHandler(newTestDB(mt), &recordingMailer{}, "").ReviewChangeRequest, http.MethodPut,
+			"/api/v1/change-requests/"+cr.ID.Hex()+"/review", map[string]string{"action": "approve"}, func(c *gin.Context) {
+				c.Params = gin.Params{{Key: "id", Value: cr.ID.Hex()}}
+				c.Set(eventContextKey, event)
+			})
+
+		current := stale(mt.T, w, http.StatusConflict, models.CodeChangeRequestConflict, `"3"`)
+		if current["mentorName"] != "Dr. Shah" {
+			mt.Errorf("current = %v, want the registration with the new mentor", current)
+		}
+		if fields, _ := decode(mt.T, w)["fields"].([]interface{}); len(fields) != 1 || fields[0] != "mentorName" {
+			mt.Errorf("body = %s, want mentorName among the conflicting fields", w.Body.String())
+		}
+	})
+}
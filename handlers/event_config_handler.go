@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// EventConfigHandler handles the event schedule and capacity configuration
+type EventConfigHandler struct {
+	DB *models.DatabaseService
+}
+
+// NewEventConfigHandler creates a new EventConfigHandler
+func NewEventConfigHandler(db *models.DatabaseService) *EventConfigHandler {
+	return &EventConfigHandler{DB: db}
+}
+
+// UpdateEventConfigRequest represents the event configuration payload.
+// Omitted times remove the corresponding restriction.
+type UpdateEventConfigRequest struct {
+	RegistrationOpensAt     *time.Time     `json:"registrationOpensAt,omitempty"`
+	RegistrationClosesAt    *time.Time     `json:"registrationClosesAt,omitempty"`
+	VideoSubmissionDeadline *time.Time     `json:"videoSubmissionDeadline,omitempty"`
+	ParticipantEditDeadline *time.Time     `json:"participantEditDeadline,omitempty"`
+	DefaultTrackCapacity    int            `json:"defaultTrackCapacity" binding:"min=0"`
+	TrackCapacity           map[string]int `json:"trackCapacity,omitempty"`
+	// DuplicatePolicy handles registrations sharing a participant with another team (default: flag)
+	DuplicatePolicy models.DuplicatePolicy `json:"duplicatePolicy,omitempty" binding:"omitempty,oneof=allow flag block"`
+}
+
+// GetEventConfig returns the event schedule and capacity limits (public)
+// @Summary Get event configuration
+// @Description Get registration windows, deadlines and per-track capacity of an event
+// @Tags event-config
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Success 200 {object} EventConfigResponse
+// @Failure 404 {object} Problem "Event not found"
+// @Router /api/v1/event-config [get]
+func (h *EventConfigHandler) GetEventConfig(c *gin.Context) {
+	event := currentEvent(c)
+
+	now := time.Now()
+	c.JSON(http.StatusOK, gin.H{
+		"event":            event.Slug,
+		"config":           event.Config,
+		"registrationOpen": !event.ReadOnly && event.Config.CheckRegistrationOpen(now) == nil,
+		"serverTime":       now,
+	})
+}
+
+// UpdateEventConfig replaces the event configuration
+// @Summary Update event configuration
+// @Description Replace registration windows, deadlines, per-track capacity and the duplicate participant policy (admin only).
+// @Description Raising a capacity promotes waitlisted teams immediately.
+// @Tags event-config
+// @Accept json
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param config body UpdateEventConfigRequest true "Event configuration"
+// @Success 200 {object} EventConfigUpdateResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event not found"
+// @Failure 409 {object} Problem "Event is read-only"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/event-config [put]
+func (h *EventConfigHandler) UpdateEventConfig(c *gin.Context) {
+	var req UpdateEventConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	cfg := models.NewEventConfig()
+	cfg.RegistrationOpensAt = req.RegistrationOpensAt
+	cfg.RegistrationClosesAt = req.RegistrationClosesAt
+	cfg.VideoSubmissionDeadline = req.VideoSubmissionDeadline
+	cfg.ParticipantEditDeadline = req.ParticipantEditDeadline
+	cfg.DefaultTrackCapacity = req.DefaultTrackCapacity
+	cfg.DuplicatePolicy = req.DuplicatePolicy
+	if req.TrackCapacity != nil {
+		cfg.TrackCapacity = req.TrackCapacity
+	}
+	if err := cfg.Validate(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	event := currentEvent(c)
+	before := auditSnapshot(event.Config)
+	event.Config = *cfg
+	saved, err := h.DB.UpdateEvent(c.Request.Context(), event)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to save event configuration: %w", err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditEventConfigUpdate, models.AuditTargetEvent, saved.ID.Hex(), before, saved.Config)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Event configuration updated successfully",
+		"event":   saved.Slug,
+		"config":  saved.Config,
+	})
+}
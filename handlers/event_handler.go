@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// eventContextKey is the gin context key holding the event a request is scoped to
+const eventContextKey = "event"
+
+// errEventReadOnly rejects changes to archived events
+var errEventReadOnly = models.ConflictError(models.CodeEventReadOnly, "Event is read-only")
+
+// EventHandler handles event (edition) management
+type EventHandler struct {
+	DB *models.DatabaseService
+}
+
+// NewEventHandler creates a new EventHandler
+func NewEventHandler(db *models.DatabaseService) *EventHandler {
+	return &EventHandler{DB: db}
+}
+
+// EventRequest represents the event create/update payload
+type EventRequest struct {
+	Slug         string                   `json:"slug" binding:"required,max=50"`
+	Name         string                   `json:"name" binding:"required,max=200"`
+	NumberPrefix string                   `json:"numberPrefix" binding:"required"`
+	TeamIDPrefix string                   `json:"teamIdPrefix" binding:"required"`
+	Tracks       []models.Track           `json:"tracks"`
+	Rubric       []models.RubricCriterion `json:"rubric" binding:"dive"`
+	JudgeIDs     []string                 `json:"judgeIds"`
+	Config       *models.EventConfig      `json:"config,omitempty"`
+	ReadOnly     bool                     `json:"readOnly"`
+}
+
+// EventScope resolves the event a request targets from the "event" query parameter
+// or the X-Event-ID header (slug or ID), defaulting to the active event.
+// Write requests against read-only events are rejected.
+func EventScope(db *models.DatabaseService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ref := strings.TrimSpace(c.Query("event"))
+		if ref == "" {
+			ref = strings.TrimSpace(c.GetHeader("X-Event-ID"))
+		}
+
+		var event *models.Event
+		var err error
+		if ref == "" {
+			event, err = db.GetActiveEvent(c.Request.Context())
+		} else {
+			event, err = db.GetEvent(c.Request.Context(), ref)
+		}
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to load event: %w", err))
+			return
+		}
+
+		if event.ReadOnly && c.Request.Method != http.MethodGet {
+			respondError(c, errEventReadOnly.With("event", event.Slug))
+			return
+		}
+
+		c.Set(eventContextKey, event)
+		c.Next()
+	}
+}
+
+// currentEvent returns the event resolved by EventScope
+func currentEvent(c *gin.Context) *models.Event {
+	event, _ := c.MustGet(eventContextKey).(*models.Event)
+	return event
+}
+
+// teamInEvent loads a team of the current event, writing an error response if it does not exist
+func teamInEvent(c *gin.Context, db *models.DatabaseService, id string) (*models.TeamRegistration, bool) {
+	team, err := db.GetTeamRegistrationByID(c.Request.Context(), id)
+	if err == nil && team.EventID != currentEvent(c).ID {
+		err = models.ErrTeamNotFound
+	}
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve team registration: %w", err))
+		return nil, false
+	}
+	return team, true
+}
+
+// toEvent applies the request to an event
+func (req *EventRequest) toEvent(event *models.Event) error {
+	event.Slug = strings.ToLower(strings.TrimSpace(req.Slug))
+	event.Name = strings.TrimSpace(req.Name)
+	event.NumberPrefix = strings.ToUpper(strings.TrimSpace(req.NumberPrefix))
+	event.TeamIDPrefix = strings.ToUpper(strings.TrimSpace(req.TeamIDPrefix))
+	if req.Tracks != nil {
+		event.Tracks = req.Tracks
+	}
+	if req.Rubric != nil {
+		event.Rubric = req.Rubric
+	}
+	if req.JudgeIDs != nil {
+		ids := make([]primitive.ObjectID, 0, len(req.JudgeIDs))
+		for _, id := range req.JudgeIDs {
+			oid, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				return models.InvalidIDError("Invalid judge ID").With("id", id)
+			}
+			ids = append(ids, oid)
+		}
+		event.JudgeIDs = ids
+	}
+	if req.Config != nil {
+		event.Config = *req.Config
+		if event.Config.TrackCapacity == nil {
+			event.Config.TrackCapacity = map[string]int{}
+		}
+	}
+	event.ReadOnly = req.ReadOnly
+	return nil
+}
+
+// GetEvents lists all events
+// @Summary List events
+// @Description List all editions of the challenge, newest first
+// @Tags events
+// @Produce json
+// @Success 200 {object} EventListResponse
+// @Failure 500 {object} Problem
+// @Router /api/v1/events/ [get]
+func (h *EventHandler) GetEvents(c *gin.Context) {
+	events, err := h.DB.GetEvents(c.Request.Context())
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve events: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+	})
+}
+
+// GetEvent retrieves an event by slug or ID
+// @Summary Get event
+// @Description Get an event with its tracks, rubric and deadlines
+// @Tags events
+// @Produce json
+// @Param event path string true "Event slug or ID"
+// @Success 200 {object} EventDetailResponse
+// @Failure 404 {object} Problem
+// @Router /api/v1/events/{event} [get]
+func (h *EventHandler) GetEvent(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	c.JSON(http.StatusOK, gin.H{
+		"event":            event,
+		"registrationOpen": !event.ReadOnly && event.Config.CheckRegistrationOpen(now) == nil,
+		"serverTime":       now,
+	})
+}
+
+// CreateEvent creates a new event
+// @Summary Create event
+// @Description Create a new edition (admin only). The first event becomes the active one.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param event body EventRequest true "Event data"
+// @Success 201 {object} EventEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 409 {object} Problem "Event already exists"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/events/ [post]
+func (h *EventHandler) CreateEvent(c *gin.Context) {
+	var req EventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	event := models.NewEvent(req.Slug, req.Name)
+	if err := req.toEvent(event); err != nil {
+		respondError(c, err)
+		return
+	}
+	// Without an explicit list, the event offers every active catalogue track
+	if req.Tracks == nil {
+		entries, err := h.DB.GetCatalogEntries(c.Request.Context(), models.CatalogTrack, false)
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to retrieve tracks: %w", err))
+			return
+		}
+		event.Tracks = make([]models.Track, 0, len(entries))
+		for _, entry := range entries {
+			event.Tracks = append(event.Tracks, models.Track(entry.Name))
+		}
+	}
+	if err := h.resolveTracks(c.Request.Context(), event); err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := event.Validate(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	created, err := h.DB.CreateEvent(c.Request.Context(), event)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to create event: %w", err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditEventCreate, models.AuditTargetEvent, created.ID.Hex(), nil, created)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Event created successfully",
+		"event":   created,
+	})
+}
+
+// UpdateEvent replaces an event's settings
+// @Summary Update event
+// @Description Update an event's tracks, rubric, deadlines, prefixes and judge pool (admin only).
+// @Description Raising a capacity promotes waitlisted teams immediately.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param event path string true "Event slug or ID"
+// @Param data body EventRequest true "Event data"
+// @Success 200 {object} EventEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem "Number prefix already in use"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/events/{event} [put]
+func (h *EventHandler) UpdateEvent(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+	before := auditSnapshot(event)
+
+	var req EventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	// Slugs and prefixes are baked into URLs and registration numbers
+	if strings.ToLower(strings.TrimSpace(req.Slug)) != event.Slug {
+		respondError(c, badRequest("Event slug cannot be changed"))
+		return
+	}
+	if err := req.toEvent(event); err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := h.resolveTracks(c.Request.Context(), event); err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := event.Validate(); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := h.DB.UpdateEvent(c.Request.Context(), event)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to update event: %w", err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditEventUpdate, models.AuditTargetEvent, updated.ID.Hex(), before, updated)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Event updated successfully",
+		"event":   updated,
+	})
+}
+
+// ActivateEvent makes an event the default for requests that do not name one
+// @Summary Activate event
+// @Description Make an event the active edition (admin only)
+// @Tags events
+// @Produce json
+// @Param event path string true "Event slug or ID"
+// @Success 200 {object} EventEnvelope
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/events/{event}/activate [post]
+func (h *EventHandler) ActivateEvent(c *gin.Context) {
+	event, ok := h.loadEvent(c)
+	if !ok {
+		return
+	}
+
+	before := auditSnapshot(event)
+	if err := h.DB.SetActiveEvent(c.Request.Context(), event.ID); err != nil {
+		respondError(c, fmt.Errorf("failed to activate event: %w", err))
+		return
+	}
+	event.Active = true
+	recordAudit(c, h.DB, models.AuditEventActivate, models.AuditTargetEvent, event.ID.Hex(), before, event)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Event activated successfully",
+		"event":   event,
+	})
+}
+
+// GetEvaluations lists the judges' evaluations of the current event
+// @Summary List evaluations
+// @Description List judge evaluations and rubric scores of an event (admin only)
+// @Tags events
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param teamId query string false "Filter by team registration ID"
+// @Param judgeId query string false "Filter by judge ID"
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: -createdAt)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Success 200 {object} EvaluationListResponse
+// @Failure 400 {object} Problem "Invalid filter or paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/evaluations/ [get]
+func (h *EventHandler) GetEvaluations(c *gin.Context) {
+	q, err := listQuery(c, models.EvaluationSortFields, "-createdAt")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	filter := bson.M{"eventId": currentEvent(c).ID}
+	for param, field := range map[string]string{"teamId": "teamRegistrationId", "judgeId": "judgeId"} {
+		if v := c.Query(param); v != "" {
+			oid, err := primitive.ObjectIDFromHex(v)
+			if err != nil {
+				respondError(c, models.InvalidIDError("Invalid "+param).With("id", v))
+				return
+			}
+			filter[field] = oid
+		}
+	}
+
+	page, err := h.DB.ListEvaluations(c.Request.Context(), filter, q)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve evaluations: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"evaluations": page.Items,
+		"pagination":  pagination(q, page),
+	})
+}
+
+// resolveTracks replaces the event's tracks with their current catalogue names
+func (h *EventHandler) resolveTracks(ctx context.Context, event *models.Event) error {
+	for i, track := range event.Tracks {
+		entry, err := h.DB.FindCatalogEntry(ctx, models.CatalogTrack, string(track))
+		if errors.Is(err, models.ErrNotFound) {
+			return badRequest(fmt.Sprintf("Unknown track %q", track))
+		} else if err != nil {
+			return fmt.Errorf("failed to look up track: %w", err)
+		}
+		event.Tracks[i] = models.Track(entry.Name)
+	}
+	return nil
+}
+
+// loadEvent loads the event named by the :event path parameter
+func (h *EventHandler) loadEvent(c *gin.Context) (*models.Event, bool) {
+	event, err := h.DB.GetEvent(c.Request.Context(), c.Param("event"))
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to load event: %w", err))
+		return nil, false
+	}
+	return event, true
+}
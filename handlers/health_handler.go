@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/buildinfo"
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/Mastermind730/igc-admin-backend/workers"
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds the dependency checks of a readiness probe
+const readinessTimeout = 3 * time.Second
+
+// HealthHandler serves the API root, the health check and the liveness and readiness probes
+type HealthHandler struct {
+	DB      *models.DatabaseService
+	Workers *workers.Manager
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(db *models.DatabaseService, manager *workers.Manager) *HealthHandler {
+	return &HealthHandler{DB: db, Workers: manager}
+}
+
+// Index welcomes clients at the API root
+// @Summary API root
+// @Description Welcome message with the API version and a link to the documentation
+// @Tags health
+// @Produce json
+// @Success 200 {object} IndexResponse
+// @Router / [get]
+func (h *HealthHandler) Index(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Welcome to IGC Admin Backend API",
+		"version": buildinfo.Version,
+		"docs":    "/api/v1/docs",
+	})
+}
+
+// Status reports that the API is running
+// @Summary Health check
+// @Description Reports that the API is running and its version
+// @Tags health
+// @Produce json
+// @Success 200 {object} StatusResponse
+// @Router /api/v1/health [get]
+func (h *HealthHandler) Status(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "healthy",
+		"message": "IGC Admin Backend API is running",
+		"version": buildinfo.Version,
+	})
+}
+
+// Livez reports that the process is up and serving requests
+// @Summary Liveness probe
+// @Description Succeeds while the process can serve requests; doesn't check dependencies
+// @Tags health
+// @Produce json
+// @Success 200 {object} LivenessResponse
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"build":  buildinfo.Get(),
+	})
+}
+
+// Readyz reports whether the API can serve traffic: MongoDB answers, the required
+// indexes exist and the background workers are running
+// @Summary Readiness probe
+// @Description Pings MongoDB, checks the required indexes and background worker status
+// @Tags health
+// @Produce json
+// @Success 200 {object} ReadinessResponse
+// @Failure 503 {object} ReadinessResponse "Not ready"
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	ready := true
+	checks := gin.H{}
+
+	if err := h.DB.Ping(ctx); err != nil {
+		ready = false
+		checks["mongodb"] = gin.H{"status": "fail", "error": err.Error()}
+	} else {
+		checks["mongodb"] = gin.H{"status": "ok"}
+	}
+
+	// Indexes can only be checked while MongoDB is reachable
+	if ready {
+		missing, err := h.DB.MissingIndexes(ctx)
+		switch {
+		case err != nil:
+			ready = false
+			checks["indexes"] = gin.H{"status": "fail", "error": err.Error()}
+		case len(missing) > 0:
+			ready = false
+			checks["indexes"] = gin.H{"status": "fail", "missing": missing}
+		default:
+			checks["indexes"] = gin.H{"status": "ok"}
+		}
+	}
+
+	workerStatus := "ok"
+	if !h.Workers.Healthy() {
+		ready = false
+		workerStatus = "fail"
+	}
+	checks["workers"] = gin.H{"status": workerStatus, "workers": h.Workers.Status()}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not ready", http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{
+		"status": status,
+		"checks": checks,
+		"build":  buildinfo.Get(),
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mastermind730/igc-admin-backend/middleware"
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// testDatabase is the database name of the mock deployment
+const testDatabase = "igc_test"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestDB returns a DatabaseService whose commands are answered by the mock
+// deployment of mt; queue the replies with mt.AddMockResponses
+func newTestDB(mt *mtest.T) *models.DatabaseService {
+	return models.NewDatabaseService(mt.Client, testDatabase, "videos")
+}
+
+// ns is the namespace of a collection of the test database, for cursor replies
+func ns(collection string) string {
+	return testDatabase + "." + collection
+}
+
+// doc converts a record into the document the database would return for it
+func doc(t testing.TB, v interface{}) bson.D {
+	t.Helper()
+	data, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal %T: %v", v, err)
+	}
+	var d bson.D
+	if err := bson.Unmarshal(data, &d); err != nil {
+		t.Fatalf("unmarshal %T: %v", v, err)
+	}
+	return d
+}
+
+// serve runs handler on a request with a JSON body behind the error handling
+// middleware and returns the recorded response. setup, when given, runs first on
+// the request context, e.g. to set the user.
+func serve(handler gin.HandlerFunc, method, path string, body interface{}, setup func(c *gin.Context)) *httptest.ResponseRecorder {
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+	w := httptest.NewRecorder()
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.Handle(method, "/*path", func(c *gin.Context) {
+		if setup != nil {
+			setup(c)
+		}
+		handler(c)
+	})
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// decode reads a JSON response body
+func decode(t testing.TB, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	return body
+}
+
+// configure changes the handler settings for the duration of the test
+func configure(t testing.TB, change func(s *Settings)) {
+	previous := settings
+	s := settings
+	change(&s)
+	Configure(s)
+	t.Cleanup(func() { settings = previous })
+}
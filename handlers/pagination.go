@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPageOffset is the number of records page numbers can skip; deeper pages are only
+// reachable with cursors, which don't make the database scan the skipped records
+const maxPageOffset = 100_000
+
+// listQuery reads the paging parameters of a list endpoint: limit (default and maximum
+// configurable), sort (one of fields, prefixed with - for descending order), and cursor,
+// the nextCursor of the previous page. The page parameter still pages by number for
+// clients that don't use cursors.
+func listQuery(c *gin.Context, fields models.SortFields, defaultSort string) (models.PageQuery, error) {
+	q := models.PageQuery{Limit: int64(settings.DefaultPageLimit)}
+	var invalid []models.FieldError
+
+	if s := c.Query("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		switch {
+		case err != nil || limit < 1:
+			invalid = append(invalid, models.FieldError{Field: "limit", Code: models.FieldOutOfRange, Message: "Must be a positive number"})
+		case limit > settings.MaxPageLimit:
+			q.Limit = int64(settings.MaxPageLimit)
+		default:
+			q.Limit = int64(limit)
+		}
+	}
+
+	sort, _ := fields.Parse(defaultSort)
+	if s := c.Query("sort"); s != "" {
+		var ok bool
+		if sort, ok = fields.Parse(s); !ok {
+			invalid = append(invalid, models.FieldError{
+				Field:   "sort",
+				Code:    models.FieldInvalidChoice,
+				Message: "Must be one of: " + strings.Join(fields, ", ") + " (prefix with - for descending order)",
+			})
+		}
+	}
+	q.Sort = sort
+
+	if s := c.Query("cursor"); s != "" {
+		cursor, err := models.DecodeCursor(s, sort)
+		if err != nil {
+			invalid = append(invalid, models.FieldError{Field: "cursor", Code: models.FieldInvalid, Message: "Is not a cursor of this list and sort order"})
+		}
+		q.Cursor = cursor
+	} else if s := c.Query("page"); s != "" {
+		page, err := strconv.Atoi(s)
+		switch {
+		case err != nil || page < 1:
+			invalid = append(invalid, models.FieldError{Field: "page", Code: models.FieldOutOfRange, Message: "Must be a positive number"})
+		case int64(page-1) > maxPageOffset/q.Limit:
+			invalid = append(invalid, models.FieldError{
+				Field:   "page",
+				Code:    models.FieldOutOfRange,
+				Message: fmt.Sprintf("Must be at most %d with this limit; use cursor to page further", maxPageOffset/q.Limit+1),
+			})
+		default:
+			q.Offset = int64(page-1) * q.Limit
+		}
+	}
+
+	if len(invalid) > 0 {
+		return q, models.InvalidFieldsError(invalid)
+	}
+	return q, nil
+}
+
+// pagination describes a page of a list for the response
+func pagination[T any](q models.PageQuery, page *models.Page[T]) Pagination {
+	p := Pagination{
+		Limit:      q.Limit,
+		Total:      page.Total,
+		HasMore:    page.HasMore,
+		NextCursor: page.NextCursor,
+	}
+	if q.Cursor == nil {
+		p.Page = q.Offset/q.Limit + 1
+	}
+	return p
+}
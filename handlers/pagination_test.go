@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// query runs listQuery on a request with the given query string
+func query(t *testing.T, rawQuery string) (models.PageQuery, error) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+rawQuery, nil)
+	return listQuery(c, models.TeamSortFields, "-submittedAt")
+}
+
+func TestListQuery(t *testing.T) {
+	configure(t, func(s *Settings) { s.DefaultPageLimit = 10; s.MaxPageLimit = 50 })
+	cursor := &models.Cursor{Sort: "teamName", Value: "Alpha", ID: primitive.NewObjectID()}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    models.PageQuery
+		invalid []string
+	}{
+		{"defaults", "", models.PageQuery{Limit: 10, Sort: models.Sort{Field: "submittedAt", Desc: true}}, nil},
+		{"page and limit", "page=3&limit=20", models.PageQuery{Limit: 20, Sort: models.Sort{Field: "submittedAt", Desc: true}, Offset: 40}, nil},
+		{"limit above the maximum", "limit=500", models.PageQuery{Limit: 50, Sort: models.Sort{Field: "submittedAt", Desc: true}}, nil},
+		{"cursor", "sort=teamName&cursor=" + cursor.Encode() + "&page=4", models.PageQuery{Limit: 10, Sort: models.Sort{Field: "teamName"}, Cursor: cursor}, nil},
+		{"invalid values", "limit=0&sort=password&page=-1", models.PageQuery{}, []string{"limit", "sort", "page"}},
+		{"last numbered page", "page=2001&limit=50", models.PageQuery{Limit: 50, Sort: models.Sort{Field: "submittedAt", Desc: true}, Offset: 100_000}, nil},
+		{"page past the numbered pages", "page=2002&limit=50", models.PageQuery{}, []string{"page"}},
+		{"page overflowing the offset", "page=9223372036854775807&limit=50", models.PageQuery{}, []string{"page"}},
+		{"cursor of another sort", "sort=-teamName&cursor=" + cursor.Encode(), models.PageQuery{}, []string{"cursor"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := query(t, tt.query)
+			if tt.invalid == nil {
+				if err != nil {
+					t.Fatalf("listQuery: %v", err)
+				}
+				if got.Limit != tt.want.Limit || got.Sort != tt.want.Sort || got.Offset != tt.want.Offset ||
+					(got.Cursor == nil) != (tt.want.Cursor == nil) || (got.Cursor != nil && got.Cursor.ID != tt.want.Cursor.ID) {
+					t.Errorf("listQuery = %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+
+			var domainErr *models.Error
+			if !errors.As(err, &domainErr) {
+				t.Fatalf("listQuery error = %v, want a validation error", err)
+			}
+			fields, _ := domainErr.Extensions["errors"].([]models.FieldError)
+			if len(fields) != len(tt.invalid) {
+				t.Fatalf("invalid fields = %+v, want %v", fields, tt.invalid)
+			}
+			for i, f := range fields {
+				if f.Field != tt.invalid[i] {
+					t.Errorf("invalid field %d = %s, want %s", i, f.Field, tt.invalid[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllUsersPagination(t *testing.T) {
+	configure(t, func(s *Settings) { s.DefaultPageLimit = 10; s.MaxPageLimit = 50 })
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("numbered page", func(mt *mtest.T) {
+		user := models.User{ID: primitive.NewObjectID(), Username: "asha", Role: "admin"}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch, doc(mt, map[string]int64{"n": 3})),
+			mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch, doc(mt, user)),
+		)
+
+		w := serve(NewUserHandler(newTestDB(mt)).GetAllUsers, http.MethodGet, "/api/v1/users/?page=2&limit=2", nil, nil)
+		if w.Code != http.StatusOK {
+			mt.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+		got := decode(mt, w)["pagination"].(map[string]interface{})
+		want := map[string]interface{}{"page": 2.0, "limit": 2.0, "total": 3.0, "hasMore": false}
+		for key, value := range want {
+			if got[key] != value {
+				mt.Errorf("pagination %s = %v, want %v", key, got[key], value)
+			}
+		}
+	})
+
+	mt.Run("invalid paging parameters", func(mt *mtest.T) {
+		w := serve(NewUserHandler(newTestDB(mt)).GetAllUsers, http.MethodGet, "/api/v1/users/?sort=password", nil, nil)
+		if w.Code != http.StatusBadRequest {
+			mt.Fatalf("status = %d, want 400", w.Code)
+		}
+		if body := decode(mt, w); body["code"] != models.CodeInvalidRequest || body["errors"] == nil {
+			mt.Errorf("problem = %v, want invalid_request listing the fields", body)
+		}
+	})
+}
@@ -0,0 +1,519 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/mailer"
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ParticipantHandler serves the team leader self-service portal
+type ParticipantHandler struct {
+	DB     *models.DatabaseService
+	Mailer mailer.Mailer
+	// PortalURL is the frontend page that receives the login token as ?token=...
+	PortalURL string
+}
+
+// NewParticipantHandler creates a new ParticipantHandler
+func NewParticipantHandler(db *models.DatabaseService, m mailer.Mailer, portalURL string) *ParticipantHandler {
+	return &ParticipantHandler{DB: db, Mailer: m, PortalURL: portalURL}
+}
+
+// MagicLinkRequest represents the login link request payload
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// MagicLinkVerifyRequest represents the login link verification payload
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ParticipantChangeRequestPayload lists the fields a team leader may ask to change
+type ParticipantChangeRequestPayload struct {
+	LeaderMobile      *string             `json:"leaderMobile,omitempty" binding:"omitempty,e164"`
+	Members           []models.TeamMember `json:"members,omitempty" binding:"omitempty,min=1,max=4,dive"`
+	MentorName        *string             `json:"mentorName,omitempty" binding:"omitempty,max=100"`
+	MentorEmail       *string             `json:"mentorEmail,omitempty" binding:"omitempty,email,lowercase"`
+	MentorMobile      *string             `json:"mentorMobile,omitempty" binding:"omitempty,e164"`
+	MentorInstitution *string             `json:"mentorInstitution,omitempty" binding:"omitempty,max=200"`
+	MentorDesignation *string             `json:"mentorDesignation,omitempty" binding:"omitempty,max=100"`
+	TopicName         *string             `json:"topicName,omitempty" binding:"omitempty,max=200"`
+	TopicDescription  *string             `json:"topicDescription,omitempty"`
+	InstituteNOC      *models.DriveFile   `json:"instituteNOC,omitempty"`
+	IDCardsPDF        *models.DriveFile   `json:"idCardsPDF,omitempty"`
+	PresentationPPT   *models.DriveFile   `json:"presentationPPT,omitempty"`
+}
+
+// normalize rewrites the phone numbers to E.164, reading numbers without a country
+// code as numbers of the team's country, and trims and lowercases the emails
+func (r *ParticipantChangeRequestPayload) normalize(country string) {
+	normalizeEmail(r.MentorEmail)
+	normalizePhone(r.LeaderMobile, country)
+	normalizePhone(r.MentorMobile, country)
+	normalizeMembers(r.Members, country)
+}
+
+// ReviewChangeRequestRequest represents the admin decision on a change request
+type ReviewChangeRequestRequest struct {
+	Action string `json:"action" binding:"required,oneof=approve reject"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// hashToken returns the stored form of a magic link token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RequestMagicLink emails a one-time login link to the leader of each matching team
+// @Summary Request participant login link
+// @Description Email a one-time login link to a team leader. Always succeeds to avoid leaking which emails are registered.
+// @Tags participant
+// @Accept json
+// @Produce json
+// @Param request body MagicLinkRequest true "Leader email"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} Problem
+// @Failure 429 {object} Problem "Rate limit exceeded"
+// @Failure 500 {object} Problem
+// @Router /api/v1/participant/auth/request-link [post]
+func (h *ParticipantHandler) RequestMagicLink(c *gin.Context) {
+	var req MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	response := gin.H{"message": "If this email belongs to a team leader, a login link has been sent"}
+
+	teams, err := h.DB.GetTeamRegistrationsByLeaderEmail(c.Request.Context(), models.NormalizeEmail(req.Email))
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to look up teams for login link", "error", err)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	for _, team := range teams {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			respondError(c, fmt.Errorf("failed to create login link: %w", err))
+			return
+		}
+		token := base64.RawURLEncoding.EncodeToString(raw)
+
+		link := &models.MagicLink{
+			TokenHash:          hashToken(token),
+			Email:              strings.ToLower(team.LeaderEmail),
+			TeamRegistrationID: team.ID,
+			ExpiresAt:          time.Now().Add(settings.MagicLinkTTL),
+		}
+		if err := h.DB.CreateMagicLink(c.Request.Context(), link); err != nil {
+			slog.ErrorContext(c.Request.Context(), "failed to store login link", "error", err, "team_id", team.ID.Hex())
+			continue
+		}
+
+		body := fmt.Sprintf("Hello %s,\n\nUse the link below to sign in to the IGC participant portal for team %s (%s).\n\n%s?token=%s\n\nThe link expires in %d minutes and can be used once. If you did not request it, you can ignore this email.\n",
+			team.LeaderName, team.TeamName, team.RegistrationNumber, h.PortalURL, token, int(settings.MagicLinkTTL.Minutes()))
+		if err := h.Mailer.Send(team.LeaderEmail, "Your IGC participant portal login link", body); err != nil {
+			slog.ErrorContext(c.Request.Context(), "failed to send login link", "error", err, "team_id", team.ID.Hex())
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// VerifyMagicLink exchanges a login link token for a participant session token
+// @Summary Verify participant login link
+// @Description Exchange a one-time login token for a participant JWT
+// @Tags participant
+// @Accept json
+// @Produce json
+// @Param request body MagicLinkVerifyRequest true "Login token"
+// @Success 200 {object} ParticipantLoginResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Login link is invalid or has expired"
+// @Failure 429 {object} Problem "Rate limit exceeded"
+// @Failure 500 {object} Problem
+// @Router /api/v1/participant/auth/verify [post]
+func (h *ParticipantHandler) VerifyMagicLink(c *gin.Context) {
+	var req MagicLinkVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	link, err := h.DB.ConsumeMagicLink(c.Request.Context(), hashToken(strings.TrimSpace(req.Token)))
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to consume login link: %w", err))
+		return
+	}
+
+	team, err := h.DB.GetTeamRegistrationByID(c.Request.Context(), link.TeamRegistrationID.Hex())
+	if errors.Is(err, models.ErrNotFound) {
+		respondError(c, models.ErrLoginLinkInvalid)
+		return
+	} else if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve team registration: %w", err))
+		return
+	}
+
+	token, err := GenerateParticipantJWT(team)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to generate token: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"token":   token,
+		"team": gin.H{
+			"id":                 team.ID.Hex(),
+			"teamName":           team.TeamName,
+			"registrationNumber": team.RegistrationNumber,
+		},
+	})
+}
+
+// currentTeam loads the team of the logged-in participant
+func (h *ParticipantHandler) currentTeam(c *gin.Context) (*models.TeamRegistration, bool) {
+	teamID, _ := c.Get("team_id")
+	id, _ := teamID.(string)
+
+	team, err := h.DB.GetTeamRegistrationByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve team registration: %w", err))
+		return nil, false
+	}
+	return team, true
+}
+
+// teamEvent loads the event a team registered for; teams of a deleted event get an unrestricted one
+func (h *ParticipantHandler) teamEvent(c *gin.Context, team *models.TeamRegistration) (*models.Event, bool) {
+	event, err := h.DB.GetEventByID(c.Request.Context(), team.EventID.Hex())
+	if errors.Is(err, models.ErrNotFound) {
+		event, err = &models.Event{ID: team.EventID, Config: *models.NewEventConfig()}, nil
+	}
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to load event: %w", err))
+		return nil, false
+	}
+	// Participant requests are scoped to their team's event
+	c.Set(eventContextKey, event)
+	return event, true
+}
+
+// GetMyRegistration returns the logged-in leader's registration, status and video status
+// @Summary Get own registration
+// @Description View the participant's registration, status, rejection reason and video status
+// @Tags participant
+// @Produce json
+// @Success 200 {object} ParticipantRegistrationResponse
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Participant session required"
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ParticipantAuth
+// @Router /api/v1/participant/registration [get]
+func (h *ParticipantHandler) GetMyRegistration(c *gin.Context) {
+	team, ok := h.currentTeam(c)
+	if !ok {
+		return
+	}
+
+	event, ok := h.teamEvent(c, team)
+	if !ok {
+		return
+	}
+	cfg := &event.Config
+
+	video, err := h.DB.GetVideoSubmission(c.Request.Context(), team)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve video status: %w", err))
+		return
+	}
+	videoStatus := gin.H{"submitted": video != nil, "deadline": cfg.VideoSubmissionDeadline}
+	if video != nil {
+		team.VideoLink = video.Link
+		videoStatus["link"] = video.Link
+		videoStatus["submittedAt"] = video.SubmittedAt
+		if video.SubmittedAt != nil {
+			videoStatus["late"] = cfg.CheckVideoSubmission(*video.SubmittedAt) != nil
+		}
+	}
+
+	pending, err := h.DB.GetChangeRequests(c.Request.Context(), 0, 0, bson.M{"teamRegistrationId": team.ID, "status": models.ChangeRequestPending})
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve change requests: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team":                  team,
+		"status":                team.RegistrationStatus,
+		"rejectionReason":       team.RejectionReason,
+		"video":                 videoStatus,
+		"editDeadline":          cfg.ParticipantEditDeadline,
+		"canEdit":               !event.ReadOnly && cfg.CheckParticipantEdit(time.Now()) == nil,
+		"pendingChangeRequests": pending,
+	})
+}
+
+// CreateChangeRequest submits an edit of the participant's registration for admin approval
+// @Summary Request registration change
+// @Description Submit changes to a restricted set of fields; an admin must approve them
+// @Tags participant
+// @Accept json
+// @Produce json
+// @Param changes body ParticipantChangeRequestPayload true "Requested changes"
+// @Success 201 {object} ChangeRequestEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Participant session required or edit deadline passed"
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem "A change request is already awaiting review, a participant is already in another team or the event is read-only"
+// @Failure 500 {object} Problem
+// @Security ParticipantAuth
+// @Router /api/v1/participant/change-requests [post]
+func (h *ParticipantHandler) CreateChangeRequest(c *gin.Context) {
+	team, ok := h.currentTeam(c)
+	if !ok {
+		return
+	}
+	event, ok := h.teamEvent(c, team)
+	if !ok {
+		return
+	}
+	if event.ReadOnly {
+		respondError(c, errEventReadOnly.With("event", event.Slug))
+		return
+	}
+	if err := event.Config.CheckParticipantEdit(time.Now()); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req ParticipantChangeRequestPayload
+	if err := bindJSON(c, &req, func() { req.normalize(team.Country) }); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	changes := models.TeamChanges{
+		LeaderMobile:      req.LeaderMobile,
+		Members:           req.Members,
+		MentorName:        req.MentorName,
+		MentorEmail:       req.MentorEmail,
+		MentorMobile:      req.MentorMobile,
+		MentorInstitution: req.MentorInstitution,
+		MentorDesignation: req.MentorDesignation,
+		TopicName:         req.TopicName,
+		TopicDescription:  req.TopicDescription,
+		InstituteNOC:      req.InstituteNOC,
+		IDCardsPDF:        req.IDCardsPDF,
+		PresentationPPT:   req.PresentationPPT,
+	}
+	updateData, err := changes.UpdateData()
+	if err != nil || len(updateData) == 0 {
+		respondError(c, badRequest("No valid fields to update"))
+		return
+	}
+
+	// Blocked duplicates are rejected without naming the other team
+	if event.Config.Duplicates() == models.DuplicatesBlock && (req.LeaderMobile != nil || req.Members != nil) {
+		candidate := *team
+		if req.LeaderMobile != nil {
+			candidate.LeaderMobile = *req.LeaderMobile
+		}
+		if req.Members != nil {
+			candidate.Members = req.Members
+		}
+		if err := applyDuplicatePolicy(c.Request.Context(), h.DB, event, &candidate); err != nil {
+			if errors.Is(err, errDuplicateParticipant) {
+				err = errDuplicateParticipant
+			}
+			respondError(c, err)
+			return
+		}
+	}
+
+	// One open request at a time keeps the admin review queue unambiguous
+	pending, err := h.DB.GetChangeRequests(c.Request.Context(), 1, 0, bson.M{"teamRegistrationId": team.ID, "status": models.ChangeRequestPending})
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve change requests: %w", err))
+		return
+	}
+	if len(pending) > 0 {
+		respondError(c, models.ConflictError(models.CodeChangeRequestPending, "A change request is already awaiting review").With("changeRequest", pending[0]))
+		return
+	}
+
+	requestedBy, _ := c.Get("username")
+	requestedByEmail, _ := requestedBy.(string)
+
+	created, err := h.DB.CreateChangeRequest(c.Request.Context(), models.NewChangeRequest(team, requestedByEmail, changes))
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to create change request: %w", err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditChangeRequestCreate, models.AuditTargetChangeRequest, created.ID.Hex(), nil, created)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":       "Change request submitted for review",
+		"changeRequest": created,
+	})
+}
+
+// GetMyChangeRequests lists the participant's change requests
+// @Summary List own change requests
+// @Description List change requests submitted by the participant's team
+// @Tags participant
+// @Produce json
+// @Success 200 {object} ChangeRequestListResponse
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Participant session required"
+// @Failure 500 {object} Problem
+// @Security ParticipantAuth
+// @Router /api/v1/participant/change-requests [get]
+func (h *ParticipantHandler) GetMyChangeRequests(c *gin.Context) {
+	team, ok := h.currentTeam(c)
+	if !ok {
+		return
+	}
+
+	requests, err := h.DB.GetChangeRequests(c.Request.Context(), 0, 0, bson.M{"teamRegistrationId": team.ID})
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve change requests: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"changeRequests": requests})
+}
+
+// GetChangeRequests lists participant change requests for review
+// @Summary List change requests
+// @Description List participant change requests (admin only)
+// @Tags change-requests
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param status query string false "Filter by status (pending/approved/rejected)"
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: -createdAt)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Success 200 {object} ChangeRequestListResponse
+// @Failure 400 {object} Problem "Invalid paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/change-requests/ [get]
+func (h *ParticipantHandler) GetChangeRequests(c *gin.Context) {
+	q, err := listQuery(c, models.ChangeRequestSortFields, "-createdAt")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	filter := bson.M{"eventId": currentEvent(c).ID}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+
+	page, err := h.DB.ListChangeRequests(c.Request.Context(), filter, q)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve change requests: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changeRequests": page.Items,
+		"pagination":     pagination(q, page),
+	})
+}
+
+// ReviewChangeRequest approves or rejects a participant change request
+// @Summary Review change request
+// @Description Approve (and apply) or reject a participant change request (admin only). Approved changes are applied on top of later changes to other fields of the team registration. If a requested field itself changed since the request, approving fails with the conflicting fields and the request stays pending.
+// @Tags change-requests
+// @Accept json
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param id path string true "Change Request ID"
+// @Param review body ReviewChangeRequestRequest true "Decision"
+// @Success 200 {object} ChangeRequestEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 409 {object} Problem "Already reviewed, the event is read-only, or the team registration changed the requested fields since the request (current registration and fields included)"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/change-requests/{id}/action [put]
+func (h *ParticipantHandler) ReviewChangeRequest(c *gin.Context) {
+	var req ReviewChangeRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+	if req.Action == "reject" && strings.TrimSpace(req.Reason) == "" {
+		respondError(c, badRequest("Rejection reason is required"))
+		return
+	}
+
+	reviewer, _ := c.Get("username")
+	reviewerName, _ := reviewer.(string)
+
+	existing, err := h.DB.GetChangeRequestByID(c.Request.Context(), c.Param("id"))
+	if err == nil && existing.EventID != currentEvent(c).ID {
+		err = models.ErrChangeRequestNotFound
+	}
+	// Approved changes are applied to the team, which is audited as well
+	var team *models.TeamRegistration
+	if err == nil && req.Action == "approve" {
+		team, err = h.DB.GetTeamRegistrationByID(c.Request.Context(), existing.TeamRegistrationID.Hex())
+	}
+	var cr *models.ChangeRequest
+	if err == nil {
+		cr, err = h.DB.ReviewChangeRequest(c.Request.Context(), existing.ID.Hex(), req.Action == "approve", req.Reason, reviewerName)
+	}
+	if err != nil {
+		var conflict *models.Error
+		if errors.As(err, &conflict) && errors.Is(conflict, models.ErrChangeRequestConflict) {
+			if current, getErr := h.DB.GetTeamRegistrationByID(c.Request.Context(), existing.TeamRegistrationID.Hex()); getErr == nil {
+				respondStale(c, conflict, current.Version, current)
+				return
+			}
+		}
+		if errors.Is(err, models.ErrVersionConflict) {
+			respondTeamWriteError(c, h.DB, existing.TeamRegistrationID.Hex(), "review change request", err)
+			return
+		}
+		respondError(c, fmt.Errorf("failed to review change request: %w", err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditChangeRequestReview, models.AuditTargetChangeRequest, cr.ID.Hex(), existing, cr)
+	if team != nil {
+		if updated, err := h.DB.GetTeamRegistrationByID(c.Request.Context(), team.ID.Hex()); err == nil {
+			recordAudit(c, h.DB, models.AuditTeamUpdate, models.AuditTargetTeam, team.ID.Hex(), team, updated)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Change request " + req.Action + "d successfully",
+		"changeRequest": cr,
+	})
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// sentMail is an email captured by recordingMailer
+type sentMail struct {
+	to, subject, body string
+}
+
+// recordingMailer keeps the emails it is asked to send
+type recordingMailer struct {
+	sent []sentMail
+}
+
+func (m *recordingMailer) Send(to, subject, body string) error {
+	m.sent = append(m.sent, sentMail{to, subject, body})
+	return nil
+}
+
+var linkToken = regexp.MustCompile(`\?token=(\S+)`)
+
+func TestRequestMagicLink(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("emails a one-time link and stores only its hash", func(mt *mtest.T) {
+		team := models.TeamRegistration{
+			ID:                 primitive.NewObjectID(),
+			TeamName:           "Rocket",
+			RegistrationNumber: "IGC-0001",
+			LeaderName:         "Asha",
+			LeaderEmail:        "Asha@Example.org",
+		}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, team)),
+			mtest.CreateSuccessResponse(),
+		)
+		mailer := &recordingMailer{}
+		h := NewParticipantHandler(newTestDB(mt), mailer, "https://portal.example.org/login")
+
+		before := time.Now()
+		w := serve(h.RequestMagicLink, http.MethodPost, "/", MagicLinkRequest{Email: "asha@example.org"}, nil)
+		if w.Code != http.StatusOK {
+			mt.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+		if len(mailer.sent) != 1 || mailer.sent[0].to != team.LeaderEmail {
+			mt.Fatalf("sent %+v, want one email to %s", mailer.sent, team.LeaderEmail)
+		}
+		match := linkToken.FindStringSubmatch(mailer.sent[0].body)
+		if match == nil {
+			mt.Fatalf("email has no login link: %q", mailer.sent[0].body)
+		}
+
+		mt.GetStartedEvent() // team lookup
+		insert := mt.GetStartedEvent()
+		if insert.CommandName != "insert" {
+			mt.Fatalf("command = %s, want insert", insert.CommandName)
+		}
+		stored := insert.Command.Lookup("documents").Array().Index(0).Value().Document()
+		if got := stored.Lookup("tokenHash").StringValue(); got != hashToken(match[1]) {
+			mt.Errorf("stored token hash %q, want the hash of the emailed token", got)
+		}
+		if stored.Lookup("token").Type != 0 {
+			mt.Error("the raw token was stored")
+		}
+		if got := stored.Lookup("email").StringValue(); got != "asha@example.org" {
+			mt.Errorf("stored email %q, want it lowercased", got)
+		}
+		expires := stored.Lookup("expiresAt").Time()
+		if want := before.Add(settings.MagicLinkTTL); expires.Before(want.Add(-time.Second)) || expires.After(want.Add(time.Minute)) {
+			mt.Errorf("link expires at %v, want %v from now", expires, settings.MagicLinkTTL)
+		}
+	})
+
+	mt.Run("answers the same for unknown emails", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch))
+		mailer := &recordingMailer{}
+		h := NewParticipantHandler(newTestDB(mt), mailer, "https://portal.example.org/login")
+
+		w := serve(h.RequestMagicLink, http.MethodPost, "/", MagicLinkRequest{Email: "nobody@example.org"}, nil)
+		if w.Code != http.StatusOK {
+			mt.Errorf("status = %d, want 200", w.Code)
+		}
+		if len(mailer.sent) != 0 {
+			mt.Errorf("sent %d emails, want none", len(mailer.sent))
+		}
+	})
+}
+
+func TestVerifyMagicLink(t *testing.T) {
+	configure(t, func(s *Settings) { s.JWTSecret = "test-secret-0123456789" })
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("exchanges an unused, unexpired link for a participant session", func(mt *mtest.T) {
+		team := models.TeamRegistration{ID: primitive.NewObjectID(), TeamName: "Rocket", LeaderEmail: "asha@example.org"}
+		link := models.MagicLink{
+			ID:                 primitive.NewObjectID(),
+			TokenHash:          hashToken("secret-token"),
+			TeamRegistrationID: team.ID,
+			ExpiresAt:          time.Now().Add(time.Minute),
+		}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(mt, link)}),
+			mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, team)),
+		)
+		h := NewParticipantHandler(newTestDB(mt), &recordingMailer{}, "")
+
+		w := serve(h.VerifyMagicLink, http.MethodPost, "/", MagicLinkVerifyRequest{Token: " secret-token "}, nil)
+		if w.Code != http.StatusOK {
+			mt.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+
+		consume := mt.GetStartedEvent()
+		query := consume.Command.Lookup("query").Document()
+		if got := query.Lookup("tokenHash").StringValue(); got != link.TokenHash {
+			mt.Errorf("looked up token hash %q, want %q", got, link.TokenHash)
+		}
+		if _, err := query.LookupErr("usedAt", "$exists"); err != nil {
+			mt.Error("consumed links aren't excluded")
+		}
+		if _, err := query.LookupErr("expiresAt", "$gt"); err != nil {
+			mt.Error("expired links aren't excluded")
+		}
+		if _, err := consume.Command.LookupErr("update", "$set", "usedAt"); err != nil {
+			mt.Error("the link isn't marked as used")
+		}
+
+		token, _ := decode(mt, w)["token"].(string)
+		claims := jwt.MapClaims{}
+		if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return []byte(settings.JWTSecret), nil }); err != nil {
+			mt.Fatalf("parse session token: %v", err)
+		}
+		if claims["role"] != RoleParticipant || claims["team_id"] != team.ID.Hex() {
+			mt.Errorf("session claims %v, want a participant session of team %s", claims, team.ID.Hex())
+		}
+	})
+
+	mt.Run("rejects used or expired links", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+		h := NewParticipantHandler(newTestDB(mt), &recordingMailer{}, "")
+
+		w := serve(h.VerifyMagicLink, http.MethodPost, "/", MagicLinkVerifyRequest{Token: "expired-token"}, nil)
+		if w.Code != http.StatusUnauthorized {
+			mt.Errorf("status = %d, want 401", w.Code)
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/buildinfo"
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/Mastermind730/igc-admin-backend/workers"
+)
+
+// The types below document the JSON bodies the handlers write, for the OpenAPI
+// document; the handlers build the same shapes with gin.H.
+
+// Problem is the RFC 7807 body of every error response, written by
+// middleware.ErrorHandler. Some errors add members, e.g. the conflicting record;
+// requests with invalid fields list them under errors.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail"`
+	Instance  string              `json:"instance"`
+	Code      string              `json:"code"`
+	RequestID string              `json:"request_id,omitempty"`
+	Errors    []models.FieldError `json:"errors,omitempty"`
+}
+
+// MessageResponse is returned by operations that only confirm success
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// Pagination describes the page of a list response. Pass nextCursor as the cursor
+// parameter to get the next page; page is set when paging by page number.
+type Pagination struct {
+	Page       int64  `json:"page,omitempty"`
+	Limit      int64  `json:"limit"`
+	Total      int64  `json:"total"`
+	HasMore    bool   `json:"hasMore"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// LoginResponse is returned by a successful staff login
+type LoginResponse struct {
+	Message string       `json:"message"`
+	User    UserResponse `json:"user"`
+	Token   string       `json:"token"`
+}
+
+// UserEnvelope wraps a single user
+type UserEnvelope struct {
+	Message string       `json:"message,omitempty"`
+	User    UserResponse `json:"user"`
+}
+
+// UserListResponse is a page of users
+type UserListResponse struct {
+	Users      []UserResponse `json:"users"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// TeamEnvelope wraps a single team registration
+type TeamEnvelope struct {
+	Message string                   `json:"message,omitempty"`
+	Team    *models.TeamRegistration `json:"team"`
+}
+
+// TeamListResponse is a page of team registrations; track is set when filtered by track
+type TeamListResponse struct {
+	Teams      []*models.TeamRegistration `json:"teams"`
+	Track      string                     `json:"track,omitempty"`
+	Pagination Pagination                 `json:"pagination"`
+}
+
+// TeamSearchResponse is a page of the teams matching a search and the facet counts of
+// all matching teams
+type TeamSearchResponse struct {
+	Teams      []*models.TeamRegistration `json:"teams"`
+	Pagination Pagination                 `json:"pagination"`
+	Facets     *models.TeamFacets         `json:"facets"`
+}
+
+// DeletedUserResponse is a user in the trash
+type DeletedUserResponse struct {
+	ID        string     `json:"id"`
+	Username  string     `json:"username"`
+	Role      string     `json:"role"`
+	DeletedAt *time.Time `json:"deletedAt"`
+	DeletedBy string     `json:"deletedBy"`
+}
+
+// DeletedUserListResponse is a page of the users in the trash
+type DeletedUserListResponse struct {
+	Users      []DeletedUserResponse `json:"users"`
+	Pagination Pagination            `json:"pagination"`
+}
+
+// AuditLogResponse is a page of audit log entries
+type AuditLogResponse struct {
+	Entries    []*models.AuditEntry `json:"entries"`
+	Pagination Pagination           `json:"pagination"`
+}
+
+// TeamStatsResponse counts the registrations of an event by status
+type TeamStatsResponse struct {
+	Stats map[string]int64 `json:"stats"`
+}
+
+// EvaluationResultResponse is returned after a judge evaluated a team
+type EvaluationResultResponse struct {
+	Message    string                   `json:"message"`
+	Team       *models.TeamRegistration `json:"team"`
+	Evaluation *models.Evaluation       `json:"evaluation"`
+}
+
+// EvaluationListResponse is a page of the evaluations of an event
+type EvaluationListResponse struct {
+	Evaluations []*models.Evaluation `json:"evaluations"`
+	Pagination  Pagination           `json:"pagination"`
+}
+
+// BulkCertificateResponse reports the outcome of a bulk certificate run
+type BulkCertificateResponse struct {
+	Message      string                `json:"message"`
+	Teams        int                   `json:"teams"`
+	Created      int                   `json:"created"`
+	Existing     int                   `json:"existing"`
+	Certificates []*models.Certificate `json:"certificates"`
+}
+
+// CertificateListResponse lists issued certificates
+type CertificateListResponse struct {
+	Certificates []*models.Certificate `json:"certificates"`
+	Total        int                   `json:"total"`
+}
+
+// RegistrationVerificationResponse is the public view of a registration
+type RegistrationVerificationResponse struct {
+	Valid        bool                `json:"valid"`
+	Verification *PublicVerification `json:"verification"`
+}
+
+// CertificateVerificationResponse is the public view of a certificate and its team
+type CertificateVerificationResponse struct {
+	Valid        bool                `json:"valid"`
+	Certificate  PublicCertificate   `json:"certificate"`
+	Verification *PublicVerification `json:"verification"`
+}
+
+// ParticipantLoginResponse is returned when a login link is exchanged for a session
+type ParticipantLoginResponse struct {
+	Message string          `json:"message"`
+	Token   string          `json:"token"`
+	Team    ParticipantTeam `json:"team"`
+}
+
+// ParticipantTeam identifies the team of a participant session
+type ParticipantTeam struct {
+	ID                 string `json:"id"`
+	TeamName           string `json:"teamName"`
+	RegistrationNumber string `json:"registrationNumber"`
+}
+
+// ParticipantRegistrationResponse is a team leader's view of their registration
+type ParticipantRegistrationResponse struct {
+	Team                  *models.TeamRegistration `json:"team"`
+	Status                string                   `json:"status"`
+	RejectionReason       string                   `json:"rejectionReason"`
+	Video                 VideoStatus              `json:"video"`
+	EditDeadline          *time.Time               `json:"editDeadline"`
+	CanEdit               bool                     `json:"canEdit"`
+	PendingChangeRequests []*models.ChangeRequest  `json:"pendingChangeRequests"`
+}
+
+// VideoStatus reports whether and when the team submitted its video
+type VideoStatus struct {
+	Submitted   bool       `json:"submitted"`
+	Deadline    *time.Time `json:"deadline"`
+	Link        string     `json:"link,omitempty"`
+	SubmittedAt *time.Time `json:"submittedAt,omitempty"`
+	Late        bool       `json:"late,omitempty"`
+}
+
+// ChangeRequestEnvelope wraps a single change request
+type ChangeRequestEnvelope struct {
+	Message       string                `json:"message"`
+	ChangeRequest *models.ChangeRequest `json:"changeRequest"`
+}
+
+// ChangeRequestListResponse is a list of change requests, paged for admins
+type ChangeRequestListResponse struct {
+	ChangeRequests []*models.ChangeRequest `json:"changeRequests"`
+	Pagination     *Pagination             `json:"pagination,omitempty"`
+}
+
+// EventConfigResponse is the schedule and capacity of an event
+type EventConfigResponse struct {
+	Event            string             `json:"event"`
+	Config           models.EventConfig `json:"config"`
+	RegistrationOpen bool               `json:"registrationOpen"`
+	ServerTime       time.Time          `json:"serverTime"`
+}
+
+// EventConfigUpdateResponse is returned after the configuration was replaced
+type EventConfigUpdateResponse struct {
+	Message string             `json:"message"`
+	Event   string             `json:"event"`
+	Config  models.EventConfig `json:"config"`
+}
+
+// EventListResponse lists the events
+type EventListResponse struct {
+	Events []*models.Event `json:"events"`
+}
+
+// EventDetailResponse is an event and whether its registration is open
+type EventDetailResponse struct {
+	Event            *models.Event `json:"event"`
+	RegistrationOpen bool          `json:"registrationOpen"`
+	ServerTime       time.Time     `json:"serverTime"`
+}
+
+// EventEnvelope wraps a single event
+type EventEnvelope struct {
+	Message string        `json:"message"`
+	Event   *models.Event `json:"event"`
+}
+
+// CatalogListResponse lists catalogue entries under "tracks" or "programs"
+type CatalogListResponse struct {
+	Tracks   []*models.CatalogEntry `json:"tracks,omitempty"`
+	Programs []*models.CatalogEntry `json:"programs,omitempty"`
+}
+
+// CatalogEntryEnvelope wraps a catalogue entry under "track" or "program"
+type CatalogEntryEnvelope struct {
+	Message string               `json:"message,omitempty"`
+	Track   *models.CatalogEntry `json:"track,omitempty"`
+	Program *models.CatalogEntry `json:"program,omitempty"`
+}
+
+// IndexResponse is the welcome message of the API root
+type IndexResponse struct {
+	Message string `json:"message"`
+	Version string `json:"version"`
+	Docs    string `json:"docs"`
+}
+
+// StatusResponse reports that the API is running
+type StatusResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Version string `json:"version"`
+}
+
+// LivenessResponse is the body of the liveness probe
+type LivenessResponse struct {
+	Status string         `json:"status"`
+	Build  buildinfo.Info `json:"build"`
+}
+
+// ReadinessResponse is the body of the readiness probe; checks are keyed by
+// dependency (mongodb, indexes, workers)
+type ReadinessResponse struct {
+	Status string                    `json:"status"`
+	Checks map[string]ReadinessCheck `json:"checks"`
+	Build  buildinfo.Info            `json:"build"`
+}
+
+// ReadinessCheck is the result of one readiness check
+type ReadinessCheck struct {
+	Status  string           `json:"status"`
+	Error   string           `json:"error,omitempty"`
+	Missing []string         `json:"missing,omitempty"`
+	Workers []workers.Status `json:"workers,omitempty"`
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"time"
+)
+
+// Settings holds the handler behaviour configured at startup
+type Settings struct {
+	// JWTSecret signs admin, judge and participant tokens
+	JWTSecret           string
+	AdminTokenTTL       time.Duration
+	ParticipantTokenTTL time.Duration
+	// MagicLinkTTL is how long a participant login link stays valid
+	MagicLinkTTL time.Duration
+	// DefaultPageLimit and MaxPageLimit bound the limit query parameter of list endpoints
+	DefaultPageLimit int
+	MaxPageLimit     int
+}
+
+var settings = Settings{
+	AdminTokenTTL:       24 * time.Hour,
+	ParticipantTokenTTL: 12 * time.Hour,
+	MagicLinkTTL:        15 * time.Minute,
+	DefaultPageLimit:    10,
+	MaxPageLimit:        100,
+}
+
+// Configure applies the startup configuration; call it before serving requests
+func Configure(s Settings) {
+	settings = s
+}
@@ -1,12 +1,27 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Mastermind730/igc-admin-backend/models"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// errTrackNotOffered rejects tracks the event doesn't offer
+	errTrackNotOffered = invalidField("track", models.FieldInvalidChoice, "Is not offered by this event")
+	// errDuplicateParticipant rejects registrations sharing a participant with another team
+	errDuplicateParticipant = models.ConflictError(models.CodeDuplicateParticipant, "A participant is already registered in another team")
 )
 
 // TeamRegistrationHandler handles team registration API requests
@@ -23,57 +38,138 @@ func NewTeamRegistrationHandler(db *models.DatabaseService) *TeamRegistrationHan
 type CreateTeamRegistrationRequest struct {
 	TeamName          string              `json:"teamName" binding:"required,max=100"`
 	LeaderName        string              `json:"leaderName" binding:"required,max=100"`
-	LeaderEmail       string              `json:"leaderEmail" binding:"required,email"`
-	LeaderMobile      string              `json:"leaderMobile" binding:"required"`
-	LeaderGender      models.Gender       `json:"leaderGender" binding:"required"`
+	LeaderEmail       string              `json:"leaderEmail" binding:"required,email,lowercase"`
+	LeaderMobile      string              `json:"leaderMobile" binding:"required,e164"`
+	LeaderGender      models.Gender       `json:"leaderGender" binding:"required,oneof=male female other"`
 	Institution       string              `json:"institution" binding:"required,max=200"`
-	Program           models.Program      `json:"program" binding:"required"`
+	Program           models.Program      `json:"program" binding:"required,max=200"`
 	Country           string              `json:"country" binding:"required,max=100"`
 	State             string              `json:"state" binding:"required,max=100"`
-	Members           []models.TeamMember `json:"members"`
+	Members           []models.TeamMember `json:"members" binding:"required,min=1,max=4,dive"`
 	MentorName        string              `json:"mentorName" binding:"required,max=100"`
-	MentorEmail       string              `json:"mentorEmail" binding:"required,email"`
-	MentorMobile      string              `json:"mentorMobile" binding:"required"`
+	MentorEmail       string              `json:"mentorEmail" binding:"required,email,lowercase"`
+	MentorMobile      string              `json:"mentorMobile" binding:"required,e164"`
 	MentorInstitution string              `json:"mentorInstitution" binding:"required,max=200"`
 	MentorDesignation string              `json:"mentorDesignation" binding:"required,max=100"`
 	InstituteNOC      *models.DriveFile   `json:"instituteNOC,omitempty"`
 	IDCardsPDF        *models.DriveFile   `json:"idCardsPDF,omitempty"`
 	TopicName         string              `json:"topicName" binding:"required,max=200"`
 	TopicDescription  string              `json:"topicDescription" binding:"required"`
-	Track             models.Track        `json:"track" binding:"required"`
+	Track             models.Track        `json:"track" binding:"required,max=200"`
 	PresentationPPT   models.DriveFile    `json:"presentationPPT" binding:"required"`
 }
 
+// normalize rewrites the phone numbers to E.164 and trims and lowercases the emails
+func (r *CreateTeamRegistrationRequest) normalize() {
+	normalizeEmail(&r.LeaderEmail)
+	normalizeEmail(&r.MentorEmail)
+	normalizePhone(&r.LeaderMobile, r.Country)
+	normalizePhone(&r.MentorMobile, r.Country)
+	normalizeMembers(r.Members, r.Country)
+}
+
 // UpdateTeamRegistrationRequest represents the update team registration request payload
 type UpdateTeamRegistrationRequest struct {
 	TeamName          string              `json:"teamName,omitempty" binding:"omitempty,max=100"`
 	LeaderName        string              `json:"leaderName,omitempty" binding:"omitempty,max=100"`
-	LeaderEmail       string              `json:"leaderEmail,omitempty" binding:"omitempty,email"`
-	LeaderMobile      string              `json:"leaderMobile,omitempty"`
-	LeaderGender      *models.Gender      `json:"leaderGender,omitempty"`
+	LeaderEmail       string              `json:"leaderEmail,omitempty" binding:"omitempty,email,lowercase"`
+	LeaderMobile      string              `json:"leaderMobile,omitempty" binding:"omitempty,e164"`
+	LeaderGender      *models.Gender      `json:"leaderGender,omitempty" binding:"omitempty,oneof=male female other"`
 	Institution       string              `json:"institution,omitempty" binding:"omitempty,max=200"`
-	Program           *models.Program     `json:"program,omitempty"`
+	Program           *models.Program     `json:"program,omitempty" binding:"omitempty,min=1,max=200"`
 	Country           string              `json:"country,omitempty" binding:"omitempty,max=100"`
 	State             string              `json:"state,omitempty" binding:"omitempty,max=100"`
-	Members           []models.TeamMember `json:"members,omitempty"`
+	Members           []models.TeamMember `json:"members,omitempty" binding:"omitempty,min=1,max=4,dive"`
 	MentorName        string              `json:"mentorName,omitempty" binding:"omitempty,max=100"`
-	MentorEmail       string              `json:"mentorEmail,omitempty" binding:"omitempty,email"`
-	MentorMobile      string              `json:"mentorMobile,omitempty"`
+	MentorEmail       string              `json:"mentorEmail,omitempty" binding:"omitempty,email,lowercase"`
+	MentorMobile      string              `json:"mentorMobile,omitempty" binding:"omitempty,e164"`
 	MentorInstitution string              `json:"mentorInstitution,omitempty" binding:"omitempty,max=200"`
 	MentorDesignation string              `json:"mentorDesignation,omitempty" binding:"omitempty,max=100"`
 	InstituteNOC      *models.DriveFile   `json:"instituteNOC,omitempty"`
 	IDCardsPDF        *models.DriveFile   `json:"idCardsPDF,omitempty"`
 	TopicName         string              `json:"topicName,omitempty" binding:"omitempty,max=200"`
 	TopicDescription  string              `json:"topicDescription,omitempty"`
-	Track             *models.Track       `json:"track,omitempty"`
+	Track             *models.Track       `json:"track,omitempty" binding:"omitempty,min=1,max=200"`
 	PresentationPPT   *models.DriveFile   `json:"presentationPPT,omitempty"`
 }
 
+// normalize rewrites the phone numbers to E.164 and trims and lowercases the emails.
+// Numbers without a country code belong to country unless the update changes it.
+func (r *UpdateTeamRegistrationRequest) normalize(country string) {
+	if r.Country != "" {
+		country = r.Country
+	}
+	normalizeEmail(&r.LeaderEmail)
+	normalizeEmail(&r.MentorEmail)
+	normalizePhone(&r.LeaderMobile, country)
+	normalizePhone(&r.MentorMobile, country)
+	normalizeMembers(r.Members, country)
+}
+
 // ApproveRejectRequest represents the approve/reject request payload
 type ApproveRejectRequest struct {
-	Action     string `json:"action" binding:"required,oneof=approve reject"`
-	Reason     string `json:"reason,omitempty"`
-	ActionedBy string `json:"actionedBy" binding:"required"`
+	Action string `json:"action" binding:"required,oneof=approve reject"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// TeamSearchQuery is the query of a team search; every filter is optional and they combine
+type TeamSearchQuery struct {
+	Q           string `form:"q" json:"q" binding:"max=200"`
+	Status      string `form:"status" json:"status" binding:"omitempty,oneof=pending approved rejected waitlisted"`
+	Track       string `form:"track" json:"track" binding:"max=200"`
+	Program     string `form:"program" json:"program" binding:"max=200"`
+	Country     string `form:"country" json:"country" binding:"max=100"`
+	State       string `form:"state" json:"state" binding:"max=100"`
+	Institution string `form:"institution" json:"institution" binding:"max=200"`
+	GenderMix   string `form:"genderMix" json:"genderMix" binding:"omitempty,oneof=male female other mixed"`
+	MinSize     int    `form:"minSize" json:"minSize" binding:"omitempty,min=1,max=5"`
+	MaxSize     int    `form:"maxSize" json:"maxSize" binding:"omitempty,min=1,max=5"`
+	HasVideo    *bool  `form:"hasVideo" json:"hasVideo"`
+	Allocated   *bool  `form:"allocated" json:"allocated"`
+	JudgeID     string `form:"judgeId" json:"judgeId" binding:"omitempty,mongodb"`
+}
+
+// search returns the model search of the query
+func (q *TeamSearchQuery) search() *models.TeamSearch {
+	s := &models.TeamSearch{
+		Text:        strings.TrimSpace(q.Q),
+		Status:      models.RegistrationStatus(q.Status),
+		Track:       models.Track(q.Track),
+		Program:     models.Program(q.Program),
+		Country:     q.Country,
+		State:       q.State,
+		Institution: q.Institution,
+		GenderMix:   models.GenderMix(q.GenderMix),
+		MinSize:     q.MinSize,
+		MaxSize:     q.MaxSize,
+		HasVideo:    q.HasVideo,
+		Allocated:   q.Allocated,
+	}
+	s.JudgeID, _ = primitive.ObjectIDFromHex(q.JudgeID)
+	return s
+}
+
+// AnalyticsQuery selects the registrations covered by analytics
+type AnalyticsQuery struct {
+	Status string `form:"status" json:"status" binding:"omitempty,oneof=pending approved rejected waitlisted"`
+	From   string `form:"from" json:"from" binding:"omitempty,datetime=2006-01-02"`
+	To     string `form:"to" json:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// filter returns the model filter of the query; both dates are inclusive days in UTC
+func (q *AnalyticsQuery) filter() (*models.AnalyticsFilter, error) {
+	f := &models.AnalyticsFilter{Status: models.RegistrationStatus(q.Status)}
+	if q.From != "" {
+		f.From, _ = time.Parse(time.DateOnly, q.From)
+	}
+	if q.To != "" {
+		to, _ := time.Parse(time.DateOnly, q.To)
+		f.To = to.AddDate(0, 0, 1)
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return nil, invalidField("to", models.FieldOutOfRange, "Must not be before from")
+	}
+	return f, nil
 }
 
 // CreateTeamRegistration creates a new team registration
@@ -82,34 +178,60 @@ type ApproveRejectRequest struct {
 // @Tags team-registrations
 // @Accept json
 // @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
 // @Param teamData body CreateTeamRegistrationRequest true "Team registration data"
-// @Success 201 {object} models.TeamRegistration
-// @Failure 400 {object} gin.H
-// @Failure 409 {object} gin.H
-// @Router /api/team-registrations [post]
+// @Success 201 {object} TeamEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Registration is closed"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 409 {object} Problem "Team name exists, program is full, a participant is already in another team or the event is read-only"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/ [post]
 func (h *TeamRegistrationHandler) CreateTeamRegistration(c *gin.Context) {
 	var req CreateTeamRegistrationRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+	if err := bindJSON(c, &req, req.normalize); err != nil {
+		respondError(c, invalidRequest(err))
 		return
 	}
 
-	// Validate team size (1-4 members + leader)
-	validMembers := 0
-	for _, member := range req.Members {
-		if len(strings.TrimSpace(member.FullName)) > 0 {
-			validMembers++
-		}
+	// Reject submissions outside the registration window
+	event := currentEvent(c)
+	if err := event.Config.CheckRegistrationOpen(time.Now()); err != nil {
+		respondError(c, err)
+		return
 	}
-	if validMembers < 1 || validMembers > 4 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Team must have between 1-4 members (excluding leader)"})
+
+	// Tracks and programs must come from the catalogue; store their current names
+	track, err := resolveCatalogName(c.Request.Context(), h.DB, models.CatalogTrack, string(req.Track))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	req.Track = models.Track(track)
+	if !event.HasTrack(req.Track) {
+		respondError(c, errTrackNotOffered.With("track", req.Track))
+		return
+	}
+	program, err := resolveCatalogName(c.Request.Context(), h.DB, models.CatalogProgram, string(req.Program))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	req.Program = models.Program(program)
+	if full, err := h.programFull(c.Request.Context(), event, req.Program); err != nil {
+		respondError(c, fmt.Errorf("failed to check program capacity: %w", err))
+		return
+	} else if full {
+		respondError(c, models.ConflictError(models.CodeProgramFull, "Program is full").With("program", program))
 		return
 	}
 
 	// Check if team name already exists
-	existingTeam, _ := h.DB.GetTeamRegistrationByTeamName(req.TeamName)
+	existingTeam, _ := h.DB.GetTeamRegistrationByTeamName(c.Request.Context(), event.ID, req.TeamName)
 	if existingTeam != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Team name already exists"})
+		respondError(c, models.ConflictError(models.CodeTeamNameExists, "Team name already exists"))
 		return
 	}
 
@@ -137,12 +259,19 @@ func (h *TeamRegistrationHandler) CreateTeamRegistration(c *gin.Context) {
 	teamReg.Track = req.Track
 	teamReg.PresentationPPT = req.PresentationPPT
 
-	createdTeam, err := h.DB.CreateTeamRegistration(teamReg)
+	if err := applyDuplicatePolicy(c.Request.Context(), h.DB, event, teamReg); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	createdTeam, err := h.DB.CreateTeamRegistration(c.Request.Context(), teamReg, event)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create team registration", "details": err.Error()})
+		respondError(c, fmt.Errorf("failed to create team registration: %w", err))
 		return
 	}
+	recordAudit(c, h.DB, models.AuditTeamCreate, models.AuditTargetTeam, createdTeam.ID.Hex(), nil, createdTeam)
 
+	setETag(c, createdTeam.Version)
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Team registration created successfully",
 		"team":    createdTeam,
@@ -151,34 +280,32 @@ func (h *TeamRegistrationHandler) CreateTeamRegistration(c *gin.Context) {
 
 // GetTeamRegistration retrieves a team registration by ID
 // @Summary Get team registration by ID
-// @Description Get team registration information by ID
+// @Description Get team registration information by ID. The response carries the registration's ETag, to send as If-Match when changing it.
 // @Tags team-registrations
 // @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
 // @Param id path string true "Team Registration ID"
-// @Success 200 {object} models.TeamRegistration
-// @Failure 400 {object} gin.H
-// @Failure 404 {object} gin.H
-// @Router /api/team-registrations/{id} [get]
+// @Success 200 {object} TeamEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/{id} [get]
 func (h *TeamRegistrationHandler) GetTeamRegistration(c *gin.Context) {
-	teamID := c.Param("id")
-
-	team, err := h.DB.GetTeamRegistrationByID(teamID)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Team registration not found"})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team registration ID", "details": err.Error()})
-		}
+	team, ok := teamInEvent(c, h.DB, c.Param("id"))
+	if !ok {
 		return
 	}
 
 	// If team is approved, try to fetch submitted video link
 	if team.IsApproved() {
-		if link, err := h.DB.GetVideoLinkForTeam(team); err == nil {
+		if link, err := h.DB.GetVideoLinkForTeam(c.Request.Context(), team); err == nil {
 			team.VideoLink = link
 		}
 	}
 
+	setETag(c, team.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"team": team,
 	})
@@ -186,28 +313,36 @@ func (h *TeamRegistrationHandler) GetTeamRegistration(c *gin.Context) {
 
 // GetTeamRegistrationByRegNumber retrieves a team registration by registration number
 // @Summary Get team registration by registration number
-// @Description Get team registration information by registration number
+// @Description Get team registration information by registration number. The response carries the registration's ETag, to send as If-Match when changing it.
 // @Tags team-registrations
 // @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
 // @Param regNumber path string true "Registration Number"
-// @Success 200 {object} models.TeamRegistration
-// @Failure 404 {object} gin.H
-// @Router /api/team-registrations/reg/{regNumber} [get]
+// @Success 200 {object} TeamEnvelope
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/reg/{regNumber} [get]
 func (h *TeamRegistrationHandler) GetTeamRegistrationByRegNumber(c *gin.Context) {
 	regNumber := c.Param("regNumber")
 
-	team, err := h.DB.GetTeamRegistrationByRegistrationNumber(regNumber)
+	team, err := h.DB.GetTeamRegistrationByRegistrationNumber(c.Request.Context(), regNumber)
+	if err == nil && team.EventID != currentEvent(c).ID {
+		err = models.ErrTeamNotFound
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Team registration not found"})
+		respondError(c, fmt.Errorf("failed to retrieve team registration: %w", err))
 		return
 	}
 
 	if team.IsApproved() {
-		if link, err := h.DB.GetVideoLinkForTeam(team); err == nil {
+		if link, err := h.DB.GetVideoLinkForTeam(c.Request.Context(), team); err == nil {
 			team.VideoLink = link
 		}
 	}
 
+	setETag(c, team.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"team": team,
 	})
@@ -215,35 +350,28 @@ func (h *TeamRegistrationHandler) GetTeamRegistrationByRegNumber(c *gin.Context)
 
 // GetAllTeamRegistrations retrieves all team registrations with pagination and filtering
 // @Summary Get all team registrations
-// @Description Get all team registrations with optional pagination and filtering
+// @Description Get the team registrations that submitted a video, with optional pagination and filtering
 // @Tags team-registrations
 // @Produce json
-// @Param page query int false "Page number (default: 1)"
-// @Param limit query int false "Items per page (default: 10)"
-// @Param status query string false "Filter by status (pending/approved/rejected)"
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: -submittedAt)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Param status query string false "Filter by status (pending/approved/rejected/waitlisted, default: approved)"
 // @Param track query string false "Filter by track"
 // @Param institution query string false "Filter by institution"
-// @Success 200 {array} models.TeamRegistration
-// @Router /api/team-registrations [get]
+// @Success 200 {object} TeamListResponse
+// @Failure 400 {object} Problem "Invalid paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/ [get]
 func (h *TeamRegistrationHandler) GetAllTeamRegistrations(c *gin.Context) {
-	// Parse query parameters
-	page := 1
-	limit := 10
-
-	if pageStr := c.Query("page"); pageStr != "" {
-		if p := parseInt(pageStr); p > 0 {
-			page = p
-		}
-	}
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l := parseInt(limitStr); l > 0 && l <= 100 {
-			limit = l
-		}
-	}
-
 	// Build filter
-	filter := bson.M{}
+	filter := bson.M{"eventId": currentEvent(c).ID}
 	// Default to returning only approved teams unless a status is explicitly provided
 	if status := c.Query("status"); status != "" {
 		filter["registrationStatus"] = status
@@ -256,127 +384,172 @@ func (h *TeamRegistrationHandler) GetAllTeamRegistrations(c *gin.Context) {
 	}
 
 	if institution := c.Query("institution"); institution != "" {
-		filter["institution"] = bson.M{"$regex": institution, "$options": "i"}
-	}
-
-	skip := int64((page - 1) * limit)
-	teams, err := h.DB.GetAllTeamRegistrations(int64(limit), skip, filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team registrations", "details": err.Error()})
-		return
-	}
-
-	// Populate video link (by registration number or teamId/teamName) and keep only teams that provided a video
-	filtered := make([]*models.TeamRegistration, 0, len(teams))
-	for _, t := range teams {
-		if t == nil {
-			continue
-		}
-		if link, err := h.DB.GetVideoLinkForTeam(t); err == nil && link != "" {
-			t.VideoLink = link
-			filtered = append(filtered, t)
-		}
+		filter["institution"] = bson.M{"$regex": regexp.QuoteMeta(institution), "$options": "i"}
 	}
-	teams = filtered
 
-	// Set total to the number of teams after filtering by video presence for accurate client display
-	total := len(teams)
-
-	c.JSON(http.StatusOK, gin.H{
-		"teams": teams,
-		"pagination": gin.H{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
-	})
+	h.listTeams(c, filter, gin.H{})
 }
 
 // GetTeamRegistrationsByTrack retrieves teams by track
 // @Summary Get team registrations by track
-// @Description Get team registrations filtered by track
+// @Description Get the approved team registrations of a track that submitted a video
 // @Tags team-registrations
 // @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
 // @Param track path string true "Track name"
-// @Param page query int false "Page number (default: 1)"
-// @Param limit query int false "Items per page (default: 10)"
-// @Success 200 {array} models.TeamRegistration
-// @Router /api/team-registrations/track/{track} [get]
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: -submittedAt)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Success 200 {object} TeamListResponse
+// @Failure 400 {object} Problem "Invalid paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/track/{track} [get]
 func (h *TeamRegistrationHandler) GetTeamRegistrationsByTrack(c *gin.Context) {
 	track := models.Track(c.Param("track"))
 
-	page := 1
-	limit := 10
+	// Return only approved teams for track listings
+	filter := bson.M{"eventId": currentEvent(c).ID, "track": track, "registrationStatus": models.StatusApproved}
+	h.listTeams(c, filter, gin.H{"track": track})
+}
+
+// listTeams responds with a page of the teams matching filter that submitted a video,
+// adding extra to the response
+func (h *TeamRegistrationHandler) listTeams(c *gin.Context, filter bson.M, extra gin.H) {
+	q, err := listQuery(c, models.TeamSortFields, "-submittedAt")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
-	if pageStr := c.Query("page"); pageStr != "" {
-		if p := parseInt(pageStr); p > 0 {
-			page = p
-		}
+	withVideo, err := h.DB.VideoSubmittedFilter(c.Request.Context())
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve team registrations: %w", err))
+		return
 	}
+	filter["$and"] = bson.A{withVideo}
 
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l := parseInt(limitStr); l > 0 && l <= 100 {
-			limit = l
+	page, err := h.DB.ListTeamRegistrations(c.Request.Context(), filter, q)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve team registrations: %w", err))
+		return
+	}
+	for _, t := range page.Items {
+		if link, err := h.DB.GetVideoLinkForTeam(c.Request.Context(), t); err == nil {
+			t.VideoLink = link
 		}
 	}
 
-	skip := int64((page - 1) * limit)
-	// Return only approved teams for track listings
-	filter := bson.M{"track": track, "registrationStatus": models.StatusApproved}
-	teams, err := h.DB.GetAllTeamRegistrations(int64(limit), skip, filter)
+	extra["teams"] = page.Items
+	extra["pagination"] = pagination(q, page)
+	c.JSON(http.StatusOK, extra)
+}
+
+// SearchTeams searches the teams of an event
+// @Summary Search team registrations
+// @Description Search the teams by text over team, topic, leader and member names and the topic description, with combinable filters (admin only). Facets count the matching teams by the value of each filter.
+// @Tags team-registrations
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param q query string false "Text to search for"
+// @Param status query string false "Filter by status (pending/approved/rejected/waitlisted)"
+// @Param track query string false "Filter by track"
+// @Param program query string false "Filter by program"
+// @Param country query string false "Filter by country"
+// @Param state query string false "Filter by state"
+// @Param institution query string false "Filter by institution (case-insensitive substring)"
+// @Param genderMix query string false "Filter by the genders of the participants (male/female/other/mixed)"
+// @Param minSize query int false "Minimum number of participants, leader included"
+// @Param maxSize query int false "Maximum number of participants, leader included"
+// @Param hasVideo query bool false "Filter by whether the team submitted a video"
+// @Param allocated query bool false "Filter by whether the team is allocated to a judge"
+// @Param judgeId query string false "Filter by allocated judge ID"
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: -submittedAt)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Success 200 {object} TeamSearchResponse
+// @Failure 400 {object} Problem "Invalid filters or paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/search [get]
+func (h *TeamRegistrationHandler) SearchTeams(c *gin.Context) {
+	var query TeamSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondError(c, invalidRequest(err))
+		return
+	}
+	if query.MinSize > 0 && query.MaxSize > 0 && query.MinSize > query.MaxSize {
+		respondError(c, invalidField("maxSize", models.FieldOutOfRange, "Must be at least minSize"))
+		return
+	}
+	q, err := listQuery(c, models.TeamSortFields, "-submittedAt")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve team registrations", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	// Keep only teams that provided a video (by registration number or teamId/teamName)
-	filtered := make([]*models.TeamRegistration, 0, len(teams))
-	for _, t := range teams {
-		if t == nil {
-			continue
-		}
-		if link, err := h.DB.GetVideoLinkForTeam(t); err == nil && link != "" {
+	page, facets, err := h.DB.SearchTeamRegistrations(c.Request.Context(), currentEvent(c).ID, query.search(), q)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to search team registrations: %w", err))
+		return
+	}
+	for _, t := range page.Items {
+		if link, err := h.DB.GetVideoLinkForTeam(c.Request.Context(), t); err == nil {
 			t.VideoLink = link
-			filtered = append(filtered, t)
 		}
 	}
-	teams = filtered
 
-	c.JSON(http.StatusOK, gin.H{
-		"teams": teams,
-		"track": track,
+	c.JSON(http.StatusOK, TeamSearchResponse{
+		Teams:      page.Items,
+		Pagination: pagination(q, page),
+		Facets:     facets,
 	})
 }
 
 // UpdateTeamRegistration updates an existing team registration
 // @Summary Update team registration
-// @Description Update team registration information
+// @Description Update team registration information. Send the ETag of the registration the changes are based on as If-Match; the response carries the ETag of the updated registration.
 // @Tags team-registrations
 // @Accept json
 // @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
 // @Param id path string true "Team Registration ID"
+// @Param If-Match header string false "ETag of the registration the changes are based on"
 // @Param teamData body UpdateTeamRegistrationRequest true "Updated team data"
-// @Success 200 {object} models.TeamRegistration
-// @Failure 400 {object} gin.H
-// @Failure 404 {object} gin.H
-// @Router /api/team-registrations/{id} [put]
+// @Success 200 {object} TeamEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 409 {object} Problem "A participant is already in another team, the registration changed concurrently (current registration included) or the event is read-only"
+// @Failure 412 {object} Problem "If-Match doesn't match the current registration (current registration included)"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/{id} [put]
 func (h *TeamRegistrationHandler) UpdateTeamRegistration(c *gin.Context) {
 	teamID := c.Param("id")
 
-	var req UpdateTeamRegistrationRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+	// Check if team exists
+	existingTeam, ok := teamInEvent(c, h.DB, teamID)
+	if !ok {
+		return
+	}
+	if !ifMatch(c, existingTeam.Version) {
+		respondStale(c, models.ErrVersionMismatch, existingTeam.Version, existingTeam)
 		return
 	}
 
-	// Check if team exists
-	_, err := h.DB.GetTeamRegistrationByID(teamID)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Team registration not found"})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team registration ID", "details": err.Error()})
-		}
+	var req UpdateTeamRegistrationRequest
+	if err := bindJSON(c, &req, func() { req.normalize(existingTeam.Country) }); err != nil {
+		respondError(c, invalidRequest(err))
 		return
 	}
 
@@ -401,7 +574,12 @@ func (h *TeamRegistrationHandler) UpdateTeamRegistration(c *gin.Context) {
 		updateData["institution"] = req.Institution
 	}
 	if req.Program != nil {
-		updateData["program"] = *req.Program
+		program, err := resolveCatalogName(c.Request.Context(), h.DB, models.CatalogProgram, string(*req.Program))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		updateData["program"] = program
 	}
 	if req.Country != "" {
 		updateData["country"] = req.Country
@@ -440,23 +618,62 @@ func (h *TeamRegistrationHandler) UpdateTeamRegistration(c *gin.Context) {
 		updateData["topicDescription"] = req.TopicDescription
 	}
 	if req.Track != nil {
-		updateData["track"] = *req.Track
+		track, err := resolveCatalogName(c.Request.Context(), h.DB, models.CatalogTrack, string(*req.Track))
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if !currentEvent(c).HasTrack(models.Track(track)) {
+			respondError(c, errTrackNotOffered.With("track", track))
+			return
+		}
+		updateData["track"] = track
 	}
 	if req.PresentationPPT != nil {
 		updateData["presentationPPT"] = req.PresentationPPT
 	}
 
+	// Changed participants are checked against the other teams again
+	if req.LeaderEmail != "" || req.LeaderMobile != "" || req.Members != nil {
+		candidate := *existingTeam
+		if req.LeaderEmail != "" {
+			candidate.LeaderEmail = req.LeaderEmail
+		}
+		if req.LeaderMobile != "" {
+			candidate.LeaderMobile = req.LeaderMobile
+		}
+		if req.Members != nil {
+			candidate.Members = req.Members
+		}
+		if err := applyDuplicatePolicy(c.Request.Context(), h.DB, currentEvent(c), &candidate); err != nil {
+			respondError(c, err)
+			return
+		}
+		if currentEvent(c).Config.Duplicates() == models.DuplicatesFlag {
+			updateData["duplicates"] = candidate.Duplicates
+		}
+	}
+
 	if len(updateData) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No valid fields to update"})
+		respondError(c, badRequest("No valid fields to update"))
 		return
 	}
 
-	updatedTeam, err := h.DB.UpdateTeamRegistration(teamID, updateData)
+	updatedTeam, err := h.DB.UpdateTeamRegistration(c.Request.Context(), teamID, existingTeam.Version, updateData)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team registration", "details": err.Error()})
+		respondTeamWriteError(c, h.DB, teamID, "update team registration", err)
 		return
 	}
+	recordAudit(c, h.DB, models.AuditTeamUpdate, models.AuditTargetTeam, teamID, existingTeam, updatedTeam)
+
+	// An approved team moving to another track frees a slot in its old track
+	if existingTeam.IsApproved() && updatedTeam.Track != existingTeam.Track {
+		if _, err := h.DB.PromoteWaitlisted(c.Request.Context(), existingTeam.EventID, existingTeam.Track); err != nil {
+			slog.ErrorContext(c.Request.Context(), "failed to promote waitlisted teams", "error", err, "track", existingTeam.Track)
+		}
+	}
 
+	setETag(c, updatedTeam.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Team registration updated successfully",
 		"team":    updatedTeam,
@@ -465,91 +682,127 @@ func (h *TeamRegistrationHandler) UpdateTeamRegistration(c *gin.Context) {
 
 // ApproveOrRejectTeamRegistration approves or rejects a team registration
 // @Summary Approve or reject team registration
-// @Description Approve or reject a team registration (admin only)
+// @Description Approve or reject a team registration (admin only). The decision is recorded under the authenticated admin. Send the ETag of the registration the decision is based on as If-Match.
 // @Tags team-registrations
 // @Accept json
 // @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
 // @Param id path string true "Team Registration ID"
+// @Param If-Match header string false "ETag of the registration the decision is based on"
 // @Param actionData body ApproveRejectRequest true "Action data"
-// @Success 200 {object} models.TeamRegistration
-// @Failure 400 {object} gin.H
-// @Failure 404 {object} gin.H
-// @Router /api/team-registrations/{id}/action [put]
+// @Success 200 {object} TeamEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 409 {object} Problem "The registration changed concurrently (current registration included) or the event is read-only"
+// @Failure 412 {object} Problem "If-Match doesn't match the current registration (current registration included)"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/{id}/action [put]
 func (h *TeamRegistrationHandler) ApproveOrRejectTeamRegistration(c *gin.Context) {
 	teamID := c.Param("id")
 
 	var req ApproveRejectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		respondError(c, invalidRequest(err))
+		return
+	}
+
+	existingTeam, ok := teamInEvent(c, h.DB, teamID)
+	if !ok {
+		return
+	}
+	if !ifMatch(c, existingTeam.Version) {
+		respondStale(c, models.ErrVersionMismatch, existingTeam.Version, existingTeam)
 		return
 	}
 
 	var updatedTeam *models.TeamRegistration
 	var err error
+	action := models.AuditTeamApprove
 
+	// The actor is taken from the token, never from the request
 	if req.Action == "approve" {
-		updatedTeam, err = h.DB.ApproveTeamRegistration(teamID, req.ActionedBy)
+		updatedTeam, err = h.DB.ApproveTeamRegistration(c.Request.Context(), teamID, existingTeam.Version, actorName(c))
 	} else if req.Action == "reject" {
 		if req.Reason == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Rejection reason is required"})
+			respondError(c, badRequest("Rejection reason is required"))
 			return
 		}
-		updatedTeam, err = h.DB.RejectTeamRegistration(teamID, req.Reason, req.ActionedBy)
+		action = models.AuditTeamReject
+		updatedTeam, err = h.DB.RejectTeamRegistration(c.Request.Context(), teamID, existingTeam.Version, req.Reason, actorName(c))
 	}
 
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Team registration not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team registration", "details": err.Error()})
-		}
+		respondTeamWriteError(c, h.DB, teamID, "update team registration", err)
 		return
 	}
+	recordAudit(c, h.DB, action, models.AuditTargetTeam, teamID, existingTeam, updatedTeam)
 
+	message := "Team registration " + req.Action + "d successfully"
+	if updatedTeam.IsWaitlisted() {
+		message = "Track is at capacity; team registration added to the waitlist"
+	}
+
+	setETag(c, updatedTeam.Version)
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Team registration " + req.Action + "d successfully",
+		"message": message,
 		"team":    updatedTeam,
 	})
 }
 
 // DeleteTeamRegistration deletes a team registration by ID
 // @Summary Delete team registration
-// @Description Delete a team registration by ID (admin only)
+// @Description Move a team registration to the trash, from where it can be restored until the retention period ends; its registration number is never reused (admin only)
 // @Tags team-registrations
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
 // @Param id path string true "Team Registration ID"
-// @Success 200 {object} gin.H
-// @Failure 400 {object} gin.H
-// @Failure 404 {object} gin.H
-// @Router /api/team-registrations/{id} [delete]
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 409 {object} Problem "Event is read-only"
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/{id} [delete]
 func (h *TeamRegistrationHandler) DeleteTeamRegistration(c *gin.Context) {
 	teamID := c.Param("id")
+	existingTeam, ok := teamInEvent(c, h.DB, teamID)
+	if !ok {
+		return
+	}
 
-	err := h.DB.DeleteTeamRegistration(teamID)
+	deletedTeam, err := h.DB.DeleteTeamRegistration(c.Request.Context(), teamID, actorName(c))
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Team registration not found"})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team registration ID", "details": err.Error()})
-		}
+		respondError(c, fmt.Errorf("failed to delete team registration: %w", err))
 		return
 	}
+	recordAudit(c, h.DB, models.AuditTeamDelete, models.AuditTargetTeam, teamID, existingTeam, deletedTeam)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Team registration deleted successfully",
+		"message": "Team registration moved to the trash",
 	})
 }
 
 // GetTeamRegistrationStats retrieves registration statistics
 // @Summary Get team registration statistics
-// @Description Get statistics about team registrations
+// @Description Count the registrations of an event by status
 // @Tags team-registrations
 // @Produce json
-// @Success 200 {object} gin.H
-// @Router /api/team-registrations/stats [get]
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Success 200 {object} TeamStatsResponse
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/stats [get]
 func (h *TeamRegistrationHandler) GetTeamRegistrationStats(c *gin.Context) {
-	stats, err := h.DB.GetTeamRegistrationStats()
+	stats, err := h.DB.GetTeamRegistrationStats(c.Request.Context(), currentEvent(c).ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve statistics", "details": err.Error()})
+		respondError(c, fmt.Errorf("failed to retrieve statistics: %w", err))
 		return
 	}
 
@@ -557,3 +810,142 @@ func (h *TeamRegistrationHandler) GetTeamRegistrationStats(c *gin.Context) {
 		"stats": stats,
 	})
 }
+
+// GetRegistrationAnalytics breaks the registrations of an event down
+// @Summary Get registration analytics
+// @Description Break the registrations down by status, track, institution, program, country and state, with the gender ratio of leaders and members, the team size distribution, the video submission rate and the daily registrations (admin only)
+// @Tags team-registrations
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param status query string false "Filter by status (pending/approved/rejected/waitlisted)"
+// @Param from query string false "First submission day, YYYY-MM-DD (UTC)"
+// @Param to query string false "Last submission day, YYYY-MM-DD (UTC)"
+// @Success 200 {object} models.RegistrationAnalytics
+// @Failure 400 {object} Problem "Invalid filters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/analytics [get]
+func (h *TeamRegistrationHandler) GetRegistrationAnalytics(c *gin.Context) {
+	analytics, err := h.registrationAnalytics(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// ExportRegistrationAnalytics downloads the registration analytics as CSV
+// @Summary Export registration analytics
+// @Description Download the registration analytics as CSV, one row per breakdown value with its count and share (admin only)
+// @Tags team-registrations
+// @Produce text/csv
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param status query string false "Filter by status (pending/approved/rejected/waitlisted)"
+// @Param from query string false "First submission day, YYYY-MM-DD (UTC)"
+// @Param to query string false "Last submission day, YYYY-MM-DD (UTC)"
+// @Success 200 {file} file "CSV with the columns breakdown, value, count and share"
+// @Failure 400 {object} Problem "Invalid filters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/analytics/export [get]
+func (h *TeamRegistrationHandler) ExportRegistrationAnalytics(c *gin.Context) {
+	analytics, err := h.registrationAnalytics(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(analytics.CSVRecords()); err != nil {
+		respondError(c, fmt.Errorf("failed to write analytics: %w", err))
+		return
+	}
+
+	name := fmt.Sprintf("registration-analytics-%s-%s.csv", currentEvent(c).Slug, time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// registrationAnalytics computes the analytics selected by the request's query
+func (h *TeamRegistrationHandler) registrationAnalytics(c *gin.Context) (*models.RegistrationAnalytics, error) {
+	var query AnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, invalidRequest(err)
+	}
+	filter, err := query.filter()
+	if err != nil {
+		return nil, err
+	}
+
+	analytics, err := h.DB.GetRegistrationAnalytics(c.Request.Context(), currentEvent(c).ID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute registration analytics: %w", err)
+	}
+	return analytics, nil
+}
+
+// GetDuplicateReport lists people registered in more than one team
+// @Summary Get duplicate participant report
+// @Description List the emails and phone numbers shared by participants of several teams, and the teams involved (admin only). Rejected registrations are ignored.
+// @Tags team-registrations
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Success 200 {object} models.DuplicateReport
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/duplicates [get]
+func (h *TeamRegistrationHandler) GetDuplicateReport(c *gin.Context) {
+	report, err := h.DB.GetDuplicateReport(c.Request.Context(), currentEvent(c).ID)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to build duplicate report: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// applyDuplicatePolicy checks the participants of team against the other teams of the
+// event: under the block policy matches are rejected, under flag they are recorded on team
+func applyDuplicatePolicy(ctx context.Context, db *models.DatabaseService, event *models.Event, team *models.TeamRegistration) error {
+	policy := event.Config.Duplicates()
+	if policy == models.DuplicatesAllow {
+		return nil
+	}
+	matches, err := db.FindDuplicateParticipants(ctx, event.ID, team)
+	if err != nil {
+		return fmt.Errorf("failed to check for duplicate participants: %w", err)
+	}
+	if len(matches) > 0 && policy == models.DuplicatesBlock {
+		return errDuplicateParticipant.With("duplicates", matches)
+	}
+	team.Duplicates = matches
+	return nil
+}
+
+// programFull reports whether a program has reached its registration capacity in an event
+func (h *TeamRegistrationHandler) programFull(ctx context.Context, event *models.Event, program models.Program) (bool, error) {
+	entry, err := h.DB.FindCatalogEntry(ctx, models.CatalogProgram, string(program))
+	if err != nil || entry.Capacity == 0 {
+		return false, err
+	}
+	count, err := h.DB.CountTeamRegistrationsWithFilter(ctx, bson.M{
+		"eventId":            event.ID,
+		"program":            entry.Name,
+		"registrationStatus": bson.M{"$ne": models.StatusRejected},
+	})
+	if err != nil {
+		return false, err
+	}
+	return count >= int64(entry.Capacity), nil
+}
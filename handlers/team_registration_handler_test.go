@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// inEvent sets the event resolved by the event scope middleware
+func inEvent(event *models.Event) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Set(eventContextKey, event)
+	}
+}
+
+func TestTeamSearchQuery(t *testing.T) {
+	judgeID := primitive.NewObjectID()
+	yes := true
+	q := TeamSearchQuery{
+		Q:         "  solar drone ",
+		Status:    "approved",
+		Track:     "AI",
+		GenderMix: "mixed",
+		MinSize:   2,
+		HasVideo:  &yes,
+		JudgeID:   judgeID.Hex(),
+	}
+	got := q.search()
+	if got.Text != "solar drone" || got.Status != models.StatusApproved || got.Track != "AI" ||
+		got.GenderMix != models.GenderMixMixed || got.MinSize != 2 || got.HasVideo != &yes || got.JudgeID != judgeID {
+		t.Errorf("search = %+v", got)
+	}
+	if (&TeamSearchQuery{}).search().JudgeID != primitive.NilObjectID {
+		t.Error("an empty judge filter matches a judge")
+	}
+}
+
+func TestSearchTeams(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	event := &models.Event{ID: primitive.NewObjectID(), Slug: "igc-2026"}
+
+	invalid := []struct {
+		name  string
+		query string
+	}{
+		{"unknown status", "status=archived"},
+		{"unknown gender mix", "genderMix=unknown"},
+		{"size out of range", "minSize=9"},
+		{"inverted size range", "minSize=4&maxSize=2"},
+		{"invalid judge", "judgeId=nobody"},
+		{"unknown sort", "sort=leaderEmail"},
+	}
+	for _, tt := range invalid {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			h := NewTeamRegistrationHandler(newTestDB(mt))
+			w := serve(h.SearchTeams, http.MethodGet, "/api/v1/team-registrations/search?"+tt.query, nil, inEvent(event))
+			if w.Code != http.StatusBadRequest {
+				mt.Errorf("status = %d, want 400: %s", w.Code, w.Body)
+			}
+			if started := mt.GetStartedEvent(); started != nil {
+				mt.Errorf("invalid search sent %s", started.CommandName)
+			}
+		})
+	}
+}
+
+func TestAnalyticsQueryFilter(t *testing.T) {
+	f, err := (&AnalyticsQuery{Status: "approved", From: "2026-03-01", To: "2026-03-07"}).filter()
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	if f.Status != models.StatusApproved || !f.From.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) ||
+		!f.To.Equal(time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("filter = %+v, want approved teams submitted 1-7 March inclusive", f)
+	}
+
+	if _, err := (&AnalyticsQuery{From: "2026-03-07", To: "2026-03-07"}).filter(); err != nil {
+		t.Errorf("a single day is rejected: %v", err)
+	}
+	if _, err := (&AnalyticsQuery{From: "2026-03-08", To: "2026-03-07"}).filter(); err == nil {
+		t.Error("an inverted range is accepted")
+	}
+}
+
+func TestExportRegistrationAnalytics(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	event := &models.Event{ID: primitive.NewObjectID(), Slug: "igc-2026"}
+
+	mt.Run("downloads the analytics as CSV", func(mt *mtest.T) {
+		for range 8 {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{}}))
+		}
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns("teamregistrations"), mtest.FirstBatch, doc(mt, bson.M{
+			"status": bson.A{bson.M{"_id": "approved", "count": 2}, bson.M{"_id": "pending", "count": 2}},
+		})))
+
+		h := NewTeamRegistrationHandler(newTestDB(mt))
+		w := serve(h.ExportRegistrationAnalytics, http.MethodGet, "/api/v1/team-registrations/analytics/export?status=", nil, inEvent(event))
+		if w.Code != http.StatusOK {
+			mt.Fatalf("status = %d, want 200: %s", w.Code, w.Body)
+		}
+		if got := w.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+			mt.Errorf("Content-Type = %q, want CSV", got)
+		}
+		if got := w.Header().Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="registration-analytics-igc-2026-`) {
+			mt.Errorf("Content-Disposition = %q", got)
+		}
+		records, err := csv.NewReader(w.Body).ReadAll()
+		if err != nil {
+			mt.Fatalf("read CSV: %v", err)
+		}
+		want := [][]string{
+			{"breakdown", "value", "count", "share"},
+			{"total", "", "4", "1.0000"},
+			{"status", "approved", "2", "0.5000"},
+			{"status", "pending", "2", "0.5000"},
+		}
+		if len(records) < len(want) || !reflect.DeepEqual(records[:len(want)], want) {
+			mt.Errorf("CSV starts with %q, want %q", records, want)
+		}
+	})
+
+	mt.Run("rejects invalid filters before querying", func(mt *mtest.T) {
+		h := NewTeamRegistrationHandler(newTestDB(mt))
+		for _, query := range []string{"from=March", "status=archived", "from=2026-03-08&to=2026-03-01"} {
+			w := serve(h.GetRegistrationAnalytics, http.MethodGet, "/api/v1/team-registrations/analytics?"+query, nil, inEvent(event))
+			if w.Code != http.StatusBadRequest {
+				mt.Errorf("%s: status = %d, want 400", query, w.Code)
+			}
+		}
+		if started := mt.GetStartedEvent(); started != nil {
+			mt.Errorf("invalid filters sent %s", started.CommandName)
+		}
+	})
+}
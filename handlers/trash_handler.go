@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// TrashHandler lists and restores deleted users and team registrations
+type TrashHandler struct {
+	DB *models.DatabaseService
+}
+
+// NewTrashHandler creates a new TrashHandler
+func NewTrashHandler(db *models.DatabaseService) *TrashHandler {
+	return &TrashHandler{DB: db}
+}
+
+// GetDeletedTeams lists the team registrations of an event in the trash
+// @Summary List deleted team registrations
+// @Description List the team registrations of an event in the trash, most recently deleted first by default (admin only)
+// @Tags trash
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: -deletedAt)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Success 200 {object} TeamListResponse
+// @Failure 400 {object} Problem "Invalid paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/trash/teams [get]
+func (h *TrashHandler) GetDeletedTeams(c *gin.Context) {
+	q, err := listQuery(c, models.TrashSortFields, "-deletedAt")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, err := h.DB.ListDeletedTeamRegistrations(c.Request.Context(), currentEvent(c).ID, q)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve deleted team registrations: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"teams":      page.Items,
+		"pagination": pagination(q, page),
+	})
+}
+
+// RestoreTeam takes a team registration out of the trash
+// @Summary Restore team registration
+// @Description Restore a deleted team registration of an event with its original registration number. A team that was approved goes to the waitlist if its track has filled up since (admin only)
+// @Tags trash
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param id path string true "Team Registration ID"
+// @Success 200 {object} TeamEnvelope
+// @Failure 400 {object} Problem "Invalid ID"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or deleted team registration not found"
+// @Failure 409 {object} Problem "Team name taken since, or event is read-only"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/trash/teams/{id}/restore [post]
+func (h *TrashHandler) RestoreTeam(c *gin.Context) {
+	teamID := c.Param("id")
+	eventID := currentEvent(c).ID
+	deletedTeam, err := h.DB.GetDeletedTeamRegistration(c.Request.Context(), eventID, teamID)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve deleted team registration: %w", err))
+		return
+	}
+
+	team, err := h.DB.RestoreTeamRegistration(c.Request.Context(), eventID, teamID)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to restore team registration: %w", err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditTeamRestore, models.AuditTargetTeam, teamID, deletedTeam, team)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Team registration restored successfully",
+		"team":    team,
+	})
+}
+
+// GetDeletedUsers lists the users in the trash
+// @Summary List deleted users
+// @Description List the users in the trash, most recently deleted first by default (admin only)
+// @Tags trash
+// @Produce json
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: -deletedAt)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Success 200 {object} DeletedUserListResponse
+// @Failure 400 {object} Problem "Invalid paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/trash/users [get]
+func (h *TrashHandler) GetDeletedUsers(c *gin.Context) {
+	q, err := listQuery(c, models.TrashSortFields, "-deletedAt")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, err := h.DB.ListDeletedUsers(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve deleted users: %w", err))
+		return
+	}
+
+	// Convert to response format (without passwords)
+	response := make([]DeletedUserResponse, 0, len(page.Items))
+	for _, user := range page.Items {
+		response = append(response, DeletedUserResponse{
+			ID:        user.ID.Hex(),
+			Username:  user.Username,
+			Role:      user.Role,
+			DeletedAt: user.DeletedAt,
+			DeletedBy: user.DeletedBy,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":      response,
+		"pagination": pagination(q, page),
+	})
+}
+
+// RestoreUser takes a user out of the trash
+// @Summary Restore user
+// @Description Restore a deleted user, who can log in again (admin only)
+// @Tags trash
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} UserEnvelope
+// @Failure 400 {object} Problem "Invalid ID"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Deleted user not found"
+// @Failure 409 {object} Problem "Username taken since"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/trash/users/{id}/restore [post]
+func (h *TrashHandler) RestoreUser(c *gin.Context) {
+	userID := c.Param("id")
+	deletedUser, err := h.DB.GetDeletedUser(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to retrieve deleted user: %w", err))
+		return
+	}
+
+	user, err := h.DB.RestoreUser(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to restore user: %w", err))
+		return
+	}
+	recordAudit(c, h.DB, models.AuditUserRestore, models.AuditTargetUser, userID, deletedUser, user)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User restored successfully",
+		"user": UserResponse{
+			ID:       user.ID.Hex(),
+			Username: user.Username,
+		},
+	})
+}
@@ -1,17 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
+	"github.com/Mastermind730/igc-admin-backend/logging"
 	"github.com/Mastermind730/igc-admin-backend/models"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // UserHandler handles user-related API requests
@@ -48,24 +50,23 @@ type UpdateUserRequest struct {
 	Password string `json:"password,omitempty" binding:"omitempty,min=6"`
 }
 
-// UserResponse represents the user response (without password)
-type UserResponse struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
+// TeamAllocationRequest assigns a team to a judge of the event's judge pool
+type TeamAllocationRequest struct {
+	JudgeID string `json:"judgeId" binding:"required"`
 }
 
-func init() {
-	_ = godotenv.Load()
+// EvaluateTeamRequest is a judge's decision on an allocated team; scores are keyed by
+// rubric criterion
+type EvaluateTeamRequest struct {
+	Decision string             `json:"decision" binding:"required,oneof=approve reject"`
+	Reason   string             `json:"reason"`
+	Scores   map[string]float64 `json:"scores"`
 }
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
-
-func getJWTSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "supersecretkey" // fallback for demo
-	}
-	return secret
+// UserResponse represents the user response (without password)
+type UserResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
 }
 
 // GenerateJWT generates a JWT token for a user
@@ -74,79 +75,153 @@ func GenerateJWT(user *models.User) (string, error) {
 		"user_id":  user.ID.Hex(),
 		"username": user.Username,
 		"role":     user.Role,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(), // 24h expiry
+		"exp":      time.Now().Add(settings.AdminTokenTTL).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(settings.JWTSecret))
+}
+
+// GenerateParticipantJWT generates a JWT token for a team leader's portal session
+func GenerateParticipantJWT(team *models.TeamRegistration) (string, error) {
+	claims := jwt.MapClaims{
+		"team_id":  team.ID.Hex(),
+		"username": team.LeaderEmail,
+		"role":     RoleParticipant,
+		"exp":      time.Now().Add(settings.ParticipantTokenTTL).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return token.SignedString([]byte(settings.JWTSecret))
 }
 
-// JWTAuthMiddleware validates JWT token and sets user info in context
+// RoleParticipant is the JWT role of team leaders logged in through a magic link
+const RoleParticipant = "participant"
+
+// parseJWTClaims validates the bearer token and returns its claims.
+// It aborts the request and returns false when the token is missing or invalid.
+func parseJWTClaims(c *gin.Context) (jwt.MapClaims, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		respondError(c, models.UnauthorizedError(models.CodeTokenInvalid, "Missing or invalid Authorization header"))
+		return nil, false
+	}
+	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(settings.JWTSecret), nil
+	})
+	if err != nil || !token.Valid {
+		respondError(c, models.UnauthorizedError(models.CodeTokenInvalid, "Invalid or expired token"))
+		return nil, false
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		respondError(c, models.UnauthorizedError(models.CodeTokenInvalid, "Invalid token claims"))
+		return nil, false
+	}
+
+	// Attribute the request's log records to the caller (participants are identified by team)
+	userID, _ := claims["user_id"].(string)
+	if userID == "" {
+		userID, _ = claims["team_id"].(string)
+	}
+	role, _ := claims["role"].(string)
+	logging.SetUser(c.Request.Context(), userID, role)
+	return claims, true
+}
+
+// JWTAuthMiddleware validates a staff (admin/judge) JWT token and sets user info in context
 func JWTAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid Authorization header"})
+		claims, ok := parseJWTClaims(c)
+		if !ok {
 			return
 		}
-		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return jwtSecret, nil
-		})
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		if claims["role"] == RoleParticipant {
+			respondError(c, models.ForbiddenError(models.CodeStaffSessionRequired, "Participant sessions cannot access this resource"))
 			return
 		}
-		claims, ok := token.Claims.(jwt.MapClaims)
+		c.Set("user_id", claims["user_id"])
+		c.Set("username", claims["username"])
+		c.Set("role", claims["role"])
+		c.Next()
+	}
+}
+
+// ParticipantAuthMiddleware validates a team leader's portal JWT and sets the team in context
+func ParticipantAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := parseJWTClaims(c)
 		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
 			return
 		}
-		c.Set("user_id", claims["user_id"])
+		if claims["role"] != RoleParticipant {
+			respondError(c, models.ForbiddenError(models.CodeParticipantSessionRequired, "Participant session required"))
+			return
+		}
+		c.Set("team_id", claims["team_id"])
 		c.Set("username", claims["username"])
 		c.Set("role", claims["role"])
 		c.Next()
 	}
 }
 
+// RequireRole aborts requests whose JWT role is not one of the given roles.
+// It must run after JWTAuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+		respondError(c, errInsufficientPermissions)
+	}
+}
+
 // Login handles user authentication
 // @Summary Login user
-// @Description Authenticate user with username and password
+// @Description Authenticate an admin or judge with username and password
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param loginData body LoginRequest true "Login credentials"
-// @Success 200 {object} UserResponse
-// @Failure 400 {object} gin.H
-// @Failure 401 {object} gin.H
-// @Router /api/auth/login [post]
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Invalid credentials"
+// @Failure 500 {object} Problem
+// @Router /api/v1/auth/login [post]
 func (h *UserHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Get user by username
-	user, err := h.DB.GetUserByUsername(req.Username)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+	user, err := h.DB.GetUserByUsername(c.Request.Context(), req.Username)
+	if errors.Is(err, models.ErrNotFound) {
+		respondError(c, errInvalidCredentials)
+		return
+	} else if err != nil {
+		respondError(c, fmt.Errorf("failed to look up user: %w", err))
 		return
 	}
 
 	// In a real application, you would hash and compare passwords
 	// For now, we'll do a simple comparison (NOT SECURE - implement proper hashing)
 	if user.Password != req.Password {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		respondError(c, errInvalidCredentials)
 		return
 	}
 
 	// Generate JWT token
 	token, err := GenerateJWT(user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		respondError(c, fmt.Errorf("failed to generate token: %w", err))
 		return
 	}
 
@@ -165,26 +240,30 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 // CreateUser creates a new user (admin only)
 // @Summary Create a new user
-// @Description Create a new admin user
+// @Description Create an admin or judge account
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param userData body UnifiedCreateUserRequest true "User data"
-// @Success 201 {object} UserResponse
-// @Failure 400 {object} gin.H
-// @Failure 409 {object} gin.H
-// @Router /api/users [post]
+// @Success 201 {object} UserEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 409 {object} Problem "Username already exists"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/users/ [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req UnifiedCreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Check if user already exists
-	existingUser, _ := h.DB.GetUserByUsername(req.Username)
+	existingUser, _ := h.DB.GetUserByUsername(c.Request.Context(), req.Username)
 	if existingUser != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "User already exists"})
+		respondError(c, models.ConflictError(models.CodeUserExists, "User already exists"))
 		return
 	}
 
@@ -192,7 +271,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	judgeID := ""
 	if req.Role == "judge" {
 		if req.Name == "" || req.Organization == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Judge must have name and organization"})
+			respondError(c, badRequest("Judge must have name and organization"))
 			return
 		}
 		judgeID = "JUDGE-" + generateRandomID()
@@ -205,11 +284,12 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	newUser := models.NewUser(req.Username, req.Password)
 	newUser.Role = req.Role
 	// Optionally, extend User model to store Name, Organization, JudgeID
-	createdUser, err := h.DB.CreateUser(newUser)
+	createdUser, err := h.DB.CreateUser(c.Request.Context(), newUser)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user", "details": err.Error()})
+		respondError(c, fmt.Errorf("failed to create user: %w", err))
 		return
 	}
+	recordAudit(c, h.DB, models.AuditUserCreate, models.AuditTargetUser, createdUser.ID.Hex(), nil, createdUser)
 
 	response := gin.H{
 		"id":       createdUser.ID.Hex(),
@@ -231,24 +311,24 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 
 // GetUser retrieves a user by ID
 // @Summary Get user by ID
-// @Description Get user information by user ID
+// @Description Get user information by user ID. The response carries the user's ETag, to send as If-Match when changing it.
 // @Tags users
 // @Produce json
 // @Param id path string true "User ID"
-// @Success 200 {object} UserResponse
-// @Failure 400 {object} gin.H
-// @Failure 404 {object} gin.H
-// @Router /api/users/{id} [get]
+// @Success 200 {object} UserEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
 	userID := c.Param("id")
 
-	user, err := h.DB.GetUserByID(userID)
+	user, err := h.DB.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID", "details": err.Error()})
-		}
+		respondError(c, err)
 		return
 	}
 
@@ -258,6 +338,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		Username: user.Username,
 	}
 
+	setETag(c, user.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"user": response,
 	})
@@ -268,88 +349,81 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // @Description Get all users with optional pagination
 // @Tags users
 // @Produce json
-// @Param page query int false "Page number (default: 1)"
-// @Param limit query int false "Items per page (default: 10)"
-// @Success 200 {array} UserResponse
-// @Router /api/users [get]
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: username)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Success 200 {object} UserListResponse
+// @Failure 400 {object} Problem "Invalid paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/users/ [get]
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	// Parse query parameters
-	page := 1
-	limit := 10
-
-	if pageStr := c.Query("page"); pageStr != "" {
-		if p := parseInt(pageStr); p > 0 {
-			page = p
-		}
-	}
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l := parseInt(limitStr); l > 0 && l <= 100 {
-			limit = l
-		}
+	q, err := listQuery(c, models.UserSortFields, "username")
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	skip := int64((page - 1) * limit)
-	users, err := h.DB.GetAllUsers(int64(limit), skip)
+	page, err := h.DB.ListUsers(c.Request.Context(), q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve users", "details": err.Error()})
+		respondError(c, fmt.Errorf("failed to retrieve users: %w", err))
 		return
 	}
 
 	// Convert to response format (without passwords)
-	var response []UserResponse
-	for _, user := range users {
+	response := make([]UserResponse, 0, len(page.Items))
+	for _, user := range page.Items {
 		response = append(response, UserResponse{
 			ID:       user.ID.Hex(),
 			Username: user.Username,
 		})
 	}
 
-	// Get total count
-	total, err := h.DB.CountUsers()
-	if err != nil {
-		total = 0
-	}
-
 	c.JSON(http.StatusOK, gin.H{
-		"users": response,
-		"pagination": gin.H{
-			"page":  page,
-			"limit": limit,
-			"total": total,
-		},
+		"users":      response,
+		"pagination": pagination(q, page),
 	})
 }
 
 // UpdateUser updates an existing user
 // @Summary Update user
-// @Description Update user information
+// @Description Update user information. Send the ETag of the user the changes are based on as If-Match; the response carries the ETag of the updated user.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
+// @Param If-Match header string false "ETag of the user the changes are based on"
 // @Param userData body UpdateUserRequest true "Updated user data"
-// @Success 200 {object} UserResponse
-// @Failure 400 {object} gin.H
-// @Failure 404 {object} gin.H
-// @Router /api/users/{id} [put]
+// @Success 200 {object} UserEnvelope
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem "Username already exists, or the user changed concurrently (current user included)"
+// @Failure 412 {object} Problem "If-Match doesn't match the current user (current user included)"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	userID := c.Param("id")
 
 	var req UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Check if user exists
-	existingUser, err := h.DB.GetUserByID(userID)
+	existingUser, err := h.DB.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID", "details": err.Error()})
-		}
+		respondError(c, err)
+		return
+	}
+	if !ifMatch(c, existingUser.Version) {
+		respondStale(c, models.ErrVersionMismatch, existingUser.Version, UserResponse{ID: existingUser.ID.Hex(), Username: existingUser.Username})
 		return
 	}
 
@@ -357,8 +431,8 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	updateData := bson.M{}
 	if req.Username != "" && req.Username != existingUser.Username {
 		// Check if new username already exists
-		if existingUserWithUsername, _ := h.DB.GetUserByUsername(req.Username); existingUserWithUsername != nil {
-			c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+		if existingUserWithUsername, _ := h.DB.GetUserByUsername(c.Request.Context(), req.Username); existingUserWithUsername != nil {
+			respondError(c, models.ConflictError(models.CodeUserExists, "Username already exists"))
 			return
 		}
 		updateData["username"] = req.Username
@@ -368,15 +442,22 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	}
 
 	if len(updateData) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No valid fields to update"})
+		respondError(c, badRequest("No valid fields to update"))
 		return
 	}
 
-	updatedUser, err := h.DB.UpdateUser(userID, updateData)
+	updatedUser, err := h.DB.UpdateUser(c.Request.Context(), userID, existingUser.Version, updateData)
+	if errors.Is(err, models.ErrVersionConflict) {
+		if current, getErr := h.DB.GetUserByID(c.Request.Context(), userID); getErr == nil {
+			respondStale(c, models.ErrVersionConflict, current.Version, UserResponse{ID: current.ID.Hex(), Username: current.Username})
+			return
+		}
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user", "details": err.Error()})
+		respondError(c, fmt.Errorf("failed to update user: %w", err))
 		return
 	}
+	recordAudit(c, h.DB, models.AuditUserUpdate, models.AuditTargetUser, userID, existingUser, updatedUser)
 
 	// Return updated user data (without password)
 	response := UserResponse{
@@ -384,6 +465,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		Username: updatedUser.Username,
 	}
 
+	setETag(c, updatedUser.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "User updated successfully",
 		"user":    response,
@@ -392,60 +474,36 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 // DeleteUser deletes a user by ID
 // @Summary Delete user
-// @Description Delete a user by ID
+// @Description Move a user to the trash, from where it can be restored until the retention period ends; a deleted user can't log in
 // @Tags users
+// @Produce json
 // @Param id path string true "User ID"
-// @Success 200 {object} gin.H
-// @Failure 400 {object} gin.H
-// @Failure 404 {object} gin.H
-// @Router /api/users/{id} [delete]
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	userID := c.Param("id")
 
-	err := h.DB.DeleteUser(userID)
+	existingUser, err := h.DB.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID", "details": err.Error()})
-		}
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "User deleted successfully",
-	})
-}
-
-// CreateDefaultAdmin creates a default admin user
-func (h *UserHandler) CreateDefaultAdmin(c *gin.Context) {
-	username := os.Getenv("ADMIN_USERNAME")
-	password := os.Getenv("ADMIN_PASSWORD")
-
-	// Check if admin already exists
-	existingUser, _ := h.DB.GetUserByUsername(username)
-	if existingUser != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Admin user already exists"})
+		respondError(c, err)
 		return
 	}
 
-	// Create new admin user
-	newUser := models.NewUser(username, password)
-	newUser.Role = "admin"
-	createdUser, err := h.DB.CreateUser(newUser)
+	deletedUser, err := h.DB.DeleteUser(c.Request.Context(), userID, actorName(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create admin user", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
+	recordAudit(c, h.DB, models.AuditUserDelete, models.AuditTargetUser, userID, existingUser, deletedUser)
 
-	response := UserResponse{
-		ID:       createdUser.ID.Hex(),
-		Username: createdUser.Username,
-	}
-
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Admin user created successfully",
-		"user":    response,
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User moved to the trash",
 	})
 }
 
@@ -460,14 +518,14 @@ type CreateJudgeRequest struct {
 func (h *UserHandler) CreateJudge(c *gin.Context) {
 	var req CreateJudgeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 
 	// Check if judge already exists by email
-	existingUser, _ := h.DB.GetUserByUsername(req.Email)
+	existingUser, _ := h.DB.GetUserByUsername(c.Request.Context(), req.Email)
 	if existingUser != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Judge with this email already exists"})
+		respondError(c, models.ConflictError(models.CodeUserExists, "Judge with this email already exists"))
 		return
 	}
 
@@ -480,11 +538,12 @@ func (h *UserHandler) CreateJudge(c *gin.Context) {
 	// Add extra fields to user model if needed (Name, Organization)
 	// For now, store in Username and add judgeID to a custom field if you extend the model
 
-	createdUser, err := h.DB.CreateUser(newUser)
+	createdUser, err := h.DB.CreateUser(c.Request.Context(), newUser)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create judge", "details": err.Error()})
+		respondError(c, fmt.Errorf("failed to create judge: %w", err))
 		return
 	}
+	recordAudit(c, h.DB, models.AuditUserCreate, models.AuditTargetUser, createdUser.ID.Hex(), nil, createdUser)
 
 	response := gin.H{
 		"id":           createdUser.ID.Hex(),
@@ -502,100 +561,211 @@ func (h *UserHandler) CreateJudge(c *gin.Context) {
 	})
 }
 
-// TeamAllocationRequest for admin to allocate team to judge
-// judgeId is the ObjectID hex string of the judge user
-// teamId is the ObjectID hex string of the team
-// Only admin can call this
-// Route: PUT /api/v1/team-registrations/:id/allocate
-// Body: { "judgeId": "..." }
+// AllocateTeamToJudge assigns a team to a judge of the event's judge pool (admin only)
+// @Summary Allocate team to judge
+// @Description Assign a team to a judge of the event's judge pool (admin only). Send the ETag of the registration the allocation is based on as If-Match.
+// @Tags team-registrations
+// @Accept json
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param id path string true "Team Registration ID"
+// @Param If-Match header string false "ETag of the registration the allocation is based on"
+// @Param allocation body TeamAllocationRequest true "Judge to allocate"
+// @Success 200 {object} TeamEnvelope
+// @Failure 400 {object} Problem "Invalid request or judge"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 409 {object} Problem "The registration changed concurrently (current registration included) or the event is read-only"
+// @Failure 412 {object} Problem "If-Match doesn't match the current registration (current registration included)"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/{id}/allocate [put]
 func (h *UserHandler) AllocateTeamToJudge(c *gin.Context) {
 	teamId := c.Param("id")
-	var req struct {
-		JudgeId string `json:"judgeId" binding:"required"`
-	}
+	var req TeamAllocationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		respondError(c, invalidRequest(err))
 		return
 	}
 	// Only admin can allocate
 	role, _ := c.Get("role")
 	if role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only admin can allocate teams"})
+		respondError(c, models.ForbiddenError(models.CodeInsufficientPermissions, "Only admin can allocate teams"))
+		return
+	}
+	team, ok := teamInEvent(c, h.DB, teamId)
+	if !ok {
+		return
+	}
+	if !ifMatch(c, team.Version) {
+		respondStale(c, models.ErrVersionMismatch, team.Version, team)
+		return
+	}
+	judge, err := h.DB.GetUserByID(c.Request.Context(), req.JudgeID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) && !errors.Is(err, models.ErrInvalidID) {
+		respondError(c, fmt.Errorf("failed to look up judge: %w", err))
+		return
+	}
+	if err != nil || judge.Role != "judge" {
+		respondError(c, models.ValidationError(models.CodeUserNotFound, "Judge not found"))
+		return
+	}
+	if !currentEvent(c).HasJudge(judge.ID) {
+		respondError(c, models.ValidationError(models.CodeJudgeNotInPool, "Judge is not part of this event's judge pool"))
 		return
 	}
 	// Update team with allocated judge
-	update := bson.M{"allocatedJudgeId": req.JudgeId}
-	updatedTeam, err := h.DB.UpdateTeamRegistration(teamId, update)
+	update := bson.M{"allocatedJudgeId": judge.ID}
+	updatedTeam, err := h.DB.UpdateTeamRegistration(c.Request.Context(), teamId, team.Version, update)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to allocate team", "details": err.Error()})
+		respondTeamWriteError(c, h.DB, teamId, "allocate team", err)
 		return
 	}
+	recordAudit(c, h.DB, models.AuditTeamAllocate, models.AuditTargetTeam, teamId, team, updatedTeam)
+	setETag(c, updatedTeam.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "Team allocated to judge", "team": updatedTeam})
 }
 
-// Judge can view teams allocated to them
-// Route: GET /api/v1/team-registrations/allocated
+// GetAllocatedTeamsForJudge lists the teams allocated to the logged-in judge that submitted a video
+// @Summary List allocated teams
+// @Description List the teams allocated to the logged-in judge that submitted a video (judges only)
+// @Tags team-registrations
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param limit query int false "Items per page (default: 10, configurable)"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default: -submittedAt)"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param page query int false "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"
+// @Success 200 {object} TeamListResponse
+// @Failure 400 {object} Problem "Invalid paging parameters"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/allocated [get]
 func (h *UserHandler) GetAllocatedTeamsForJudge(c *gin.Context) {
 	role, _ := c.Get("role")
 	userId, _ := c.Get("user_id")
 	if role != "judge" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only judges can view allocated teams"})
+		respondError(c, models.ForbiddenError(models.CodeInsufficientPermissions, "Only judges can view allocated teams"))
 		return
 	}
-	filter := bson.M{"allocatedJudgeId": userId}
-	teams, err := h.DB.GetAllTeamRegistrations(100, 0, filter)
+	q, err := listQuery(c, models.TeamSortFields, "-submittedAt")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get allocated teams", "details": err.Error()})
+		respondError(c, err)
 		return
 	}
-	// Keep only allocated teams that have submitted a video (match by registration number)
-	filtered := make([]*models.TeamRegistration, 0, len(teams))
-	for _, t := range teams {
-		if t == nil {
-			continue
-		}
-		if link, _, ok, err := h.DB.FindVideoByRegistration(t.RegistrationNumber); err == nil && ok && link != "" {
+	id, _ := userId.(string)
+	judgeID, _ := primitive.ObjectIDFromHex(id)
+
+	// Only allocated teams that have submitted a video
+	withVideo, err := h.DB.VideoSubmittedFilter(c.Request.Context())
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to get allocated teams: %w", err))
+		return
+	}
+	filter := bson.M{"eventId": currentEvent(c).ID, "allocatedJudgeId": judgeID, "$and": bson.A{withVideo}}
+	page, err := h.DB.ListTeamRegistrations(c.Request.Context(), filter, q)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to get allocated teams: %w", err))
+		return
+	}
+	for _, t := range page.Items {
+		if link, err := h.DB.GetVideoLinkForTeam(c.Request.Context(), t); err == nil {
 			t.VideoLink = link
-			filtered = append(filtered, t)
 		}
 	}
-	c.JSON(http.StatusOK, gin.H{"teams": filtered})
+	c.JSON(http.StatusOK, gin.H{"teams": page.Items, "pagination": pagination(q, page)})
 }
 
-// Judge can approve or reject allocated team
-// Route: PUT /api/v1/team-registrations/:id/evaluate
-// Body: { "decision": "approve"|"reject", "reason": "..." }
+// JudgeEvaluateTeam lets a judge approve or reject an allocated team, optionally scoring it
+// against the event's rubric
+// @Summary Evaluate team
+// @Description Approve or reject an allocated team, optionally scoring it against the event's rubric (judges only). Send the ETag of the registration the evaluation is based on as If-Match.
+// @Tags team-registrations
+// @Accept json
+// @Produce json
+// @Param event query string false "Event slug or ID (default: active event)"
+// @Param id path string true "Team Registration ID"
+// @Param If-Match header string false "ETag of the registration the evaluation is based on"
+// @Param evaluation body EvaluateTeamRequest true "Decision and rubric scores"
+// @Success 200 {object} EvaluationResultResponse
+// @Failure 400 {object} Problem "Invalid request or scores"
+// @Failure 401 {object} Problem "Missing or invalid token"
+// @Failure 403 {object} Problem "Insufficient permissions"
+// @Failure 404 {object} Problem "Event or resource not found"
+// @Failure 409 {object} Problem "The registration changed concurrently (current registration included) or the event is read-only"
+// @Failure 412 {object} Problem "If-Match doesn't match the current registration (current registration included)"
+// @Failure 500 {object} Problem
+// @Security BearerAuth
+// @Router /api/v1/team-registrations/{id}/evaluate [put]
 func (h *UserHandler) JudgeEvaluateTeam(c *gin.Context) {
 	teamId := c.Param("id")
 	role, _ := c.Get("role")
 	userId, _ := c.Get("user_id")
 	if role != "judge" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only judges can evaluate teams"})
+		respondError(c, models.ForbiddenError(models.CodeInsufficientPermissions, "Only judges can evaluate teams"))
 		return
 	}
-	var req struct {
-		Decision string `json:"decision" binding:"required,oneof=approve reject"`
-		Reason   string `json:"reason"`
-	}
+	var req EvaluateTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+		respondError(c, invalidRequest(err))
+		return
+	}
+	judgeID, _ := userId.(string)
+	team, ok := teamInEvent(c, h.DB, teamId)
+	if !ok {
+		return
+	}
+	if !ifMatch(c, team.Version) {
+		respondStale(c, models.ErrVersionMismatch, team.Version, team)
 		return
 	}
-	update := bson.M{"actionedBy": userId}
+	event := currentEvent(c)
+	judgeOID, _ := primitive.ObjectIDFromHex(judgeID)
+	if !event.HasJudge(judgeOID) {
+		respondError(c, models.ForbiddenError(models.CodeJudgeNotInPool, "Judge is not part of this event's judge pool"))
+		return
+	}
+	total, err := event.ScoreEvaluation(req.Scores)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	// Go through the approval workflow so track capacity and the waitlist apply
+	var updatedTeam *models.TeamRegistration
 	if req.Decision == "approve" {
-		update["registrationStatus"] = "approved"
-		update["approvedAt"] = time.Now()
+		updatedTeam, err = h.DB.ApproveTeamRegistration(c.Request.Context(), teamId, team.Version, actorName(c))
 	} else {
-		update["registrationStatus"] = "rejected"
-		update["rejectedAt"] = time.Now()
-		update["rejectionReason"] = req.Reason
+		updatedTeam, err = h.DB.RejectTeamRegistration(c.Request.Context(), teamId, team.Version, req.Reason, actorName(c))
 	}
-	updatedTeam, err := h.DB.UpdateTeamRegistration(teamId, update)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update team status", "details": err.Error()})
+		respondTeamWriteError(c, h.DB, teamId, "update team status", err)
+		return
+	}
+	evaluation, err := h.DB.CreateEvaluation(c.Request.Context(), &models.Evaluation{
+		EventID:            event.ID,
+		TeamRegistrationID: team.ID,
+		JudgeID:            judgeOID,
+		Decision:           req.Decision,
+		Reason:             req.Reason,
+		Scores:             req.Scores,
+		Total:              total,
+	})
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to record evaluation: %w", err))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Team evaluation updated", "team": updatedTeam})
+	action := models.AuditTeamApprove
+	if req.Decision != "approve" {
+		action = models.AuditTeamReject
+	}
+	recordAudit(c, h.DB, action, models.AuditTargetTeam, teamId, team, updatedTeam)
+	recordAudit(c, h.DB, models.AuditTeamEvaluate, models.AuditTargetEvaluation, evaluation.ID.Hex(), nil, evaluation)
+	setETag(c, updatedTeam.Version)
+	c.JSON(http.StatusOK, gin.H{"message": "Team evaluation updated", "team": updatedTeam, "evaluation": evaluation})
 }
 
 // generateRandomID generates a random string for judge ID
@@ -609,19 +779,3 @@ func generateRandomID() string {
 	}
 	return string(b)
 }
-
-// Helper function to parse integer from string
-func parseInt(s string) int {
-	if s == "" {
-		return 0
-	}
-
-	result := 0
-	for _, char := range s {
-		if char < '0' || char > '9' {
-			return 0
-		}
-		result = result*10 + int(char-'0')
-	}
-	return result
-}
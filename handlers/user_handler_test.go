@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func withID(id string) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+	}
+}
+
+func TestGetUserProblems(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("invalid ID", func(mt *mtest.T) {
+		h := NewUserHandler(newTestDB(mt))
+		w := serve(h.GetUser, http.MethodGet, "/api/v1/users/nope", nil, withID("nope"))
+		if w.Code != http.StatusBadRequest {
+			mt.Fatalf("status = %d, want 400", w.Code)
+		}
+		if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+			mt.Errorf("Content-Type = %q, want application/problem+json", got)
+		}
+		body := decode(mt, w)
+		if body["code"] != models.CodeInvalidID || body["detail"] != "Invalid user ID" || body["instance"] != "/api/v1/users/nope" {
+			mt.Errorf("problem = %v", body)
+		}
+	})
+
+	mt.Run("unknown user", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns("users"), mtest.FirstBatch))
+		h := NewUserHandler(newTestDB(mt))
+		id := primitive.NewObjectID().Hex()
+		w := serve(h.GetUser, http.MethodGet, "/api/v1/users/"+id, nil, withID(id))
+		if w.Code != http.StatusNotFound {
+			mt.Fatalf("status = %d, want 404", w.Code)
+		}
+		if body := decode(mt, w); body["code"] != models.CodeUserNotFound {
+			mt.Errorf("code = %v, want %s", body["code"], models.CodeUserNotFound)
+		}
+	})
+}
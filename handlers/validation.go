@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report fields by their JSON names so error paths match the request body
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// bindJSON decodes the request body into obj like ShouldBindJSON, but runs normalize
+// on the decoded values before validating them, so input in a lenient format (e.g. a
+// phone number without its country code) passes the stricter binding rules
+func bindJSON(c *gin.Context, obj any, normalize func()) error {
+	if c.Request.Body == nil {
+		return io.EOF
+	}
+	decoder := json.NewDecoder(c.Request.Body)
+	if binding.EnableDecoderUseNumber {
+		decoder.UseNumber()
+	}
+	if binding.EnableDecoderDisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(obj); err != nil {
+		return err
+	}
+	normalize()
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// normalizePhone rewrites a phone number to E.164, reading numbers without a country
+// code as numbers of country. Numbers that can't be read are kept for validation to report.
+func normalizePhone(number *string, country string) {
+	if number == nil {
+		return
+	}
+	if normalized, err := models.NormalizePhone(*number, country); err == nil {
+		*number = normalized
+	}
+}
+
+// normalizeEmail trims and lowercases an email address
+func normalizeEmail(email *string) {
+	if email != nil {
+		*email = models.NormalizeEmail(*email)
+	}
+}
+
+// normalizeMembers normalizes the contact details of team members
+func normalizeMembers(members []models.TeamMember, country string) {
+	for i := range members {
+		normalizePhone(&members[i].MobileNo, country)
+		normalizeEmail(&members[i].Email)
+	}
+}
+
+// invalidRequest converts the error of a request body that failed to bind into a
+// validation error listing every invalid field
+func invalidRequest(err error) *models.Error {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]models.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, fieldError(fe))
+		}
+		return models.InvalidFieldsError(fields)
+	case errors.As(err, &typeErr):
+		return models.InvalidFieldsError([]models.FieldError{{
+			Field:   indexPath(typeErr.Field),
+			Code:    models.FieldInvalidType,
+			Message: "Must be " + jsonKind(typeErr.Type),
+		}})
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return models.ValidationError(models.CodeInvalidRequest, "The request body is not valid JSON")
+	}
+	return models.ValidationError(models.CodeInvalidRequest, "Invalid request data: "+err.Error())
+}
+
+// indexPath writes the array indexes of a decoder path in brackets, e.g.
+// members.0.email becomes members[0].email
+func indexPath(path string) string {
+	parts := strings.Split(path, ".")
+	var sb strings.Builder
+	for i, part := range parts {
+		if _, err := strconv.Atoi(part); err == nil && i > 0 {
+			sb.WriteString("[" + part + "]")
+			continue
+		}
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
+
+// fieldError describes a failed validation rule
+func fieldError(fe validator.FieldError) models.FieldError {
+	// The namespace starts with the request type, e.g. CreateTeamRegistrationRequest.members[2].email
+	path := fe.Namespace()
+	if _, rest, ok := strings.Cut(path, "."); ok {
+		path = rest
+	}
+
+	code, message := models.FieldInvalid, "Is invalid"
+	switch fe.Tag() {
+	case "required":
+		code, message = models.FieldRequired, "Is required"
+	case "email":
+		code, message = models.FieldInvalidEmail, "Must be a valid email address"
+	case "e164":
+		code, message = models.FieldInvalidPhone, "Must be a valid phone number, e.g. +919812345678"
+	case "lowercase":
+		code, message = models.FieldNotLowercase, "Must be lowercase"
+	case "url":
+		code, message = models.FieldInvalidURL, "Must be a valid URL"
+	case "oneof":
+		code, message = models.FieldInvalidChoice, "Must be one of: "+strings.Join(strings.Fields(fe.Param()), ", ")
+	case "min", "max":
+		code, message = lengthError(fe)
+	case "datetime":
+		message = "Must be a date like " + fe.Param()
+	}
+	return models.FieldError{Field: path, Code: code, Message: message}
+}
+
+// lengthError describes a failed min or max rule on a string, list or number
+func lengthError(fe validator.FieldError) (string, string) {
+	atLeast := fe.Tag() == "min"
+	bound := "at most "
+	if atLeast {
+		bound = "at least "
+	}
+	switch fe.Kind() {
+	case reflect.String:
+		if atLeast {
+			return models.FieldTooShort, "Must be " + bound + fe.Param() + " characters long"
+		}
+		return models.FieldTooLong, "Must be " + bound + fe.Param() + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if atLeast {
+			return models.FieldTooFew, "Must have " + bound + fe.Param() + " items"
+		}
+		return models.FieldTooMany, "Must have " + bound + fe.Param() + " items"
+	default:
+		return models.FieldOutOfRange, "Must be " + bound + fe.Param()
+	}
+}
+
+// jsonKind names the JSON type a Go type decodes from
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// invalidField is the validation error of a single field checked by the handler
+func invalidField(field, code, message string) *models.Error {
+	return models.InvalidFieldsError([]models.FieldError{{Field: field, Code: code, Message: message}})
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// VerificationHandler serves the public participation verification API.
+// Responses never include contact details such as emails or phone numbers.
+type VerificationHandler struct {
+	DB *models.DatabaseService
+}
+
+// NewVerificationHandler creates a new VerificationHandler
+func NewVerificationHandler(db *models.DatabaseService) *VerificationHandler {
+	return &VerificationHandler{DB: db}
+}
+
+// PublicVerification is the PII-free view of a team registration
+type PublicVerification struct {
+	RegistrationNumber string `json:"registrationNumber"`
+	TeamName           string `json:"teamName"`
+	Institution        string `json:"institution"`
+	Track              string `json:"track"`
+	Stage              string `json:"stage"`
+	Status             string `json:"status"`
+}
+
+// PublicCertificate is the PII-free view of a certificate
+type PublicCertificate struct {
+	Code          string `json:"code"`
+	Type          string `json:"type"`
+	RecipientName string `json:"recipientName"`
+	Award         string `json:"award,omitempty"`
+	IssuedAt      string `json:"issuedAt"`
+}
+
+// VerifyRegistration confirms a team's participation by registration number (public)
+// @Summary Verify registration
+// @Description Confirm participation by registration number without exposing contact details
+// @Tags verification
+// @Produce json
+// @Param regNumber path string true "Registration Number"
+// @Success 200 {object} RegistrationVerificationResponse
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem "Rate limit exceeded"
+// @Failure 500 {object} Problem
+// @Router /api/v1/verify/registration/{regNumber} [get]
+func (h *VerificationHandler) VerifyRegistration(c *gin.Context) {
+	regNumber := strings.ToUpper(strings.TrimSpace(c.Param("regNumber")))
+
+	team, err := h.DB.GetTeamRegistrationByRegistrationNumber(c.Request.Context(), regNumber)
+	if errors.Is(err, models.ErrNotFound) {
+		respondError(c, models.NotFoundError(models.CodeTeamNotFound, "Registration not found").With("valid", false))
+		return
+	} else if err != nil {
+		respondError(c, fmt.Errorf("failed to verify registration: %w", err))
+		return
+	}
+
+	verification, err := h.publicVerification(c.Request.Context(), team)
+	if err != nil {
+		respondError(c, fmt.Errorf("failed to verify registration: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"valid":        true,
+		"verification": verification,
+	})
+}
+
+// VerifyCertificate confirms that a certificate code was issued (public)
+// @Summary Verify certificate
+// @Description Check a certificate verification code without exposing contact details
+// @Tags verification
+// @Produce json
+// @Param code path string true "Verification code"
+// @Success 200 {object} CertificateVerificationResponse
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem "Rate limit exceeded"
+// @Failure 500 {object} Problem
+// @Router /api/v1/certificates/verify/{code} [get]
+func (h *VerificationHandler) VerifyCertificate(c *gin.Context) {
+	cert, err := h.DB.GetCertificateByCode(c.Request.Context(), c.Param("code"))
+	if errors.Is(err, models.ErrNotFound) {
+		respondError(c, models.ErrCertificateNotFound.With("valid", false))
+		return
+	} else if err != nil {
+		respondError(c, fmt.Errorf("failed to verify certificate: %w", err))
+		return
+	}
+
+	response := gin.H{
+		"valid": true,
+		"certificate": PublicCertificate{
+			Code:          cert.Code,
+			Type:          string(cert.Type),
+			RecipientName: cert.RecipientName,
+			Award:         cert.Award,
+			IssuedAt:      cert.IssuedAt.Format(time.RFC3339),
+		},
+	}
+
+	// The team may have been removed after the certificate was issued; the certificate stays valid
+	if team, err := h.DB.GetTeamRegistrationByID(c.Request.Context(), cert.TeamRegistrationID.Hex()); err == nil {
+		verification, err := h.publicVerification(c.Request.Context(), team)
+		if err != nil {
+			respondError(c, fmt.Errorf("failed to verify certificate: %w", err))
+			return
+		}
+		response["verification"] = verification
+	} else {
+		response["verification"] = PublicVerification{
+			RegistrationNumber: cert.RegistrationNumber,
+			TeamName:           cert.TeamName,
+			Institution:        cert.Institution,
+			Track:              string(cert.Track),
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// publicVerification builds the PII-free view of a team
+func (h *VerificationHandler) publicVerification(ctx context.Context, team *models.TeamRegistration) (*PublicVerification, error) {
+	hasVideo := false
+	if team.IsApproved() {
+		link, err := h.DB.GetVideoLinkForTeam(ctx, team)
+		if err != nil {
+			return nil, err
+		}
+		hasVideo = link != ""
+	}
+
+	isWinner, err := h.DB.HasCertificate(ctx, team.ID, models.CertificateWinner)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicVerification{
+		RegistrationNumber: team.RegistrationNumber,
+		TeamName:           team.TeamName,
+		Institution:        team.Institution,
+		Track:              string(team.Track),
+		Stage:              string(team.StageReached(hasVideo, isWinner)),
+		Status:             string(team.RegistrationStatus),
+	}, nil
+}
@@ -0,0 +1,121 @@
+// Package logging configures structured (slog) logging with per-request context
+// and automatic redaction of credentials and contact details.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Setup installs the default logger writing to stdout. level is debug, info, warn or
+// error (default info); format is json (default) or text.
+func Setup(levelName, format string) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(levelName)); err != nil {
+		level = slog.LevelInfo
+	}
+
+	logger := slog.New(NewHandler(os.Stdout, format, level))
+	slog.SetDefault(logger)
+	return logger
+}
+
+// NewHandler returns a redacting handler that adds the request context of each record
+func NewHandler(w io.Writer, format string, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+
+	var h slog.Handler
+	if strings.EqualFold(format, "text") {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return contextHandler{h}
+}
+
+// contextHandler adds request ID, user ID, role and trace context from the context
+// to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if f := fieldsFrom(ctx); f != nil {
+		f.mu.Lock()
+		r.AddAttrs(slog.String("request_id", f.requestID))
+		if f.userID != "" {
+			r.AddAttrs(slog.String("user_id", f.userID), slog.String("role", f.role))
+		}
+		f.mu.Unlock()
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// requestFields is shared by everything handling one request. It is mutable because
+// the user is only known once authentication middleware has run.
+type requestFields struct {
+	mu        sync.Mutex
+	requestID string
+	userID    string
+	role      string
+}
+
+type contextKey struct{}
+
+// WithRequestID returns a context whose log records carry the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestFields{requestID: requestID})
+}
+
+// RequestID returns the request ID stored in the context, if any
+func RequestID(ctx context.Context) string {
+	if f := fieldsFrom(ctx); f != nil {
+		return f.requestID
+	}
+	return ""
+}
+
+// SetUser records the authenticated user of the request for subsequent log records
+func SetUser(ctx context.Context, userID, role string) {
+	if f := fieldsFrom(ctx); f != nil {
+		f.mu.Lock()
+		f.userID = userID
+		f.role = role
+		f.mu.Unlock()
+	}
+}
+
+// User returns the authenticated user recorded for the request, if any
+func User(ctx context.Context) (userID, role string) {
+	if f := fieldsFrom(ctx); f != nil {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.userID, f.role
+	}
+	return "", ""
+}
+
+func fieldsFrom(ctx context.Context) *requestFields {
+	if ctx == nil {
+		return nil
+	}
+	f, _ := ctx.Value(contextKey{}).(*requestFields)
+	return f
+}
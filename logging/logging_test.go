@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// record logs one message through a JSON handler and returns the decoded record
+func record(t *testing.T, ctx context.Context, msg string, args ...any) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	slog.New(NewHandler(&buf, "json", slog.LevelDebug)).InfoContext(ctx, msg, args...)
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("log output %q is not JSON: %v", buf.String(), err)
+	}
+	return rec
+}
+
+func TestHandlerAddsRequestContext(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+
+	rec := record(t, ctx, "before login")
+	if rec["request_id"] != "req-1" {
+		t.Errorf("request_id = %v, want req-1", rec["request_id"])
+	}
+	if _, ok := rec["user_id"]; ok {
+		t.Errorf("anonymous record has user_id %v", rec["user_id"])
+	}
+
+	SetUser(ctx, "u-42", "admin")
+	rec = record(t, ctx, "after login")
+	if rec["user_id"] != "u-42" || rec["role"] != "admin" {
+		t.Errorf("user = %v/%v, want u-42/admin", rec["user_id"], rec["role"])
+	}
+	if id, role := User(ctx); id != "u-42" || role != "admin" {
+		t.Errorf("User() = %s/%s, want u-42/admin", id, role)
+	}
+}
+
+func TestHandlerWithoutRequestContext(t *testing.T) {
+	rec := record(t, context.Background(), "startup")
+	if _, ok := rec["request_id"]; ok {
+		t.Errorf("record outside a request has request_id %v", rec["request_id"])
+	}
+	if got := RequestID(context.Background()); got != "" {
+		t.Errorf("RequestID() = %q, want empty", got)
+	}
+}
+
+func TestHandlerRedactsOutput(t *testing.T) {
+	rec := record(t, context.Background(), "registration failed",
+		"leaderEmail", "lead@example.org",
+		"detail", "duplicate mobile 9876543210",
+		slog.Group("auth", "password", "hunter2"),
+	)
+	if rec["leaderEmail"] != Redacted {
+		t.Errorf("leaderEmail = %v, want redacted", rec["leaderEmail"])
+	}
+	if rec["detail"] != "duplicate mobile "+Redacted {
+		t.Errorf("detail = %v, want the phone number redacted", rec["detail"])
+	}
+	if auth, _ := rec["auth"].(map[string]any); auth["password"] != Redacted {
+		t.Errorf("grouped password = %v, want redacted", auth["password"])
+	}
+}
+
+func TestHandlerTextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewHandler(&buf, "TEXT", slog.LevelInfo)).Info("hello", "token", "abc")
+	if got := buf.String(); !bytes.Contains([]byte(got), []byte("token="+Redacted)) || got[0] == '{' {
+		t.Errorf("text output = %q, want a redacted logfmt record", got)
+	}
+}
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "failed to approve team", "failed to approve team"},
+		{"bearer token", "Authorization: Bearer abc.def-123", "Authorization: Bearer [REDACTED]"},
+		{"lowercase bearer", "got bearer abc123", "got Bearer [REDACTED]"},
+		{"JWT", "token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig expired", "token [REDACTED] expired"},
+		{"password assignment", "password=hunter2&user=alice", "password=[REDACTED]&user=alice"},
+		{"quoted secret", `{"secret": "s3cr3t"}`, `{"secret": "[REDACTED]"}`},
+		{"api key", "apiKey:abc123 sent", "apiKey:[REDACTED] sent"},
+		{"email", "duplicate leaderEmail lead@example.org", "duplicate leaderEmail [REDACTED]"},
+		{"international phone", "mobile +91 98765 43210 taken", "mobile [REDACTED] taken"},
+		{"ten digit phone", "mobile 9876543210 taken", "mobile [REDACTED] taken"},
+		{"short number", "team 12345 approved", "team 12345 approved"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.in); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactAttr(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want string
+	}{
+		{"sensitive key", slog.String("leaderEmail", "anything"), Redacted},
+		{"sensitive key of any kind", slog.Int("otpToken", 123456), Redacted},
+		{"sensitive value", slog.String("detail", "sent to lead@example.org"), "sent to " + Redacted},
+		{"error", slog.Any("error", errors.New("bad password=hunter2")), "bad password=" + Redacted},
+		{"other value", slog.Int("count", 3), "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactAttr(nil, tt.attr)
+			if got.Key != tt.attr.Key || got.Value.String() != tt.want {
+				t.Errorf("redactAttr(%v) = %v, want %s=%s", tt.attr, got, tt.attr.Key, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package mailer
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// Mailer sends plain-text emails
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPMailer sends emails through an SMTP server
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send delivers a plain-text email
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(m.Host+":"+m.Port, auth, m.From, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("send mail: %v", err)
+	}
+	return nil
+}
+
+// LogMailer writes emails to the log instead of sending them (local development).
+// Log redaction masks the recipient and any tokens in the body, login links included;
+// set Outbox to also receive the emails unredacted.
+type LogMailer struct {
+	Outbox io.Writer
+}
+
+// Send logs the email and writes it to the outbox, if any
+func (m LogMailer) Send(to, subject, body string) error {
+	slog.Info("email not sent (no SMTP configured)", "to", to, "subject", subject, "body", body)
+	if m.Outbox == nil {
+		return nil
+	}
+	msg := "To: " + to + "\nSubject: " + subject + "\n\n" + body + "\n\n"
+	if _, err := io.WriteString(m.Outbox, msg); err != nil {
+		return fmt.Errorf("write outbox: %v", err)
+	}
+	return nil
+}
+
+// New returns an SMTPMailer when host is set, otherwise a LogMailer writing to outbox.
+// The sender defaults to the username.
+func New(host string, port int, username, password, from string, outbox io.Writer) Mailer {
+	if host == "" {
+		return LogMailer{Outbox: outbox}
+	}
+	if from == "" {
+		from = username
+	}
+
+	return &SMTPMailer{
+		Host:     host,
+		Port:     strconv.Itoa(port),
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
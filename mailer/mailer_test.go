@@ -0,0 +1,23 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogMailerOutbox(t *testing.T) {
+	link := "http://localhost:3000/participant/login?token=abc123"
+
+	var outbox strings.Builder
+	m := New("", 587, "", "", "", &outbox)
+	if err := m.Send("leader@example.org", "Your login link", "Log in: "+link); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := outbox.String(); !strings.Contains(got, "To: leader@example.org\n") || !strings.Contains(got, link) {
+		t.Errorf("outbox = %q, want the email with its login link", got)
+	}
+
+	if err := New("", 587, "", "", "", nil).Send("leader@example.org", "Your login link", link); err != nil {
+		t.Errorf("Send without outbox: %v", err)
+	}
+}
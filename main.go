@@ -1,43 +1,169 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
   "github.com/gin-contrib/cors"
+	"github.com/Mastermind730/igc-admin-backend/buildinfo"
+	"github.com/Mastermind730/igc-admin-backend/certificates"
+	"github.com/Mastermind730/igc-admin-backend/config"
 	"github.com/Mastermind730/igc-admin-backend/handlers"
+	"github.com/Mastermind730/igc-admin-backend/logging"
+	"github.com/Mastermind730/igc-admin-backend/mailer"
+	"github.com/Mastermind730/igc-admin-backend/metrics"
 	"github.com/Mastermind730/igc-admin-backend/middleware"
 	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/Mastermind730/igc-admin-backend/openapi"
 	"github.com/Mastermind730/igc-admin-backend/routes"
+	"github.com/Mastermind730/igc-admin-backend/tracing"
+	"github.com/Mastermind730/igc-admin-backend/workers"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
+	// Without a command the API server is started
+	command, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+	os.Exit(runCommand(command, args))
+}
+
+// runServe starts the API server and returns once it has shut down
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.Parse(args)
+
+	cfg := loadConfig()
+	ctx := context.Background()
+
+	// Tracing is exported only when an exporter is configured
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing.Exporter)
+	if err != nil {
+		slog.Error("failed to set up tracing", "error", err)
+		return 1
+	}
+
 	// Setup MongoDB connection
-	client, err := SetupMongoDB()
+	client, err := SetupMongoDB(cfg.Database.URI)
 	if err != nil {
-		log.Fatal("Failed to connect to MongoDB:", err)
+		slog.Error("failed to connect to MongoDB", "error", err)
+		return 1
 	}
 	
 	// Create a database service
-	dbService := models.NewDatabaseService(client, "pccoe_IGC")
+	dbService := models.NewDatabaseService(client, cfg.Database.Name, cfg.Database.VideoCollection)
 	defer dbService.Close()
 	
-	fmt.Println("Connected to MongoDB successfully!")
+
+	if err := dbService.EnsureIndexes(ctx); err != nil {
+		slog.Warn("failed to create indexes", "error", err)
+	}
+	if err := dbService.EnsureCatalog(ctx); err != nil {
+		slog.Error("failed to seed tracks and programs", "error", err)
+		return 1
+	}
+	if _, err := dbService.EnsureDefaultEvent(ctx); err != nil {
+		slog.Error("failed to prepare default event", "error", err)
+		return 1
+	}
+
+	port := cfg.Server.Port
+
+	// Certificate templates can be customised with a JSON file
+	verifyBaseURL := cfg.Certificates.VerifyBaseURL
+	if verifyBaseURL == "" {
+		verifyBaseURL = fmt.Sprintf("http://localhost:%d/api/v1/certificates/verify/", port)
+	}
+	certRenderer, err := certificates.NewRenderer(cfg.Certificates.TemplatesPath, verifyBaseURL)
+	if err != nil {
+		slog.Error("failed to load certificate templates", "error", err)
+		return 1
+	}
 	
 	// Initialize handlers
+	handlers.Configure(handlers.Settings{
+		JWTSecret:           cfg.Auth.JWTSecret,
+		AdminTokenTTL:       cfg.Auth.AdminTokenTTL,
+		ParticipantTokenTTL: cfg.Auth.ParticipantTokenTTL,
+		MagicLinkTTL:        cfg.Auth.MagicLinkTTL,
+		DefaultPageLimit:    cfg.Pagination.DefaultLimit,
+		MaxPageLimit:        cfg.Pagination.MaxLimit,
+	})
 	userHandler := handlers.NewUserHandler(dbService)
 	teamHandler := handlers.NewTeamRegistrationHandler(dbService)
+	certHandler := handlers.NewCertificateHandler(dbService, certRenderer)
+	verifyHandler := handlers.NewVerificationHandler(dbService)
+
+	// Public verification endpoints are rate limited per client IP
+	verifyLimiter := middleware.RateLimit(cfg.Server.VerifyRateLimit, 10)
+
+	// Participant portal login links point at the frontend
+	smtpCfg := cfg.SMTP
+	var outbox io.Writer
+	if smtpCfg.DevOutbox != "" {
+		f, err := os.OpenFile(smtpCfg.DevOutbox, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			slog.Error("failed to open the dev outbox", "error", err)
+			return 1
+		}
+		defer f.Close()
+		outbox = f
+		slog.Warn("writing unsent emails unredacted to the dev outbox", "path", smtpCfg.DevOutbox)
+	}
+	emailer := mailer.New(smtpCfg.Host, smtpCfg.Port, smtpCfg.Username, smtpCfg.Password, smtpCfg.From, outbox)
+	participantHandler := handlers.NewParticipantHandler(dbService, emailer, cfg.Portal.URL)
+	eventConfigHandler := handlers.NewEventConfigHandler(dbService)
+	eventHandler := handlers.NewEventHandler(dbService)
+	if cfg.Features.Metrics {
+		if err := metrics.RegisterBusinessCollector(dbService); err != nil {
+			slog.Error("failed to register metrics", "error", err)
+			return 1
+		}
+	}
+	trackHandler := handlers.NewCatalogHandler(dbService, models.CatalogTrack)
+	programHandler := handlers.NewCatalogHandler(dbService, models.CatalogProgram)
+	auditHandler := handlers.NewAuditHandler(dbService)
+	trashHandler := handlers.NewTrashHandler(dbService)
+
+	// Background workers; their status is part of the readiness probe
+	backgroundWorkers := workers.NewManager()
+	if cfg.Features.WaitlistReconciler {
+		backgroundWorkers.Add("waitlist-reconciler", cfg.Workers.WaitlistReconcileInterval, workers.ReconcileWaitlists(dbService))
+	}
+	if cfg.Features.TrashPurge {
+		backgroundWorkers.Add("trash-purge", cfg.Workers.TrashPurgeInterval, workers.PurgeTrash(dbService, cfg.Workers.TrashRetention))
+	}
+	backgroundWorkers.Start(ctx)
+	healthHandler := handlers.NewHealthHandler(dbService, backgroundWorkers)
+
+	// The OpenAPI document is generated from the handler annotations
+	spec, err := openapi.JSON(buildinfo.Version)
+	if err != nil {
+		slog.Error("failed to build the OpenAPI document", "error", err)
+		return 1
+	}
+	docsHandler := handlers.NewDocsHandler(spec)
 	
 	// Create Gin router
 	router := gin.New()
+	// Client IPs, used for rate limits and the audit log, are only taken from
+	// X-Forwarded-For when one of the configured proxies sent it
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		slog.Error("invalid trusted proxies", "error", err)
+		return 1
+	}
 	
 	// Add middleware
 	corsConfig := cors.Config{
-		AllowOrigins: []string{
-			"http://localhost:3000",
-			"http://127.0.0.1:3000",
-		},
+		AllowOrigins: cfg.CORS.AllowedOrigins,
 		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
 		AllowHeaders: []string{
 			"Origin",
@@ -47,28 +173,48 @@ func main() {
 			"Accept",
 			"X-Requested-With",
 			"Cache-Control",
+			"If-Match",
+			middleware.RequestIDHeader,
 		},
+		ExposeHeaders:    []string{middleware.RequestIDHeader, "ETag"},
 		AllowCredentials: true,
 		MaxAge: 12 * 60 * 60, // 12 hours
 	}
 
 	router.Use(cors.New(corsConfig))
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+	router.Use(middleware.RequestID())
 	router.Use(middleware.Logger())
+	if cfg.Features.Metrics {
+		router.Use(metrics.Middleware())
+	}
 	router.Use(middleware.ErrorHandler())
 	router.Use(gin.Recovery())
 	
 	// Setup routes
-	routes.SetupRoutes(router, userHandler, teamHandler)
+	routes.SetupRoutes(router, routes.Handlers{
+		User:            userHandler,
+		Team:            teamHandler,
+		Certificate:     certHandler,
+		Verification:    verifyHandler,
+		Participant:     participantHandler,
+		EventConfig:     eventConfigHandler,
+		Event:           eventHandler,
+		Tracks:          trackHandler,
+		Programs:        programHandler,
+		Audit:           auditHandler,
+		Trash:           trashHandler,
+		Health:          healthHandler,
+		Docs:            docsHandler,
+		EventScope:      handlers.EventScope(dbService),
+		PublicRateLimit: verifyLimiter,
+		Metrics:         metrics.Handler(cfg.Metrics.Token),
+		Features:        cfg.Features,
+	})
 	
-	// Get port from environment variable or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	
-	fmt.Printf("🚀 IGC Admin Backend API Server starting on port %s\n", port)
-	fmt.Printf("📖 API Documentation available at: http://localhost:%s/api/v1/health\n", port)
-	fmt.Printf("🌐 Base URL: http://localhost:%s\n", port)
+	fmt.Printf("🚀 IGC Admin Backend API Server %s starting on port %d\n", buildinfo.Version, port)
+	fmt.Printf("📖 API Documentation available at: http://localhost:%d/api/v1/docs\n", port)
+	fmt.Printf("🌐 Base URL: http://localhost:%d\n", port)
 	
 	// Print available routes
 	fmt.Println("\n📋 Available API Routes:")
@@ -91,39 +237,123 @@ func main() {
 	fmt.Println("  PUT  /api/v1/team-registrations/{id}/action")
 	fmt.Println("  GET  /api/v1/team-registrations/reg/{regNumber}")
 	fmt.Println("  GET  /api/v1/team-registrations/track/{track}")
+	fmt.Println("\nCertificates:")
+	fmt.Println("  POST /api/v1/certificates/bulk")
+	fmt.Println("  GET  /api/v1/certificates")
+	fmt.Println("  GET  /api/v1/certificates/zip")
+	fmt.Println("  GET  /api/v1/certificates/{code}/pdf")
+	fmt.Println("\nPublic Verification:")
+	fmt.Println("  GET  /api/v1/certificates/verify/{code}")
+	fmt.Println("  GET  /api/v1/verify/registration/{regNumber}")
+	fmt.Println("\nParticipant Portal:")
+	fmt.Println("  POST /api/v1/participant/auth/request-link")
+	fmt.Println("  POST /api/v1/participant/auth/verify")
+	fmt.Println("  GET  /api/v1/participant/registration")
+	fmt.Println("  GET  /api/v1/participant/change-requests")
+	fmt.Println("  POST /api/v1/participant/change-requests")
+	fmt.Println("  GET  /api/v1/change-requests")
+	fmt.Println("  PUT  /api/v1/change-requests/{id}/action")
+	fmt.Println("\nEvent Configuration:")
+	fmt.Println("  GET  /api/v1/event-config")
+	fmt.Println("  PUT  /api/v1/event-config")
+	fmt.Println("\nEvents:")
+	fmt.Println("  GET  /api/v1/events")
+	fmt.Println("  POST /api/v1/events")
+	fmt.Println("  GET  /api/v1/events/{event}")
+	fmt.Println("  PUT  /api/v1/events/{event}")
+	fmt.Println("  POST /api/v1/events/{event}/activate")
+	fmt.Println("  GET  /api/v1/evaluations")
+	fmt.Println("\nAudit Log:")
+	fmt.Println("  GET  /api/v1/audit-log")
+	fmt.Println("  GET  /api/v1/audit-log/export")
+	fmt.Println("\nTrash:")
+	fmt.Println("  GET  /api/v1/trash/teams")
+	fmt.Println("  POST /api/v1/trash/teams/{id}/restore")
+	fmt.Println("  GET  /api/v1/trash/users")
+	fmt.Println("  POST /api/v1/trash/users/{id}/restore")
+	fmt.Println("\nTracks & Programs:")
+	fmt.Println("  GET  /api/v1/tracks")
+	fmt.Println("  POST /api/v1/tracks")
+	fmt.Println("  GET  /api/v1/tracks/{slug}")
+	fmt.Println("  PUT  /api/v1/tracks/{slug}")
+	fmt.Println("  DELETE /api/v1/tracks/{slug}")
+	fmt.Println("  (same routes under /api/v1/programs)")
 	fmt.Println("\nHealth Check:")
 	fmt.Println("  GET  /")
 	fmt.Println("  GET  /api/v1/health")
+	fmt.Println("  GET  /livez")
+	fmt.Println("  GET  /readyz")
+	fmt.Println("\nDocumentation:")
+	fmt.Println("  GET  /api/v1/openapi.json")
+	fmt.Println("  GET  /api/v1/docs")
+	fmt.Println("\nMetrics:")
+	fmt.Println("  GET  /metrics (bearer METRICS_TOKEN if set)")
 	fmt.Println("================================")
 	
 	// Create a default admin user if none exists
-	go createDefaultAdminUser(dbService)
+	go createDefaultAdminUser(dbService, cfg.Auth.AdminUsername, cfg.Auth.AdminPassword)
 	
-	// Start the server
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// Start the server; SIGINT/SIGTERM trigger a graceful shutdown
+	serverCfg := cfg.Server
+	srv := newServer(cfg.Addr(), router, serverCfg)
+	signalCtx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	serverErr := serve(signalCtx, srv, serverCfg.ShutdownTimeout)
+	stopSignals()
+	if serverErr != nil {
+		slog.Error("server stopped", "error", serverErr)
+	}
+
+	// Requests have drained; stop background jobs before the deferred disconnect of the database they use
+	backgroundWorkers.Stop()
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Warn("failed to flush traces", "error", err)
+	}
+	if serverErr != nil {
+		return 1
 	}
+	slog.Info("shutdown complete")
+	return 0
 }
 
-// createDefaultAdminUser creates a default admin user if no users exist
-func createDefaultAdminUser(db *models.DatabaseService) {
-	count, err := db.CountUsers()
+// loadConfig loads the configuration and sets up logging. An invalid configuration
+// ends the process with a report of every problem.
+func loadConfig() *config.Config {
+	cfg, err := config.Load()
 	if err != nil {
-		log.Printf("Error checking user count: %v", err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	logging.Setup(cfg.Log.Level, cfg.Log.Format)
+	return cfg
+}
+
+// createDefaultAdminUser creates a default admin user if no users exist.
+// Without a configured password the well-known default is used.
+func createDefaultAdminUser(db *models.DatabaseService, username, password string) {
+	ctx := context.Background()
+	count, err := db.CountUsers(ctx)
+	if err != nil {
+		slog.Error("failed to check user count", "error", err)
 		return
 	}
 	
 	if count == 0 {
-		defaultUser := models.NewUser("admin", "admin123")
-		createdUser, err := db.CreateUser(defaultUser)
+		defaultPassword := password == ""
+		if defaultPassword {
+			password = "admin123"
+		}
+		defaultUser := models.NewUser(username, password)
+		createdUser, err := db.CreateUser(ctx, defaultUser)
 		if err != nil {
-			log.Printf("Error creating default admin user: %v", err)
+			slog.Error("failed to create default admin user", "error", err)
 			return
 		}
 		
-		fmt.Printf("\n🔐 Default admin user created successfully!\n")
-		fmt.Printf("   Username: %s\n", createdUser.Username)
-		fmt.Printf("   Password: admin123\n")
-		fmt.Printf("   ⚠️  Please change the default password after first login!\n\n")
+		if defaultPassword {
+			slog.Warn("default admin user created with the default password; change it after first login",
+				"username", createdUser.Username)
+		} else {
+			slog.Info("admin user created", "username", createdUser.Username)
+		}
 	}
 }
\ No newline at end of file
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// statsTTL bounds how often scrapes hit the database for registration figures
+const statsTTL = 15 * time.Second
+
+// EventStats are the registration figures of one event
+type EventStats struct {
+	Event         string
+	TeamsByStatus map[string]int64
+	TeamsByTrack  map[string]int64
+	// PendingAllocations counts pending teams not yet allocated to a judge
+	PendingAllocations   int64
+	EvaluationsSubmitted int64
+}
+
+// Stats are the business figures exported as gauges
+type Stats struct {
+	Events          []EventStats
+	VideosSubmitted int64
+}
+
+// StatsSource computes the business figures; implemented by models.DatabaseService
+type StatsSource interface {
+	MetricsStats(ctx context.Context) (*Stats, error)
+}
+
+var (
+	teamsByStatusDesc = prometheus.NewDesc(namespace+"_teams",
+		"Team registrations by event and registration status.", []string{"event", "status"}, nil)
+	teamsByTrackDesc = prometheus.NewDesc(namespace+"_teams_by_track",
+		"Team registrations by event and track.", []string{"event", "track"}, nil)
+	pendingAllocationsDesc = prometheus.NewDesc(namespace+"_pending_allocations",
+		"Pending team registrations not yet allocated to a judge.", []string{"event"}, nil)
+	evaluationsDesc = prometheus.NewDesc(namespace+"_evaluations_submitted",
+		"Judge evaluations submitted.", []string{"event"}, nil)
+	videosDesc = prometheus.NewDesc(namespace+"_videos_submitted",
+		"Videos submitted.", nil, nil)
+)
+
+// businessCollector reads the figures from the database at scrape time, caching them
+// for statsTTL
+type businessCollector struct {
+	source StatsSource
+
+	mu        sync.Mutex
+	stats     *Stats
+	fetchedAt time.Time
+}
+
+// RegisterBusinessCollector exports the registration, evaluation and video gauges
+func RegisterBusinessCollector(source StatsSource) error {
+	return prometheus.Register(&businessCollector{source: source})
+}
+
+func (c *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- teamsByStatusDesc
+	ch <- teamsByTrackDesc
+	ch <- pendingAllocationsDesc
+	ch <- evaluationsDesc
+	ch <- videosDesc
+}
+
+func (c *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.load()
+	if stats == nil {
+		return
+	}
+
+	for _, e := range stats.Events {
+		for status, n := range e.TeamsByStatus {
+			ch <- prometheus.MustNewConstMetric(teamsByStatusDesc, prometheus.GaugeValue, float64(n), e.Event, status)
+		}
+		for track, n := range e.TeamsByTrack {
+			ch <- prometheus.MustNewConstMetric(teamsByTrackDesc, prometheus.GaugeValue, float64(n), e.Event, track)
+		}
+		ch <- prometheus.MustNewConstMetric(pendingAllocationsDesc, prometheus.GaugeValue, float64(e.PendingAllocations), e.Event)
+		ch <- prometheus.MustNewConstMetric(evaluationsDesc, prometheus.GaugeValue, float64(e.EvaluationsSubmitted), e.Event)
+	}
+	ch <- prometheus.MustNewConstMetric(videosDesc, prometheus.GaugeValue, float64(stats.VideosSubmitted))
+}
+
+// load returns the cached figures, refreshing them when stale. On failure the last
+// figures are kept so a database hiccup doesn't blank the dashboards.
+func (c *businessCollector) load() *Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stats != nil && time.Since(c.fetchedAt) < statsTTL {
+		return c.stats
+	}
+
+	stats, err := c.source.MetricsStats(context.Background())
+	if err != nil {
+		slog.Error("failed to collect business metrics", "error", err)
+		return c.stats
+	}
+	c.stats = stats
+	c.fetchedAt = time.Now()
+	return stats
+}
@@ -0,0 +1,87 @@
+// Package metrics exposes Prometheus metrics for HTTP requests, MongoDB operations
+// and registration figures.
+package metrics
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "igc"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+)
+
+// Middleware records request count and latency per route template (e.g.
+// /api/v1/team-registrations/:id), so IDs in paths don't create new series
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ErrInvalidToken is attached to scrapes without the configured token
+var ErrInvalidToken = errors.New("invalid metrics token")
+
+// Handler serves the metrics in the Prometheus text format. When token is non-empty
+// scrapers must send it as a bearer token.
+// @Summary Prometheus metrics
+// @Description HTTP, database and registration metrics in the Prometheus text format
+// @Tags health
+// @Produce plain
+// @Success 200 {string} string
+// @Failure 401 {object} handlers.Problem "Invalid metrics token"
+// @Security MetricsToken
+// @Router /metrics [get]
+func Handler(token string) gin.HandlerFunc {
+	h := promhttp.Handler()
+	return func(c *gin.Context) {
+		if token != "" {
+			got := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				_ = c.Error(ErrInvalidToken)
+				c.Abort()
+				return
+			}
+		}
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
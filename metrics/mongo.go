@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var (
+	dbDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_operation_duration_seconds",
+		Help:      "MongoDB command latency by DatabaseService method, collection and command.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method", "collection", "command"})
+
+	dbErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_operation_errors_total",
+		Help:      "Failed MongoDB commands by DatabaseService method, collection and command.",
+	}, []string{"method", "collection", "command"})
+)
+
+type operationKey struct{}
+
+// WithOperation labels the MongoDB commands issued with ctx with the name of the
+// DatabaseService method issuing them
+func WithOperation(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, operationKey{}, method)
+}
+
+func operation(ctx context.Context) string {
+	if method, ok := ctx.Value(operationKey{}).(string); ok {
+		return method
+	}
+	return "other"
+}
+
+// CommandMonitor returns a MongoDB command monitor that times every command.
+// Commands that don't target a collection (handshakes, pings) are not recorded.
+func CommandMonitor() *event.CommandMonitor {
+	// The collection is only named in the started event, so remember it until the
+	// command finishes
+	var collections sync.Map
+
+	finished := func(ctx context.Context, e event.CommandFinishedEvent, failed bool) {
+		v, ok := collections.LoadAndDelete(e.RequestID)
+		if !ok {
+			return
+		}
+		labels := []string{operation(ctx), v.(string), e.CommandName}
+		dbDuration.WithLabelValues(labels...).Observe(e.Duration.Seconds())
+		if failed {
+			dbErrors.WithLabelValues(labels...).Inc()
+		}
+	}
+
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			if collection := commandCollection(e); collection != "" {
+				collections.Store(e.RequestID, collection)
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			finished(ctx, e.CommandFinishedEvent, false)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			finished(ctx, e.CommandFinishedEvent, true)
+		},
+	}
+}
+
+// commandCollection returns the collection a command operates on, if any
+func commandCollection(e *event.CommandStartedEvent) string {
+	key := e.CommandName
+	if key == "getMore" {
+		key = "collection"
+	}
+	if v, err := e.Command.LookupErr(key); err == nil {
+		if name, ok := v.StringValueOK(); ok {
+			return name
+		}
+	}
+	return ""
+}
@@ -1,27 +1,91 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"sync"
 	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/logging"
+	"github.com/Mastermind730/igc-admin-backend/metrics"
+	"github.com/Mastermind730/igc-admin-backend/models"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
 )
 
-// Logger is a middleware that logs HTTP requests
+// RequestIDHeader carries the request ID between clients, proxies and this API
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID propagates a well-formed X-Request-ID from the client or generates one,
+// echoes it in the response and attaches it to the request context for logging
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// newRequestID returns a random 128-bit hex identifier
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Logger is a middleware that logs each HTTP request as a structured record.
+// The route template is logged instead of the raw path so IDs and query strings stay out of the logs.
 func Logger() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	})
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+		slog.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
 }
 
 // CORS middleware - backup implementation (currently using gin-contrib/cors in main.go)
@@ -65,21 +129,102 @@ func CORS() gin.HandlerFunc {
 }
 
 
-// ErrorHandler is a middleware that handles panics and errors
+// ErrorHandler recovers panics and renders the error a handler attached with c.Error
+// as an RFC 7807 problem+json response. Domain errors from the models map to their
+// status and stable code; any other error is a 500 whose cause only goes to the logs.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
-			if err := recover(); err != nil {
-				log.Printf("Panic recovered: %v", err)
-				c.JSON(500, gin.H{
-					"error":   "Internal server error",
-					"message": "Something went wrong on our end",
-				})
+			if p := recover(); p != nil {
+				slog.ErrorContext(c.Request.Context(), "panic recovered", "panic", fmt.Sprint(p), "stack", string(debug.Stack()))
+				writeProblem(c, classify(nil))
 				c.Abort()
 			}
 		}()
 		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		p := classify(err)
+		if p.status >= 500 {
+			slog.ErrorContext(c.Request.Context(), "request failed", "error", err, "status", p.status)
+		} else {
+			slog.DebugContext(c.Request.Context(), "request rejected", "error", err, "status", p.status, "code", p.code)
+		}
+		writeProblem(c, p)
+	}
+}
+
+// ErrRateLimited is attached to requests rejected by RateLimit
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// CodeRateLimited is the problem code of rate-limited requests
+const CodeRateLimited = "rate_limited"
+
+// kindStatus maps the domain error kinds to HTTP statuses
+var kindStatus = map[error]int{
+	models.ErrNotFound:           http.StatusNotFound,
+	models.ErrInvalidID:          http.StatusBadRequest,
+	models.ErrValidation:         http.StatusBadRequest,
+	models.ErrConflict:           http.StatusConflict,
+	models.ErrInvalidTransition:  http.StatusConflict,
+	models.ErrPreconditionFailed: http.StatusPreconditionFailed,
+	models.ErrForbidden:          http.StatusForbidden,
+	models.ErrUnauthorized:       http.StatusUnauthorized,
+}
+
+// problem is the client-facing part of an error
+type problem struct {
+	status     int
+	code       string
+	detail     string
+	extensions map[string]any
+}
+
+// classify maps err to the problem shown to clients
+func classify(err error) problem {
+	var domainErr *models.Error
+	var deadlineErr *models.DeadlineError
+	switch {
+	case errors.As(err, &domainErr):
+		if status, ok := kindStatus[domainErr.Kind]; ok {
+			return problem{status: status, code: domainErr.Code, detail: domainErr.Message, extensions: domainErr.Extensions}
+		}
+	case errors.As(err, &deadlineErr):
+		return problem{
+			status:     http.StatusForbidden,
+			code:       deadlineErr.Code,
+			detail:     deadlineErr.Message,
+			extensions: map[string]any{"deadline": deadlineErr.Deadline},
+		}
+	case errors.Is(err, ErrRateLimited):
+		return problem{status: http.StatusTooManyRequests, code: CodeRateLimited, detail: "Rate limit exceeded, please retry later"}
+	case errors.Is(err, metrics.ErrInvalidToken):
+		return problem{status: http.StatusUnauthorized, code: models.CodeTokenInvalid, detail: "Invalid metrics token"}
 	}
+	return problem{status: http.StatusInternalServerError, code: models.CodeInternal, detail: "Something went wrong on our end"}
+}
+
+// writeProblem writes p as application/problem+json
+func writeProblem(c *gin.Context, p problem) {
+	body := gin.H{}
+	for k, v := range p.extensions {
+		body[k] = v
+	}
+	body["type"] = "about:blank"
+	body["title"] = http.StatusText(p.status)
+	body["status"] = p.status
+	body["detail"] = p.detail
+	body["instance"] = c.Request.URL.Path
+	body["code"] = p.code
+	if id := c.GetString("request_id"); id != "" {
+		body["request_id"] = id
+	}
+
+	c.Header("Content-Type", "application/problem+json")
+	c.JSON(p.status, body)
 }
 
 // BasicAuth is a simple authentication middleware (for admin routes)
@@ -99,4 +244,56 @@ func BasicAuth() gin.HandlerFunc {
 		
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+// RateLimit limits each client IP to perMinute requests per minute with the given burst.
+// Requests over the limit receive 429 with a Retry-After header. Client IPs are only
+// taken from X-Forwarded-For when the engine trusts the proxy that sent it.
+func RateLimit(perMinute int, burst int) gin.HandlerFunc {
+	type client struct {
+		limiter  *rate.Limiter
+		lastSeen time.Time
+	}
+
+	var (
+		mu      sync.Mutex
+		clients = make(map[string]*client)
+		every   = rate.Every(time.Minute / time.Duration(perMinute))
+	)
+
+	// Forget idle clients so the map does not grow without bound
+	go func() {
+		for range time.Tick(time.Minute) {
+			mu.Lock()
+			for ip, cl := range clients {
+				if time.Since(cl.lastSeen) > 3*time.Minute {
+					delete(clients, ip)
+				}
+			}
+			mu.Unlock()
+		}
+	}()
+
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+
+		mu.Lock()
+		cl, ok := clients[ip]
+		if !ok {
+			cl = &client{limiter: rate.NewLimiter(every, burst)}
+			clients[ip] = cl
+		}
+		cl.lastSeen = time.Now()
+		reservation := cl.limiter.Reserve()
+		mu.Unlock()
+
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			_ = c.Error(ErrRateLimited)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
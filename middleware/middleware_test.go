@@ -0,0 +1,355 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/logging"
+	"github.com/Mastermind730/igc-admin-backend/metrics"
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+func init() { gin.SetMode(gin.TestMode) }
+
+// captureLogs routes the default logger to a buffer for the duration of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(logging.NewHandler(&buf, "json", slog.LevelDebug)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"propagates a client ID", "abc-123", true},
+		{"generates a missing ID", "", false},
+		{"replaces a malformed ID", "bad id\nX-Injected: 1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inContext string
+			router := gin.New()
+			router.Use(RequestID())
+			router.GET("/", func(c *gin.Context) {
+				inContext = logging.RequestID(c.Request.Context())
+				c.Status(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			got := w.Header().Get(RequestIDHeader)
+			if tt.keep && got != tt.header {
+				t.Errorf("response ID = %q, want %q", got, tt.header)
+			}
+			if !tt.keep && (got == tt.header || len(got) != 32) {
+				t.Errorf("response ID = %q, want a generated one", got)
+			}
+			if inContext != got {
+				t.Errorf("context ID = %q, want %q", inContext, got)
+			}
+		})
+	}
+}
+
+func TestLogger(t *testing.T) {
+	logs := captureLogs(t)
+	router := gin.New()
+	router.Use(RequestID(), Logger())
+	router.GET("/teams/:id", func(c *gin.Context) {
+		logging.SetUser(c.Request.Context(), "u-1", "admin")
+		c.Status(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/teams/665f1?email=lead@example.org", nil)
+	req.Header.Set(RequestIDHeader, "req-7")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var rec map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &rec); err != nil {
+		t.Fatalf("log output %q is not one JSON record: %v", logs, err)
+	}
+	want := map[string]any{
+		"level":      "WARN",
+		"msg":        "request",
+		"route":      "/teams/:id",
+		"status":     float64(http.StatusNotFound),
+		"request_id": "req-7",
+		"user_id":    "u-1",
+		"role":       "admin",
+	}
+	for key, value := range want {
+		if rec[key] != value {
+			t.Errorf("%s = %v, want %v", key, rec[key], value)
+		}
+	}
+	if bytes.Contains(logs.Bytes(), []byte("665f1")) || bytes.Contains(logs.Bytes(), []byte("example.org")) {
+		t.Errorf("log leaks the raw path: %s", logs)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	deadline := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		err  error
+		want problem
+	}{
+		{
+			name: "not found",
+			err:  models.ErrTeamNotFound,
+			want: problem{status: http.StatusNotFound, code: models.CodeTeamNotFound, detail: "Team registration not found"},
+		},
+		{
+			name: "invalid ID",
+			err:  models.ErrInvalidUserID,
+			want: problem{status: http.StatusBadRequest, code: models.CodeInvalidID, detail: "Invalid user ID"},
+		},
+		{
+			name: "wrapped conflict",
+			err:  fmt.Errorf("failed to create event: %w", models.ErrEventExists),
+			want: problem{status: http.StatusConflict, code: models.CodeEventExists, detail: "Event slug or number prefix already exists"},
+		},
+		{
+			name: "extensions",
+			err:  models.ErrCertificateNotFound.With("valid", false),
+			want: problem{
+				status:     http.StatusNotFound,
+				code:       models.CodeCertificateNotFound,
+				detail:     "Certificate not found",
+				extensions: map[string]any{"valid": false},
+			},
+		},
+		{
+			name: "stale write",
+			err:  models.ErrVersionMismatch.With("current", 3),
+			want: problem{
+				status:     http.StatusPreconditionFailed,
+				code:       models.CodeVersionMismatch,
+				detail:     models.ErrVersionMismatch.Message,
+				extensions: map[string]any{"current": 3},
+			},
+		},
+		{
+			name: "lost write",
+			err:  fmt.Errorf("failed to update user: %w", models.ErrVersionConflict),
+			want: problem{status: http.StatusConflict, code: models.CodeVersionConflict, detail: models.ErrVersionConflict.Message},
+		},
+		{
+			name: "cause stays out of the detail",
+			err:  models.ErrUserNotFound.WithCause(errors.New("connection reset")),
+			want: problem{status: http.StatusNotFound, code: models.CodeUserNotFound, detail: "User not found"},
+		},
+		{
+			name: "deadline",
+			err:  &models.DeadlineError{Code: "registration_closed", Message: "Registration is closed", Deadline: deadline},
+			want: problem{
+				status:     http.StatusForbidden,
+				code:       "registration_closed",
+				detail:     "Registration is closed",
+				extensions: map[string]any{"deadline": deadline},
+			},
+		},
+		{
+			name: "rate limited",
+			err:  ErrRateLimited,
+			want: problem{status: http.StatusTooManyRequests, code: CodeRateLimited, detail: "Rate limit exceeded, please retry later"},
+		},
+		{
+			name: "metrics token",
+			err:  metrics.ErrInvalidToken,
+			want: problem{status: http.StatusUnauthorized, code: models.CodeTokenInvalid, detail: "Invalid metrics token"},
+		},
+		{
+			name: "domain error of unknown kind",
+			err:  &models.Error{Kind: errors.New("other"), Code: "other", Message: "Other"},
+			want: problem{status: http.StatusInternalServerError, code: models.CodeInternal, detail: "Something went wrong on our end"},
+		},
+		{
+			name: "unexpected error",
+			err:  errors.New("database unavailable"),
+			want: problem{status: http.StatusInternalServerError, code: models.CodeInternal, detail: "Something went wrong on our end"},
+		},
+		{
+			name: "panic",
+			err:  nil,
+			want: problem{status: http.StatusInternalServerError, code: models.CodeInternal, detail: "Something went wrong on our end"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("classify = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestKindStatus makes sure every error kind of the models has a status
+func TestKindStatus(t *testing.T) {
+	tests := []struct {
+		kind error
+		want int
+	}{
+		{models.ErrNotFound, http.StatusNotFound},
+		{models.ErrInvalidID, http.StatusBadRequest},
+		{models.ErrValidation, http.StatusBadRequest},
+		{models.ErrConflict, http.StatusConflict},
+		{models.ErrInvalidTransition, http.StatusConflict},
+		{models.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		{models.ErrForbidden, http.StatusForbidden},
+		{models.ErrUnauthorized, http.StatusUnauthorized},
+	}
+	if len(kindStatus) != len(tests) {
+		t.Errorf("kindStatus maps %d kinds, the test covers %d", len(kindStatus), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind.Error(), func(t *testing.T) {
+			if got, ok := kindStatus[tt.kind]; !ok || got != tt.want {
+				t.Errorf("kindStatus[%v] = %d, want %d", tt.kind, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	captureLogs(t)
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		status  int
+		want    map[string]any
+	}{
+		{
+			name:    "domain error",
+			handler: func(c *gin.Context) { _ = c.Error(models.ErrTeamNotFound.With("id", "42")) },
+			status:  http.StatusNotFound,
+			want: map[string]any{
+				"type":       "about:blank",
+				"title":      "Not Found",
+				"status":     float64(http.StatusNotFound),
+				"detail":     "Team registration not found",
+				"instance":   "/api/v1/teams/42",
+				"code":       models.CodeTeamNotFound,
+				"request_id": "req-1",
+				"id":         "42",
+			},
+		},
+		{
+			name:    "unexpected error",
+			handler: func(c *gin.Context) { _ = c.Error(errors.New("dial tcp 10.0.0.5:27017: connection refused")) },
+			status:  http.StatusInternalServerError,
+			want: map[string]any{
+				"status": float64(http.StatusInternalServerError),
+				"detail": "Something went wrong on our end",
+				"code":   models.CodeInternal,
+			},
+		},
+		{
+			name:    "panic",
+			handler: func(c *gin.Context) { panic("nil map") },
+			status:  http.StatusInternalServerError,
+			want:    map[string]any{"code": models.CodeInternal},
+		},
+		{
+			name: "response already written",
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusAccepted, gin.H{"ok": true})
+				_ = c.Error(errors.New("late failure"))
+			},
+			status: http.StatusAccepted,
+			want:   map[string]any{"ok": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(RequestID(), ErrorHandler())
+			router.GET("/api/v1/teams/:id", tt.handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/teams/42", nil)
+			req.Header.Set(RequestIDHeader, "req-1")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.status >= 400 {
+				if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+					t.Errorf("Content-Type = %q, want application/problem+json", got)
+				}
+				if bytes.Contains(w.Body.Bytes(), []byte("10.0.0.5")) {
+					t.Errorf("problem leaks the cause: %s", w.Body)
+				}
+			}
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body %q: %v", w.Body, err)
+			}
+			for key, value := range tt.want {
+				if body[key] != value {
+					t.Errorf("%s = %v, want %v", key, body[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitForwardedFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		// limited is whether the second request, from another forwarded client, is refused
+		limited bool
+	}{
+		{name: "spoofed header from an untrusted peer", proxies: nil, limited: true},
+		{name: "header from a trusted proxy", proxies: []string{"192.0.2.0/24"}, limited: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			if err := router.SetTrustedProxies(tt.proxies); err != nil {
+				t.Fatalf("SetTrustedProxies: %v", err)
+			}
+			router.Use(ErrorHandler(), RateLimit(1, 1))
+			router.GET("/verify", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			codes := make([]int, 0, 2)
+			for _, forwarded := range []string{"203.0.113.1", "203.0.113.2"} {
+				w := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, "/verify", nil)
+				req.RemoteAddr = "192.0.2.1:4321"
+				req.Header.Set("X-Forwarded-For", forwarded)
+				router.ServeHTTP(w, req)
+				codes = append(codes, w.Code)
+			}
+
+			if codes[0] != http.StatusOK {
+				t.Fatalf("first request = %d, want 200", codes[0])
+			}
+			if limited := codes[1] == http.StatusTooManyRequests; limited != tt.limited {
+				t.Errorf("second request = %d, limited %v, want %v", codes[1], limited, tt.limited)
+			}
+		})
+	}
+}
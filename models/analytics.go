@@ -0,0 +1,287 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// analyticsDateFormat is the day of the registration time series, in UTC
+const analyticsDateFormat = "2006-01-02"
+
+// AnalyticsFilter selects the registrations of an event that analytics cover; zero
+// values don't filter
+type AnalyticsFilter struct {
+	Status RegistrationStatus
+	// From and To bound the submission time: From is inclusive, To exclusive
+	From time.Time
+	To   time.Time
+}
+
+// match returns the query of the filter in an event
+func (f *AnalyticsFilter) match(eventID primitive.ObjectID) bson.M {
+	filter := bson.M{"eventId": eventID}
+	if f.Status != "" {
+		filter["registrationStatus"] = f.Status
+	}
+	submitted := bson.M{}
+	if !f.From.IsZero() {
+		submitted["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		submitted["$lt"] = f.To
+	}
+	if len(submitted) > 0 {
+		filter["submittedAt"] = submitted
+	}
+	return filter
+}
+
+// RegistrationAnalytics breaks the registrations of an event down by their properties
+type RegistrationAnalytics struct {
+	Total       int64       `json:"total"`
+	Status      []Bucket    `json:"status"`
+	Track       []Bucket    `json:"track"`
+	Institution []Bucket    `json:"institution"`
+	Program     []Bucket    `json:"program"`
+	Country     []Bucket    `json:"country"`
+	State       []Region    `json:"state"`
+	Gender      GenderRatio `json:"gender"`
+	// TeamSize counts teams by the number of participants, leader included
+	TeamSize []Bucket   `json:"teamSize"`
+	Video    VideoRate  `json:"video"`
+	Daily    []DayCount `json:"daily"`
+}
+
+// Bucket is the number of registrations with one value of a property, and their share
+// of the breakdown
+type Bucket struct {
+	Value interface{} `bson:"_id" json:"value"`
+	Count int64       `bson:"count" json:"count"`
+	Share float64     `bson:"-" json:"share"`
+}
+
+// Region is the number of registrations from a state of a country
+type Region struct {
+	Country string  `bson:"country" json:"country"`
+	State   string  `bson:"state" json:"state"`
+	Count   int64   `bson:"count" json:"count"`
+	Share   float64 `bson:"-" json:"share"`
+}
+
+// GenderRatio counts the participants of the registrations by gender
+type GenderRatio struct {
+	Leaders      []Bucket `json:"leaders"`
+	Members      []Bucket `json:"members"`
+	Participants []Bucket `json:"participants"`
+}
+
+// VideoRate is the share of registrations that submitted a video
+type VideoRate struct {
+	Teams     int64   `bson:"teams" json:"teams"`
+	Submitted int64   `bson:"submitted" json:"submitted"`
+	Rate      float64 `bson:"-" json:"rate"`
+}
+
+// DayCount is the number of registrations submitted on a day (UTC)
+type DayCount struct {
+	Date  string `bson:"_id" json:"date"`
+	Count int64  `bson:"count" json:"count"`
+	// Cumulative counts the registrations submitted up to and including the day
+	Cumulative int64 `bson:"-" json:"cumulative"`
+}
+
+// analyticsFacets is the raw output of the analytics pipeline
+type analyticsFacets struct {
+	Status       []Bucket    `bson:"status"`
+	Track        []Bucket    `bson:"track"`
+	Institution  []Bucket    `bson:"institution"`
+	Program      []Bucket    `bson:"program"`
+	Country      []Bucket    `bson:"country"`
+	State        []Region    `bson:"state"`
+	LeaderGender []Bucket    `bson:"leaderGender"`
+	MemberGender []Bucket    `bson:"memberGender"`
+	TeamSize     []Bucket    `bson:"teamSize"`
+	Video        []VideoRate `bson:"video"`
+	Daily        []DayCount  `bson:"daily"`
+}
+
+// analyticsPipeline computes every breakdown of the registrations matching filter in
+// one pass
+func analyticsPipeline(filter bson.M, videos *videoTeams) bson.A {
+	return bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$facet": bson.M{
+			"status":      countBy("$registrationStatus"),
+			"track":       countBy("$track"),
+			"institution": countBy("$institution"),
+			"program":     countBy("$program"),
+			"country":     countBy("$country"),
+			"state": bson.A{
+				bson.M{"$group": bson.M{"_id": bson.M{"country": "$country", "state": "$state"}, "count": bson.M{"$sum": 1}}},
+				bson.M{"$project": bson.M{"_id": 0, "country": "$_id.country", "state": "$_id.state", "count": 1}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "country", Value: 1}, {Key: "state", Value: 1}}},
+			},
+			"leaderGender": countBy("$leaderGender"),
+			"memberGender": append(bson.A{bson.M{"$unwind": "$members"}}, countBy("$members.gender")...),
+			"teamSize":     countBy(teamSizeExpr),
+			"video": bson.A{bson.M{"$group": bson.M{
+				"_id":       nil,
+				"teams":     bson.M{"$sum": 1},
+				"submitted": bson.M{"$sum": bson.M{"$cond": bson.A{videos.expr(), 1, 0}}},
+			}}},
+			"daily": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$submittedAt", "timezone": "UTC"}},
+					"count": bson.M{"$sum": 1},
+				}},
+				bson.M{"$sort": bson.M{"_id": 1}},
+			},
+		}},
+	}
+}
+
+// analytics derives the shares, totals and the gap-free time series from the raw facets
+func (f *analyticsFacets) analytics() *RegistrationAnalytics {
+	a := &RegistrationAnalytics{
+		Status:      withShares(f.Status),
+		Track:       withShares(f.Track),
+		Institution: withShares(f.Institution),
+		Program:     withShares(f.Program),
+		Country:     withShares(f.Country),
+		State:       f.State,
+		Gender: GenderRatio{
+			Leaders:      withShares(f.LeaderGender),
+			Members:      withShares(f.MemberGender),
+			Participants: withShares(mergeBuckets(f.LeaderGender, f.MemberGender)),
+		},
+		TeamSize: withShares(f.TeamSize),
+		Daily:    fillDays(f.Daily),
+	}
+	if a.State == nil {
+		a.State = []Region{}
+	}
+	for _, b := range a.Status {
+		a.Total += b.Count
+	}
+	for i := range a.State {
+		a.State[i].Share = share(a.State[i].Count, a.Total)
+	}
+	if len(f.Video) > 0 {
+		a.Video = f.Video[0]
+		a.Video.Rate = share(a.Video.Submitted, a.Video.Teams)
+	}
+	return a
+}
+
+// share returns count as a fraction of total
+func share(count, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
+
+// withShares sets the share of every bucket in the breakdown
+func withShares(buckets []Bucket) []Bucket {
+	if buckets == nil {
+		return []Bucket{}
+	}
+	var total int64
+	for _, b := range buckets {
+		total += b.Count
+	}
+	for i := range buckets {
+		buckets[i].Share = share(buckets[i].Count, total)
+	}
+	return buckets
+}
+
+// mergeBuckets adds up breakdowns of the same property, keeping the first order
+// of the values
+func mergeBuckets(breakdowns ...[]Bucket) []Bucket {
+	var merged []Bucket
+	index := map[interface{}]int{}
+	for _, buckets := range breakdowns {
+		for _, b := range buckets {
+			if i, ok := index[b.Value]; ok {
+				merged[i].Count += b.Count
+				continue
+			}
+			index[b.Value] = len(merged)
+			merged = append(merged, Bucket{Value: b.Value, Count: b.Count})
+		}
+	}
+	return merged
+}
+
+// fillDays adds the days without registrations between the first and the last one and
+// sets the running totals
+func fillDays(days []DayCount) []DayCount {
+	filled := []DayCount{}
+	if len(days) == 0 {
+		return filled
+	}
+	counts := make(map[string]int64, len(days))
+	for _, d := range days {
+		counts[d.Date] = d.Count
+	}
+	first, err1 := time.Parse(analyticsDateFormat, days[0].Date)
+	last, err2 := time.Parse(analyticsDateFormat, days[len(days)-1].Date)
+	if err1 != nil || err2 != nil {
+		return days
+	}
+	var cumulative int64
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format(analyticsDateFormat)
+		cumulative += counts[date]
+		filled = append(filled, DayCount{Date: date, Count: counts[date], Cumulative: cumulative})
+	}
+	return filled
+}
+
+// CSVRecords flattens the analytics into rows of breakdown, value, count and share,
+// headed by a header row
+func (a *RegistrationAnalytics) CSVRecords() [][]string {
+	records := [][]string{{"breakdown", "value", "count", "share"}}
+	add := func(breakdown string, value interface{}, count int64, share float64) {
+		records = append(records, []string{
+			breakdown,
+			fmt.Sprint(value),
+			strconv.FormatInt(count, 10),
+			strconv.FormatFloat(share, 'f', 4, 64),
+		})
+	}
+	buckets := func(breakdown string, buckets []Bucket) {
+		for _, b := range buckets {
+			value := b.Value
+			if value == nil {
+				value = ""
+			}
+			add(breakdown, value, b.Count, b.Share)
+		}
+	}
+
+	add("total", "", a.Total, share(a.Total, a.Total))
+	buckets("status", a.Status)
+	buckets("track", a.Track)
+	buckets("institution", a.Institution)
+	buckets("program", a.Program)
+	buckets("country", a.Country)
+	for _, r := range a.State {
+		add("state", r.Country+" / "+r.State, r.Count, r.Share)
+	}
+	buckets("gender.leaders", a.Gender.Leaders)
+	buckets("gender.members", a.Gender.Members)
+	buckets("gender.participants", a.Gender.Participants)
+	buckets("teamSize", a.TeamSize)
+	add("video", "submitted", a.Video.Submitted, a.Video.Rate)
+	add("video", "missing", a.Video.Teams-a.Video.Submitted, share(a.Video.Teams-a.Video.Submitted, a.Video.Teams))
+	for _, d := range a.Daily {
+		add("daily", d.Date, d.Count, share(d.Count, a.Total))
+	}
+	return records
+}
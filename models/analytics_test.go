@@ -0,0 +1,163 @@
+package models
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestAnalyticsFilterMatch(t *testing.T) {
+	eventID := primitive.NewObjectID()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		filter AnalyticsFilter
+		want   bson.M
+	}{
+		{"everything", AnalyticsFilter{}, bson.M{"eventId": eventID}},
+		{"status", AnalyticsFilter{Status: StatusApproved}, bson.M{"eventId": eventID, "registrationStatus": StatusApproved}},
+		{"from", AnalyticsFilter{From: from}, bson.M{"eventId": eventID, "submittedAt": bson.M{"$gte": from}}},
+		{"range", AnalyticsFilter{From: from, To: to}, bson.M{"eventId": eventID, "submittedAt": bson.M{"$gte": from, "$lt": to}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.match(eventID); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// sampleFacets is the pipeline output for four teams: three approved and one pending
+func sampleFacets() *analyticsFacets {
+	return &analyticsFacets{
+		Status:       []Bucket{{Value: "approved", Count: 3}, {Value: "pending", Count: 1}},
+		Track:        []Bucket{{Value: "AI", Count: 4}},
+		State:        []Region{{Country: "India", State: "Maharashtra", Count: 3}, {Country: "India", State: "Goa", Count: 1}},
+		LeaderGender: []Bucket{{Value: "female", Count: 3}, {Value: "male", Count: 1}},
+		MemberGender: []Bucket{{Value: "male", Count: 4}, {Value: "other", Count: 2}},
+		Video:        []VideoRate{{Teams: 4, Submitted: 1}},
+		Daily:        []DayCount{{Date: "2026-03-01", Count: 2}, {Date: "2026-03-04", Count: 2}},
+	}
+}
+
+func TestAnalytics(t *testing.T) {
+	a := sampleFacets().analytics()
+
+	if a.Total != 4 {
+		t.Errorf("total = %d, want 4", a.Total)
+	}
+	if a.Status[0].Share != 0.75 || a.Status[1].Share != 0.25 {
+		t.Errorf("status shares = %v, %v; want 0.75, 0.25", a.Status[0].Share, a.Status[1].Share)
+	}
+	if a.State[0].Share != 0.75 {
+		t.Errorf("Maharashtra share = %v, want 0.75", a.State[0].Share)
+	}
+	wantParticipants := []Bucket{{Value: "female", Count: 3, Share: 0.3}, {Value: "male", Count: 5, Share: 0.5}, {Value: "other", Count: 2, Share: 0.2}}
+	if !reflect.DeepEqual(a.Gender.Participants, wantParticipants) {
+		t.Errorf("participants = %+v, want %+v", a.Gender.Participants, wantParticipants)
+	}
+	if a.Video.Rate != 0.25 {
+		t.Errorf("video rate = %v, want 0.25", a.Video.Rate)
+	}
+	wantDaily := []DayCount{
+		{Date: "2026-03-01", Count: 2, Cumulative: 2},
+		{Date: "2026-03-02", Count: 0, Cumulative: 2},
+		{Date: "2026-03-03", Count: 0, Cumulative: 2},
+		{Date: "2026-03-04", Count: 2, Cumulative: 4},
+	}
+	if !reflect.DeepEqual(a.Daily, wantDaily) {
+		t.Errorf("daily = %+v, want %+v", a.Daily, wantDaily)
+	}
+	// Empty breakdowns render as [] rather than null
+	if a.Institution == nil || a.Program == nil || a.TeamSize == nil {
+		t.Error("an empty breakdown is nil")
+	}
+}
+
+func TestAnalyticsWithoutRegistrations(t *testing.T) {
+	a := (&analyticsFacets{}).analytics()
+	if a.Total != 0 || a.Video.Rate != 0 || len(a.Daily) != 0 || a.State == nil || a.Daily == nil {
+		t.Errorf("analytics = %+v, want zero counts and empty breakdowns", a)
+	}
+}
+
+func TestCSVRecords(t *testing.T) {
+	records := sampleFacets().analytics().CSVRecords()
+
+	want := map[int][]string{
+		0: {"breakdown", "value", "count", "share"},
+		1: {"total", "", "4", "1.0000"},
+		2: {"status", "approved", "3", "0.7500"},
+		5: {"state", "India / Maharashtra", "3", "0.7500"},
+	}
+	for i, row := range want {
+		if !reflect.DeepEqual(records[i], row) {
+			t.Errorf("row %d = %q, want %q", i, records[i], row)
+		}
+	}
+	var video [][]string
+	for _, r := range records {
+		if r[0] == "video" {
+			video = append(video, r)
+		}
+	}
+	if !reflect.DeepEqual(video, [][]string{{"video", "submitted", "1", "0.2500"}, {"video", "missing", "3", "0.7500"}}) {
+		t.Errorf("video rows = %q", video)
+	}
+	if last := records[len(records)-1]; !reflect.DeepEqual(last, []string{"daily", "2026-03-04", "2", "0.5000"}) {
+		t.Errorf("last row = %q, want the last day", last)
+	}
+}
+
+func TestGetRegistrationAnalytics(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("runs the breakdowns in one aggregation", func(mt *mtest.T) {
+		eventID := primitive.NewObjectID()
+		for range len(videoRegistrationFields) + 2 {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{}}))
+		}
+		mt.AddMockResponses(found("teamregistrations", doc(mt, bson.M{
+			"status": bson.A{bson.M{"_id": "approved", "count": 2}},
+			"video":  bson.A{bson.M{"_id": nil, "teams": 2, "submitted": 1}},
+		})))
+
+		filter := &AnalyticsFilter{Status: StatusApproved}
+		a, err := newTestDB(mt).GetRegistrationAnalytics(context.Background(), eventID, filter)
+		if err != nil {
+			mt.Fatalf("GetRegistrationAnalytics: %v", err)
+		}
+		if a.Total != 2 || a.Video.Rate != 0.5 {
+			mt.Errorf("analytics = total %d, video rate %v; want 2, 0.5", a.Total, a.Video.Rate)
+		}
+
+		for range len(videoRegistrationFields) + 2 {
+			nextCommand(mt, "distinct")
+		}
+		pipeline := nextCommand(mt, "aggregate").Lookup("pipeline").Array()
+		match := pipeline.Index(0).Value().Document().Lookup("$match").Document()
+		if match.Lookup("eventId").ObjectID() != eventID || match.Lookup("registrationStatus").StringValue() != "approved" {
+			mt.Errorf("analytics cover %v, want the approved teams of the event", match)
+		}
+	})
+
+	mt.Run("an event without registrations", func(mt *mtest.T) {
+		for range len(videoRegistrationFields) + 2 {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{}}))
+		}
+		mt.AddMockResponses(found("teamregistrations"))
+
+		a, err := newTestDB(mt).GetRegistrationAnalytics(context.Background(), primitive.NewObjectID(), &AnalyticsFilter{})
+		if err != nil || a.Total != 0 {
+			mt.Fatalf("GetRegistrationAnalytics = %+v, %v; want empty analytics", a, err)
+		}
+	})
+}
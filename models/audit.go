@@ -0,0 +1,219 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditAction names a mutation recorded in the audit log, as <target>.<verb>
+type AuditAction string
+
+const (
+	AuditUserCreate          AuditAction = "user.create"
+	AuditUserUpdate          AuditAction = "user.update"
+	AuditUserDelete          AuditAction = "user.delete"
+	AuditUserRestore         AuditAction = "user.restore"
+	AuditTeamCreate          AuditAction = "team.create"
+	AuditTeamUpdate          AuditAction = "team.update"
+	AuditTeamApprove         AuditAction = "team.approve"
+	AuditTeamReject          AuditAction = "team.reject"
+	AuditTeamDelete          AuditAction = "team.delete"
+	AuditTeamRestore         AuditAction = "team.restore"
+	AuditTeamPromote         AuditAction = "team.promote"
+	AuditTeamAllocate        AuditAction = "team.allocate"
+	AuditTeamEvaluate        AuditAction = "team.evaluate"
+	AuditChangeRequestCreate AuditAction = "changeRequest.create"
+	AuditChangeRequestReview AuditAction = "changeRequest.review"
+	AuditEventCreate         AuditAction = "event.create"
+	AuditEventUpdate         AuditAction = "event.update"
+	AuditEventActivate       AuditAction = "event.activate"
+	AuditEventConfigUpdate   AuditAction = "eventConfig.update"
+	AuditCatalogCreate       AuditAction = "catalog.create"
+	AuditCatalogUpdate       AuditAction = "catalog.update"
+	AuditCatalogDelete       AuditAction = "catalog.delete"
+	AuditCertificateGenerate AuditAction = "certificate.generate"
+)
+
+// Audit target types
+const (
+	AuditTargetUser          = "user"
+	AuditTargetTeam          = "team"
+	AuditTargetEvaluation    = "evaluation"
+	AuditTargetChangeRequest = "changeRequest"
+	AuditTargetEvent         = "event"
+	AuditTargetCatalog       = "catalog"
+	AuditTargetCertificate   = "certificate"
+)
+
+// AuditSystemActor is the actor of changes the application makes on its own, such as
+// promoting waitlisted teams into freed capacity
+var AuditSystemActor = AuditActor{Username: "system", Role: "system"}
+
+// AuditSortFields lists the fields the audit log can be sorted by
+var AuditSortFields = SortFields{"createdAt"}
+
+// auditRedacted lists the fields whose values never enter the audit log; a change is
+// recorded without them
+var auditRedacted = []string{"password"}
+
+// auditIgnored lists the fields that change with every write and aren't recorded
+var auditIgnored = []string{"id", "updatedAt", "version"}
+
+// AuditEntry records one mutation: who made it, from where, and what it changed.
+// Entries are only ever inserted.
+type AuditEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID   primitive.ObjectID `bson:"eventId,omitempty" json:"eventId,omitempty"`
+	Actor     AuditActor         `bson:"actor" json:"actor"`
+	Action    AuditAction        `bson:"action" json:"action"`
+	Target    AuditTarget        `bson:"target" json:"target"`
+	Changes   []AuditChange      `bson:"changes,omitempty" json:"changes,omitempty"`
+	IP        string             `bson:"ip" json:"ip"`
+	RequestID string             `bson:"requestId,omitempty" json:"requestId,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// AuditActor is the authenticated user who made a change, as named by their token
+type AuditActor struct {
+	// ID is the user ID, or the team ID for participants
+	ID       string `bson:"id" json:"id"`
+	Username string `bson:"username" json:"username"`
+	Role     string `bson:"role" json:"role"`
+}
+
+// AuditTarget is the record a change was made to
+type AuditTarget struct {
+	Type string `bson:"type" json:"type"`
+	ID   string `bson:"id" json:"id"`
+}
+
+// AuditChange is the value of a field before and after a change. A field without a
+// before value was added, one without an after value was removed.
+type AuditChange struct {
+	Field  string     `bson:"field" json:"field"`
+	Before AuditValue `bson:"before,omitempty" json:"before,omitempty"`
+	After  AuditValue `bson:"after,omitempty" json:"after,omitempty"`
+	// Redacted is set for secret fields, whose values aren't recorded
+	Redacted bool `bson:"redacted,omitempty" json:"redacted,omitempty"`
+}
+
+// AuditValue is a field value as JSON text, rendered as the value itself in responses
+type AuditValue string
+
+// MarshalJSON writes the value as the JSON it holds
+func (v AuditValue) MarshalJSON() ([]byte, error) {
+	if v == "" {
+		return []byte("null"), nil
+	}
+	return []byte(v), nil
+}
+
+// AuditDiff compares the JSON forms of a record before and after a change and returns
+// the changed fields. before is nil for created records and after for deleted ones, in
+// which case every field is listed.
+func AuditDiff(before, after interface{}) ([]AuditChange, error) {
+	old, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(old)+len(updated))
+	for field := range old {
+		fields = append(fields, field)
+	}
+	for field := range updated {
+		if _, ok := old[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var changes []AuditChange
+	for _, field := range fields {
+		if slices.Contains(auditIgnored, field) || bytes.Equal(old[field], updated[field]) {
+			continue
+		}
+		change := AuditChange{Field: field, Before: AuditValue(old[field]), After: AuditValue(updated[field])}
+		if slices.Contains(auditRedacted, field) {
+			change = AuditChange{Field: field, Redacted: true}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// auditFields returns the top-level fields of the JSON form of v
+func auditFields(v interface{}) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if v == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return fields, nil
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for field, value := range fields {
+		if bytes.Equal(value, []byte("null")) {
+			delete(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// AuditFilter selects audit log entries; zero values don't filter
+type AuditFilter struct {
+	EventID    primitive.ObjectID
+	ActorID    string
+	Actor      string
+	Action     AuditAction
+	TargetType string
+	TargetID   string
+	// From and To bound the time of the change: From is inclusive, To exclusive
+	From time.Time
+	To   time.Time
+}
+
+// query returns the filter as a query of the audit log
+func (f *AuditFilter) query() bson.M {
+	query := bson.M{}
+	if !f.EventID.IsZero() {
+		query["eventId"] = f.EventID
+	}
+	for field, value := range map[string]string{
+		"actor.id":       f.ActorID,
+		"actor.username": f.Actor,
+		"action":         string(f.Action),
+		"target.type":    f.TargetType,
+		"target.id":      f.TargetID,
+	} {
+		if value != "" {
+			query[field] = value
+		}
+	}
+	created := bson.M{}
+	if !f.From.IsZero() {
+		created["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		created["$lt"] = f.To
+	}
+	if len(created) > 0 {
+		query["createdAt"] = created
+	}
+	return query
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAuditDiff(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name   string
+		before interface{}
+		after  interface{}
+		want   []AuditChange
+	}{
+		{
+			name:   "unchanged",
+			before: map[string]any{"username": "alice", "role": "admin"},
+			after:  map[string]any{"username": "alice", "role": "admin"},
+			want:   nil,
+		},
+		{
+			name:   "changed field",
+			before: map[string]any{"username": "alice", "role": "judge"},
+			after:  map[string]any{"username": "alice", "role": "admin"},
+			want:   []AuditChange{{Field: "role", Before: `"judge"`, After: `"admin"`}},
+		},
+		{
+			name:   "created record lists every field",
+			before: nil,
+			after:  map[string]any{"username": "alice", "role": "admin"},
+			want: []AuditChange{
+				{Field: "role", After: `"admin"`},
+				{Field: "username", After: `"alice"`},
+			},
+		},
+		{
+			name:   "deleted record lists every field",
+			before: map[string]any{"username": "alice"},
+			after:  nil,
+			want:   []AuditChange{{Field: "username", Before: `"alice"`}},
+		},
+		{
+			name:   "null fields count as missing",
+			before: map[string]any{"username": "alice", "deletedAt": nil},
+			after:  map[string]any{"username": "alice", "deletedAt": "2026-01-02"},
+			want:   []AuditChange{{Field: "deletedAt", After: `"2026-01-02"`}},
+		},
+		{
+			name:   "password is redacted",
+			before: &User{Username: "alice", Password: "old-hash"},
+			after:  &User{Username: "alice", Password: "new-hash"},
+			want:   []AuditChange{{Field: "password", Redacted: true}},
+		},
+		{
+			name:   "added password is redacted",
+			before: nil,
+			after:  map[string]any{"password": "hash"},
+			want:   []AuditChange{{Field: "password", Redacted: true}},
+		},
+		{
+			name:   "ignored fields aren't recorded",
+			before: map[string]any{"id": "1", "version": 1, "updatedAt": "2026-01-01", "role": "judge"},
+			after:  map[string]any{"id": "2", "version": 2, "updatedAt": updatedAt, "role": "judge"},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AuditDiff(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("AuditDiff: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AuditDiff = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditDiffUnmarshalable(t *testing.T) {
+	if _, err := AuditDiff(nil, map[string]any{"ch": make(chan int)}); err == nil {
+		t.Error("AuditDiff of an unmarshalable value returned no error")
+	}
+}
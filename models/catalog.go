@@ -0,0 +1,121 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CatalogKind identifies an admin-managed list of registration options
+type CatalogKind string
+
+const (
+	CatalogTrack   CatalogKind = "track"
+	CatalogProgram CatalogKind = "program"
+)
+
+// DefaultPrograms are the programs of the original edition, used when seeding the catalogue
+var DefaultPrograms = []Program{
+	ProgramBTechCS,
+	ProgramBTechIT,
+	ProgramBTechEC,
+	ProgramBTechMech,
+	ProgramBTechCivil,
+	ProgramBTechEE,
+	ProgramMTechCS,
+	ProgramMTechIT,
+	ProgramMTechEC,
+	ProgramMCA,
+	ProgramMBA,
+	ProgramOther,
+}
+
+// CatalogEntry is a track or program teams can register for.
+// Team registrations store the entry's Name, so renaming an entry migrates them.
+type CatalogEntry struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Kind        CatalogKind        `bson:"kind" json:"kind"`
+	Slug        string             `bson:"slug" json:"slug"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	// Active entries are offered on the registration form; inactive ones stay valid on existing teams
+	Active bool `bson:"active" json:"active"`
+	// Capacity limits approved teams per track, or registrations per program (0 = unlimited)
+	Capacity int `bson:"capacity,omitempty" json:"capacity,omitempty"`
+	// PreviousNames are names the entry was renamed from; submissions using them are
+	// mapped to the current name
+	PreviousNames []string  `bson:"previousNames,omitempty" json:"previousNames,omitempty"`
+	CreatedAt     time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Rename changes the entry's name, remembering the old one
+func (e *CatalogEntry) Rename(name string) {
+	name = strings.TrimSpace(name)
+	if name == e.Name {
+		return
+	}
+	previous := make([]string, 0, len(e.PreviousNames)+1)
+	for _, n := range append(e.PreviousNames, e.Name) {
+		if n != name {
+			previous = append(previous, n)
+		}
+	}
+	e.PreviousNames = previous
+	e.Name = name
+}
+
+// NewCatalogEntry creates an active catalogue entry, deriving the slug from the name if empty
+func NewCatalogEntry(kind CatalogKind, slug, name string) *CatalogEntry {
+	if slug == "" {
+		slug = Slugify(name)
+	}
+	now := time.Now()
+	return &CatalogEntry{
+		Kind:      kind,
+		Slug:      slug,
+		Name:      strings.TrimSpace(name),
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate checks the entry for inconsistent values
+func (e *CatalogEntry) Validate() error {
+	if e.Kind != CatalogTrack && e.Kind != CatalogProgram {
+		return validationErrorf(CodeInvalidCatalogEntry, "Unknown catalogue kind %q", e.Kind)
+	}
+	if !slugPattern.MatchString(e.Slug) {
+		return validationErrorf(CodeInvalidCatalogEntry, "Slug must be lowercase letters, digits and dashes")
+	}
+	if e.Name == "" {
+		return validationErrorf(CodeInvalidCatalogEntry, "Name is required")
+	}
+	// Track names are used as keys of EventConfig.TrackCapacity
+	if e.Kind == CatalogTrack && strings.ContainsAny(e.Name, ".$") {
+		return validationErrorf(CodeInvalidCatalogEntry, "Track name cannot contain '.' or '$'")
+	}
+	if e.Capacity < 0 {
+		return validationErrorf(CodeInvalidCatalogEntry, "Capacity cannot be negative")
+	}
+	return nil
+}
+
+// Slugify derives a URL-friendly slug from a display name
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
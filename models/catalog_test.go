@@ -0,0 +1,70 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMigrateCatalogName(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	pastEvent := primitive.NewObjectID()
+	readOnly := mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{pastEvent}})
+
+	// filterOf returns the filter of an update command, failing unless it updates collection
+	filterOf := func(mt *mtest.T, cmd bson.Raw, collection string) bson.Raw {
+		mt.Helper()
+		if got := cmd.Lookup("update").StringValue(); got != collection {
+			mt.Fatalf("updated %s, want %s", got, collection)
+		}
+		return cmd.Lookup("updates").Array().Index(0).Value().Document().Lookup("q").Document()
+	}
+
+	mt.Run("renames tracks of writable events only", func(mt *mtest.T) {
+		mt.AddMockResponses(readOnly, updated(2), updated(1), updated(1))
+
+		if err := newTestDB(mt).migrateCatalogName(context.Background(), CatalogTrack, "Robotics", "Robotics & AI"); err != nil {
+			mt.Fatalf("migrateCatalogName: %v", err)
+		}
+
+		lookup := nextCommand(mt, "distinct")
+		if lookup.Lookup("key").StringValue() != "_id" || !lookup.Lookup("query", "readOnly").Boolean() {
+			mt.Errorf("lookup = %v, want the IDs of read-only events", lookup)
+		}
+		teams := filterOf(mt, nextCommand(mt, "update"), "teamregistrations")
+		excluded := teams.Lookup("eventId", "$nin").Array().Index(0).Value().ObjectID()
+		if teams.Lookup("track").StringValue() != "Robotics" || excluded != pastEvent {
+			mt.Errorf("team filter = %v, want Robotics teams outside the past event", teams)
+		}
+		for range 2 {
+			events := filterOf(mt, nextCommand(mt, "update"), "events")
+			if events.Lookup("readOnly", "$ne").Boolean() != true {
+				mt.Errorf("event filter = %v, want writable events only", events)
+			}
+		}
+		if event := mt.GetStartedEvent(); event != nil {
+			mt.Errorf("sent %s %v, want certificates left as issued", event.CommandName, event.Command)
+		}
+	})
+
+	mt.Run("renames programs on team registrations only", func(mt *mtest.T) {
+		mt.AddMockResponses(readOnly, updated(1))
+
+		if err := newTestDB(mt).migrateCatalogName(context.Background(), CatalogProgram, "Juniors", "Junior League"); err != nil {
+			mt.Fatalf("migrateCatalogName: %v", err)
+		}
+
+		nextCommand(mt, "distinct")
+		teams := filterOf(mt, nextCommand(mt, "update"), "teamregistrations")
+		if teams.Lookup("program").StringValue() != "Juniors" {
+			mt.Errorf("team filter = %v, want Juniors teams", teams)
+		}
+		if event := mt.GetStartedEvent(); event != nil {
+			mt.Errorf("sent %s after renaming the teams", event.CommandName)
+		}
+	})
+}
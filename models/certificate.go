@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CertificateType enum type
+type CertificateType string
+
+const (
+	CertificateParticipation CertificateType = "participation"
+	CertificateWinner        CertificateType = "winner"
+)
+
+// RecipientRole describes the recipient's role within the team
+type RecipientRole string
+
+const (
+	RecipientLeader RecipientRole = "leader"
+	RecipientMember RecipientRole = "member"
+)
+
+// Certificate represents an issued participation or winner certificate
+type Certificate struct {
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID            primitive.ObjectID `bson:"eventId" json:"eventId"`
+	Code               string             `bson:"code" json:"code"`
+	Type               CertificateType    `bson:"type" json:"type"`
+	RecipientName      string             `bson:"recipientName" json:"recipientName"`
+	RecipientRole      RecipientRole      `bson:"recipientRole" json:"recipientRole"`
+	TeamRegistrationID primitive.ObjectID `bson:"teamRegistrationId" json:"teamRegistrationId"`
+	RegistrationNumber string             `bson:"registrationNumber" json:"registrationNumber"`
+	TeamName           string             `bson:"teamName" json:"teamName"`
+	Institution        string             `bson:"institution" json:"institution"`
+	Track              Track              `bson:"track" json:"track"`
+	Award              string             `bson:"award,omitempty" json:"award,omitempty"`
+	IssuedBy           string             `bson:"issuedBy,omitempty" json:"issuedBy,omitempty"`
+	IssuedAt           time.Time          `bson:"issuedAt" json:"issuedAt"`
+}
+
+// NewCertificate creates a certificate for one recipient of a team
+func NewCertificate(team *TeamRegistration, certType CertificateType, name string, role RecipientRole, award string) *Certificate {
+	return &Certificate{
+		EventID:            team.EventID,
+		Type:               certType,
+		RecipientName:      name,
+		RecipientRole:      role,
+		TeamRegistrationID: team.ID,
+		RegistrationNumber: team.RegistrationNumber,
+		TeamName:           team.TeamName,
+		Institution:        team.Institution,
+		Track:              team.Track,
+		Award:              award,
+		IssuedAt:           time.Now(),
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestFindCertificate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	teamID := primitive.NewObjectID()
+
+	mt.Run("winner certificates per award", func(mt *mtest.T) {
+		mt.AddMockResponses(found("certificates"))
+
+		cert, err := newTestDB(mt).FindCertificate(context.Background(), teamID, CertificateWinner, "Asha Rao", "Best Design")
+		if err != nil || cert != nil {
+			mt.Fatalf("FindCertificate = %v, %v; want no certificate", cert, err)
+		}
+		filter := nextCommand(mt, "find").Lookup("filter").Document()
+		if got := filter.Lookup("award").StringValue(); got != "Best Design" {
+			mt.Errorf("award = %q, want Best Design", got)
+		}
+		if filter.Lookup("recipientName").StringValue() != "Asha Rao" || filter.Lookup("teamRegistrationId").ObjectID() != teamID {
+			mt.Errorf("filter = %v, want the recipient of the team", filter)
+		}
+	})
+
+	mt.Run("certificates without an award", func(mt *mtest.T) {
+		issued := &Certificate{ID: primitive.NewObjectID(), TeamRegistrationID: teamID, Type: CertificateParticipation, RecipientName: "Asha Rao"}
+		mt.AddMockResponses(found("certificates", doc(mt, issued)))
+
+		cert, err := newTestDB(mt).FindCertificate(context.Background(), teamID, CertificateParticipation, "Asha Rao", "")
+		if err != nil || cert == nil || cert.ID != issued.ID {
+			mt.Fatalf("FindCertificate = %v, %v; want the issued certificate", cert, err)
+		}
+		if got := nextCommand(mt, "find").Lookup("filter", "award").Type; got != bson.TypeNull {
+			mt.Errorf("award match = %v, want certificates without an award", got)
+		}
+	})
+}
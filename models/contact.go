@@ -0,0 +1,143 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+// ErrInvalidPhone is returned for numbers that can't be read as a valid phone number
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// countryRegions maps the country names registrations use to their ISO 3166 region,
+// which is the default region of phone numbers written without a country code
+var countryRegions = map[string]string{
+	"afghanistan":          "AF",
+	"australia":            "AU",
+	"bangladesh":           "BD",
+	"bhutan":               "BT",
+	"brazil":               "BR",
+	"canada":               "CA",
+	"china":                "CN",
+	"egypt":                "EG",
+	"france":               "FR",
+	"germany":              "DE",
+	"ghana":                "GH",
+	"india":                "IN",
+	"indonesia":            "ID",
+	"iran":                 "IR",
+	"iraq":                 "IQ",
+	"ireland":              "IE",
+	"italy":                "IT",
+	"japan":                "JP",
+	"kenya":                "KE",
+	"malaysia":             "MY",
+	"maldives":             "MV",
+	"mauritius":            "MU",
+	"myanmar":              "MM",
+	"nepal":                "NP",
+	"netherlands":          "NL",
+	"new zealand":          "NZ",
+	"nigeria":              "NG",
+	"oman":                 "OM",
+	"pakistan":             "PK",
+	"philippines":          "PH",
+	"qatar":                "QA",
+	"russia":               "RU",
+	"saudi arabia":         "SA",
+	"singapore":            "SG",
+	"south africa":         "ZA",
+	"south korea":          "KR",
+	"spain":                "ES",
+	"sri lanka":            "LK",
+	"tanzania":             "TZ",
+	"thailand":             "TH",
+	"uae":                  "AE",
+	"united arab emirates": "AE",
+	"uganda":               "UG",
+	"uk":                   "GB",
+	"united kingdom":       "GB",
+	"usa":                  "US",
+	"united states":        "US",
+	"vietnam":              "VN",
+}
+
+// CountryRegion returns the ISO 3166 region of a country name or code, or "" when
+// the country is unknown
+func CountryRegion(country string) string {
+	country = strings.TrimSpace(country)
+	if region, ok := countryRegions[strings.ToLower(country)]; ok {
+		return region
+	}
+	if len(country) == 2 {
+		region := strings.ToUpper(country)
+		if phonenumbers.GetCountryCodeForRegion(region) != 0 {
+			return region
+		}
+	}
+	return ""
+}
+
+// NormalizePhone returns number in E.164 format. Numbers without a country code are
+// read as numbers of country; numbers that can't be read return ErrInvalidPhone.
+func NormalizePhone(number, country string) (string, error) {
+	number = strings.TrimSpace(number)
+	if number == "" {
+		return "", nil
+	}
+	region := CountryRegion(country)
+	if region == "" {
+		// Without a region only international numbers can be read
+		region = "ZZ"
+	}
+	parsed, err := phonenumbers.Parse(number, region)
+	if err != nil || !phonenumbers.IsValidNumber(parsed) {
+		return number, ErrInvalidPhone
+	}
+	return phonenumbers.Format(parsed, phonenumbers.E164), nil
+}
+
+// NormalizeEmail trims and lowercases an email address
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeContacts normalizes the emails and phone numbers of the team in place.
+// Phone numbers that can't be read are kept as they are and returned as field errors.
+func (t *TeamRegistration) NormalizeContacts() []FieldError {
+	var invalid []FieldError
+	phone := func(field string, value *string) {
+		normalized, err := NormalizePhone(*value, t.Country)
+		if err != nil {
+			invalid = append(invalid, FieldError{Field: field, Code: FieldInvalidPhone, Message: fmt.Sprintf("Cannot read %q as a phone number", *value)})
+		}
+		*value = normalized
+	}
+
+	t.LeaderEmail = NormalizeEmail(t.LeaderEmail)
+	t.MentorEmail = NormalizeEmail(t.MentorEmail)
+	phone("leaderMobile", &t.LeaderMobile)
+	phone("mentorMobile", &t.MentorMobile)
+	for i := range t.Members {
+		t.Members[i].Email = NormalizeEmail(t.Members[i].Email)
+		phone(fmt.Sprintf("members[%d].mobileNo", i), &t.Members[i].MobileNo)
+	}
+	return invalid
+}
+
+// ContactReport is the outcome of normalizing the contacts of stored registrations
+type ContactReport struct {
+	Scanned int64 `json:"scanned"`
+	Updated int64 `json:"updated"`
+	// Invalid lists the phone numbers that couldn't be read; they are left unchanged
+	Invalid []InvalidContact `json:"invalid,omitempty"`
+}
+
+// InvalidContact is a stored phone number that couldn't be normalized
+type InvalidContact struct {
+	TeamID             string `json:"teamId"`
+	RegistrationNumber string `json:"registrationNumber,omitempty"`
+	FieldError
+}
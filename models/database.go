@@ -4,14 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"log/slog"
+	"regexp"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/Mastermind730/igc-admin-backend/logging"
+	"github.com/Mastermind730/igc-admin-backend/metrics"
+	"github.com/Mastermind730/igc-admin-backend/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // Database service struct
@@ -21,37 +28,207 @@ type DatabaseService struct {
 	UserCollection *mongo.Collection
 	TeamCollection *mongo.Collection
 	Videos         *mongo.Collection
+	Certificates   *mongo.Collection
+	MagicLinks     *mongo.Collection
+	ChangeRequests *mongo.Collection
+	Events         *mongo.Collection
+	Evaluations    *mongo.Collection
+	Catalog        *mongo.Collection
+	AuditLog       *mongo.Collection
+	Counters       *mongo.Collection
+	Logger         *slog.Logger
 }
 
-// NewDatabaseService creates a new database service
-func NewDatabaseService(client *mongo.Client, dbName string) *DatabaseService {
+// NewDatabaseService creates a new database service. videoCollectionName is the
+// collection the video submission form writes to.
+func NewDatabaseService(client *mongo.Client, dbName, videoCollectionName string) *DatabaseService {
 	db := client.Database(dbName)
 
-	// Choose the videos collection name via env, default to "videos"
-	videoCollectionName := os.Getenv("VIDEO_COLLECTION_NAME")
-	if videoCollectionName == "" {
-		videoCollectionName = "videos"
-	}
-
 	return &DatabaseService{
 		Client:         client,
 		Database:       db,
 		UserCollection: db.Collection("users"),
 		TeamCollection: db.Collection("teamregistrations"),
 		Videos:         db.Collection(videoCollectionName),
+		Certificates:   db.Collection("certificates"),
+		MagicLinks:     db.Collection("magiclinks"),
+		ChangeRequests: db.Collection("changerequests"),
+		Events:         db.Collection("events"),
+		Evaluations:    db.Collection("evaluations"),
+		Catalog:        db.Collection("catalog"),
+		AuditLog:       db.Collection("audit_log"),
+		Counters:       db.Collection("counters"),
+		Logger:         slog.Default().With("component", "database"),
+	}
+}
+
+// indexSet is the indexes required on one collection
+type indexSet struct {
+	collection *mongo.Collection
+	indexes    []mongo.IndexModel
+}
+
+// requiredIndexes lists the indexes the application relies on
+func (db *DatabaseService) requiredIndexes() []indexSet {
+	return []indexSet{
+		{db.Certificates, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "code", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "teamRegistrationId", Value: 1}, {Key: "type", Value: 1}}},
+		}},
+		// Expired magic links are removed by MongoDB's TTL monitor
+		{db.MagicLinks, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "tokenHash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		}},
+		{db.ChangeRequests, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "teamRegistrationId", Value: 1}, {Key: "status", Value: 1}}},
+		}},
+		{db.Events, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+		}},
+		{db.TeamCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "track", Value: 1}, {Key: "registrationStatus", Value: 1}}},
+			{Keys: bson.D{{Key: "registrationNumber", Value: 1}}},
+			// Trash listing and retention purge
+			{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "deletedAt", Value: 1}}},
+			{Keys: bson.D{{Key: "deletedAt", Value: 1}}},
+			// Default order of the paged team lists
+			{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "registrationStatus", Value: 1}, {Key: "submittedAt", Value: -1}, {Key: "_id", Value: -1}}},
+			// Duplicate participant lookups
+			{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "leaderEmail", Value: 1}}},
+			{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "leaderMobile", Value: 1}}},
+			{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "members.email", Value: 1}}},
+			{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "members.mobileNo", Value: 1}}},
+			// Full-text team search; a collection can have only one text index
+			{Keys: bson.D{
+				{Key: "teamName", Value: "text"},
+				{Key: "topicName", Value: "text"},
+				{Key: "topicDescription", Value: "text"},
+				{Key: "leaderName", Value: "text"},
+				{Key: "members.fullName", Value: "text"},
+			}, Options: options.Index().SetWeights(bson.D{
+				{Key: "teamName", Value: 10},
+				{Key: "topicName", Value: 5},
+				{Key: "leaderName", Value: 3},
+				{Key: "members.fullName", Value: 3},
+			})},
+		}},
+		{db.UserCollection, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "deletedAt", Value: 1}}},
+		}},
+		{db.Evaluations, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "teamRegistrationId", Value: 1}}},
+		}},
+		{db.Catalog, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+		}},
+		{db.AuditLog, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "target.type", Value: 1}, {Key: "target.id", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "actor.id", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "eventId", Value: 1}, {Key: "action", Value: 1}, {Key: "createdAt", Value: -1}}},
+		}},
+	}
+}
+
+// EnsureIndexes creates the indexes the application relies on
+func (db *DatabaseService) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	for _, required := range db.requiredIndexes() {
+		if _, err := required.collection.Indexes().CreateMany(ctx, required.indexes); err != nil {
+			return fmt.Errorf("failed to create indexes on %s: %w", required.collection.Name(), err)
+		}
+	}
+	return nil
+}
+
+// RebuildIndexes drops every index except _id on the collections with required
+// indexes, then creates the required ones again
+func (db *DatabaseService) RebuildIndexes(ctx context.Context) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	for _, required := range db.requiredIndexes() {
+		_, err := required.collection.Indexes().DropAll(ctx)
+		// A collection that doesn't exist yet has no indexes to drop
+		var cmdErr mongo.CommandError
+		if err != nil && !(errors.As(err, &cmdErr) && cmdErr.HasErrorCode(26)) {
+			return fmt.Errorf("failed to drop indexes on %s: %w", required.collection.Name(), err)
+		}
+	}
+	return db.EnsureIndexes(ctx)
+}
+
+// MissingIndexes lists the required indexes that don't exist, as "collection.index"
+func (db *DatabaseService) MissingIndexes(ctx context.Context) ([]string, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	missing := make([]string, 0)
+	for _, required := range db.requiredIndexes() {
+		specs, err := required.collection.Indexes().ListSpecifications(ctx)
+		if err != nil {
+			return nil, err
+		}
+		existing := make(map[string]bool, len(specs))
+		for _, spec := range specs {
+			existing[spec.Name] = true
+		}
+		for _, index := range required.indexes {
+			if name := indexName(index.Keys.(bson.D)); !existing[name] {
+				missing = append(missing, required.collection.Name()+"."+name)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// indexName returns the name MongoDB gives an index created without an explicit one
+func indexName(keys bson.D) string {
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s_%v", k.Key, k.Value))
 	}
+	return strings.Join(parts, "_")
+}
+
+// Ping checks that the primary is reachable
+func (db *DatabaseService) Ping(ctx context.Context) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	return db.Client.Ping(ctx, readpref.Primary())
 }
 
-// getContext creates a new context with timeout for database operations
-func (db *DatabaseService) getContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 30*time.Second)
+// getContext derives a context with timeout for database operations from the caller's
+// context, so cancellation and trace context flow through. Commands issued with it are
+// grouped under a span named after, and attributed in the metrics to, the calling method.
+// The returned cancel function also ends the span.
+func (db *DatabaseService) getContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	method := "unknown"
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			name := fn.Name()
+			method = name[strings.LastIndex(name, ".")+1:]
+		}
+	}
+
+	ctx, span := tracing.StartSpan(metrics.WithOperation(ctx, method), "DatabaseService."+method)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	return ctx, func() {
+		cancel()
+		span.End()
+	}
 }
 
 // User CRUD Operations
 
 // CreateUser creates a new user in the database
-func (db *DatabaseService) CreateUser(user *User) (*User, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) CreateUser(ctx context.Context, user *User) (*User, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	user.ID = primitive.NewObjectID()
@@ -68,21 +245,21 @@ func (db *DatabaseService) CreateUser(user *User) (*User, error) {
 }
 
 // GetUserByID retrieves a user by their ID
-func (db *DatabaseService) GetUserByID(id string) (*User, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) GetUserByID(ctx context.Context, id string) (*User, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid user ID format")
+		return nil, ErrInvalidUserID
 	}
 
 	var user User
-	filter := bson.M{"_id": objectID}
+	filter := notDeleted(bson.M{"_id": objectID})
 	err = db.UserCollection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -91,16 +268,16 @@ func (db *DatabaseService) GetUserByID(id string) (*User, error) {
 }
 
 // GetUserByUsername retrieves a user by their username
-func (db *DatabaseService) GetUserByUsername(username string) (*User, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	var user User
-	filter := bson.M{"username": username}
+	filter := notDeleted(bson.M{"username": username})
 	err := db.UserCollection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("user not found")
+			return nil, ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -108,104 +285,100 @@ func (db *DatabaseService) GetUserByUsername(username string) (*User, error) {
 	return &user, nil
 }
 
-// GetAllUsers retrieves all users from the database
-func (db *DatabaseService) GetAllUsers(limit int64, skip int64) ([]*User, error) {
-	ctx, cancel := db.getContext()
+// ListUsers retrieves a page of users
+func (db *DatabaseService) ListUsers(ctx context.Context, q PageQuery) (*Page[*User], error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
-	opts := options.Find().SetLimit(limit).SetSkip(skip)
-	cursor, err := db.UserCollection.Find(ctx, bson.M{}, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var users []*User
-	for cursor.Next(ctx) {
-		var user User
-		if err := cursor.Decode(&user); err != nil {
-			return nil, err
-		}
-		users = append(users, &user)
-	}
-
-	if err := cursor.Err(); err != nil {
-		return nil, err
-	}
-
-	return users, nil
+	return findPage[*User](ctx, db.UserCollection, notDeleted(nil), q)
 }
 
-// UpdateUser updates an existing user
-func (db *DatabaseService) UpdateUser(id string, updateData bson.M) (*User, error) {
-	ctx, cancel := db.getContext()
+// UpdateUser updates an existing user at version, the version the changes were based
+// on. It fails with ErrVersionConflict if the user has changed since.
+func (db *DatabaseService) UpdateUser(ctx context.Context, id string, version int64, updateData bson.M) (*User, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid user ID format")
+		return nil, ErrInvalidUserID
 	}
 
 	updateData["updatedAt"] = time.Now()
-	update := bson.M{"$set": updateData}
-	filter := bson.M{"_id": objectID}
+	update := versioned(bson.M{"$set": updateData})
+	filter := atVersion(notDeleted(bson.M{"_id": objectID}), version)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
-	_, err = db.UserCollection.UpdateOne(ctx, filter, update)
+	var user User
+	err = db.UserCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		if _, err := db.GetUserByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrVersionConflict
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return db.GetUserByID(id)
+	return &user, nil
 }
 
-// DeleteUser deletes a user by ID
-func (db *DatabaseService) DeleteUser(id string) error {
-	ctx, cancel := db.getContext()
+// DeleteUser moves a user to the trash on behalf of deletedBy, which is required,
+// and returns it as deleted
+func (db *DatabaseService) DeleteUser(ctx context.Context, id, deletedBy string) (*User, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid user ID format")
+		return nil, ErrInvalidUserID
 	}
-
-	filter := bson.M{"_id": objectID}
-	result, err := db.UserCollection.DeleteOne(ctx, filter)
-	if err != nil {
-		return err
+	if deletedBy == "" {
+		return nil, ErrActorRequired
 	}
 
-	if result.DeletedCount == 0 {
-		return errors.New("user not found")
+	var user User
+	filter := notDeleted(bson.M{"_id": objectID})
+	update := versioned(bson.M{"$set": bson.M{"deletedAt": time.Now(), "deletedBy": deletedBy}})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = db.UserCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
 	}
 
-	return nil
+	return &user, nil
 }
 
 // CountUsers returns the total number of users
-func (db *DatabaseService) CountUsers() (int64, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) CountUsers(ctx context.Context) (int64, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
-	count, err := db.UserCollection.CountDocuments(ctx, bson.M{})
+	count, err := db.UserCollection.CountDocuments(ctx, notDeleted(nil))
 	return count, err
 }
 
 // Team Registration CRUD Operations
 
-// CreateTeamRegistration creates a new team registration
-func (db *DatabaseService) CreateTeamRegistration(team *TeamRegistration) (*TeamRegistration, error) {
-	ctx, cancel := db.getContext()
+// CreateTeamRegistration creates a new team registration in an event
+func (db *DatabaseService) CreateTeamRegistration(ctx context.Context, team *TeamRegistration, event *Event) (*TeamRegistration, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
-	// Generate registration number and team ID
-	count, err := db.TeamCollection.CountDocuments(ctx, bson.M{})
+	// Generate registration number and team ID, numbered per event
+	number, err := db.nextTeamNumber(ctx, event.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	team.ID = primitive.NewObjectID()
-	team.RegistrationNumber = fmt.Sprintf("PCCOEIGC%03d", count+1)
-	team.TeamID = fmt.Sprintf("IGC%03d", count+1)
+	team.EventID = event.ID
+	team.RegistrationNumber = fmt.Sprintf("%s%03d", event.NumberPrefix, number)
+	team.TeamID = fmt.Sprintf("%s%03d", event.TeamIDPrefix, number)
 	team.CreatedAt = time.Now()
 	team.UpdatedAt = time.Now()
 	team.SubmittedAt = time.Now()
@@ -219,22 +392,56 @@ func (db *DatabaseService) CreateTeamRegistration(team *TeamRegistration) (*Team
 	return team, nil
 }
 
+// nextTeamNumber reserves the next registration number of an event. Numbers come from
+// a counter rather than the number of registrations, so deleted and purged
+// registrations never free theirs for reuse. The counter of an event numbered before
+// it existed starts after the event's registrations.
+func (db *DatabaseService) nextTeamNumber(ctx context.Context, eventID primitive.ObjectID) (int64, error) {
+	key := "teams:" + eventID.Hex()
+	exists, err := db.Counters.CountDocuments(ctx, bson.M{"_id": key})
+	if err != nil {
+		return 0, err
+	}
+	if exists == 0 {
+		count, err := db.TeamCollection.CountDocuments(ctx, bson.M{"eventId": eventID})
+		if err != nil {
+			return 0, err
+		}
+		_, err = db.Counters.UpdateOne(ctx, bson.M{"_id": key},
+			bson.M{"$setOnInsert": bson.M{"seq": count}}, options.Update().SetUpsert(true))
+		// A concurrent registration may have created the counter first
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return 0, err
+		}
+	}
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = db.Counters.FindOneAndUpdate(ctx, bson.M{"_id": key}, bson.M{"$inc": bson.M{"seq": 1}}, opts).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
+
 // GetTeamRegistrationByID retrieves a team registration by ID
-func (db *DatabaseService) GetTeamRegistrationByID(id string) (*TeamRegistration, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) GetTeamRegistrationByID(ctx context.Context, id string) (*TeamRegistration, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid team registration ID format")
+		return nil, ErrInvalidTeamID
 	}
 
 	var team TeamRegistration
-	filter := bson.M{"_id": objectID}
+	filter := notDeleted(bson.M{"_id": objectID})
 	err = db.TeamCollection.FindOne(ctx, filter).Decode(&team)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("team registration not found")
+			return nil, ErrTeamNotFound
 		}
 		return nil, err
 	}
@@ -242,17 +449,17 @@ func (db *DatabaseService) GetTeamRegistrationByID(id string) (*TeamRegistration
 	return &team, nil
 }
 
-// GetTeamRegistrationByTeamName retrieves a team registration by team name
-func (db *DatabaseService) GetTeamRegistrationByTeamName(teamName string) (*TeamRegistration, error) {
-	ctx, cancel := db.getContext()
+// GetTeamRegistrationByTeamName retrieves a team registration of an event by team name
+func (db *DatabaseService) GetTeamRegistrationByTeamName(ctx context.Context, eventID primitive.ObjectID, teamName string) (*TeamRegistration, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	var team TeamRegistration
-	filter := bson.M{"teamName": teamName}
+	filter := notDeleted(bson.M{"eventId": eventID, "teamName": teamName})
 	err := db.TeamCollection.FindOne(ctx, filter).Decode(&team)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("team registration not found")
+			return nil, ErrTeamNotFound
 		}
 		return nil, err
 	}
@@ -261,16 +468,16 @@ func (db *DatabaseService) GetTeamRegistrationByTeamName(teamName string) (*Team
 }
 
 // GetTeamRegistrationByRegistrationNumber retrieves a team by registration number
-func (db *DatabaseService) GetTeamRegistrationByRegistrationNumber(regNumber string) (*TeamRegistration, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) GetTeamRegistrationByRegistrationNumber(ctx context.Context, regNumber string) (*TeamRegistration, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	var team TeamRegistration
-	filter := bson.M{"registrationNumber": regNumber}
+	filter := notDeleted(bson.M{"registrationNumber": regNumber})
 	err := db.TeamCollection.FindOne(ctx, filter).Decode(&team)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("team registration not found")
+			return nil, ErrTeamNotFound
 		}
 		return nil, err
 	}
@@ -278,13 +485,23 @@ func (db *DatabaseService) GetTeamRegistrationByRegistrationNumber(regNumber str
 	return &team, nil
 }
 
+// GetTeamRegistrationsByLeaderEmail retrieves the teams led by an email address (case-insensitive)
+func (db *DatabaseService) GetTeamRegistrationsByLeaderEmail(ctx context.Context, email string) ([]*TeamRegistration, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return []*TeamRegistration{}, nil
+	}
+	filter := bson.M{"leaderEmail": bson.M{"$regex": "^" + regexp.QuoteMeta(email) + "$", "$options": "i"}}
+	return db.GetAllTeamRegistrations(ctx, 0, 0, filter)
+}
+
 // GetAllTeamRegistrations retrieves all team registrations with pagination and filtering
-func (db *DatabaseService) GetAllTeamRegistrations(limit int64, skip int64, filter bson.M) ([]*TeamRegistration, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) GetAllTeamRegistrations(ctx context.Context, limit int64, skip int64, filter bson.M) ([]*TeamRegistration, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"submittedAt": -1})
-	cursor, err := db.TeamCollection.Find(ctx, filter, opts)
+	cursor, err := db.TeamCollection.Find(ctx, notDeleted(filter), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -306,55 +523,120 @@ func (db *DatabaseService) GetAllTeamRegistrations(limit int64, skip int64, filt
 	return teams, nil
 }
 
-// (optional) For future optimization: Use an aggregation with $lookup to fetch only teams with videos.
-// Keeping in-memory filtering in handlers for simplicity and clarity right now.
+// ListTeamRegistrations retrieves a page of the team registrations matching filter
+func (db *DatabaseService) ListTeamRegistrations(ctx context.Context, filter bson.M, q PageQuery) (*Page[*TeamRegistration], error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	return findPage[*TeamRegistration](ctx, db.TeamCollection, notDeleted(filter), q)
+}
+
+// SearchTeamRegistrations retrieves a page of the teams of an event matching search
+func (db *DatabaseService) SearchTeamRegistrations(ctx context.Context, eventID primitive.ObjectID, search *TeamSearch, q PageQuery) (*Page[*TeamRegistration], *TeamFacets, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	videos, err := db.videoTeams(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	filter := notDeleted(search.filter(eventID, videos))
+	page, err := findPage[*TeamRegistration](ctx, db.TeamCollection, filter, q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cursor, err := db.TeamCollection.Aggregate(ctx, facetPipeline(filter, videos))
+	if err != nil {
+		return nil, nil, err
+	}
+	var facets []TeamFacets
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, nil, err
+	}
+	if len(facets) == 0 {
+		return page, &TeamFacets{}, nil
+	}
+	return page, &facets[0], nil
+}
 
 // GetTeamRegistrationsByTrack retrieves teams by track
-func (db *DatabaseService) GetTeamRegistrationsByTrack(track Track, limit int64, skip int64) ([]*TeamRegistration, error) {
+func (db *DatabaseService) GetTeamRegistrationsByTrack(ctx context.Context, track Track, limit int64, skip int64) ([]*TeamRegistration, error) {
 	filter := bson.M{"track": track}
-	return db.GetAllTeamRegistrations(limit, skip, filter)
+	return db.GetAllTeamRegistrations(ctx, limit, skip, filter)
 }
 
 // GetTeamRegistrationsByStatus retrieves teams by registration status
-func (db *DatabaseService) GetTeamRegistrationsByStatus(status RegistrationStatus, limit int64, skip int64) ([]*TeamRegistration, error) {
+func (db *DatabaseService) GetTeamRegistrationsByStatus(ctx context.Context, status RegistrationStatus, limit int64, skip int64) ([]*TeamRegistration, error) {
 	filter := bson.M{"registrationStatus": status}
-	return db.GetAllTeamRegistrations(limit, skip, filter)
+	return db.GetAllTeamRegistrations(ctx, limit, skip, filter)
 }
 
 // GetTeamRegistrationsByInstitution retrieves teams by institution
-func (db *DatabaseService) GetTeamRegistrationsByInstitution(institution string, limit int64, skip int64) ([]*TeamRegistration, error) {
+func (db *DatabaseService) GetTeamRegistrationsByInstitution(ctx context.Context, institution string, limit int64, skip int64) ([]*TeamRegistration, error) {
 	filter := bson.M{"institution": institution}
-	return db.GetAllTeamRegistrations(limit, skip, filter)
+	return db.GetAllTeamRegistrations(ctx, limit, skip, filter)
 }
 
-// UpdateTeamRegistration updates an existing team registration
-func (db *DatabaseService) UpdateTeamRegistration(id string, updateData bson.M) (*TeamRegistration, error) {
-	ctx, cancel := db.getContext()
+// UpdateTeamRegistration updates an existing team registration at version, the version
+// the changes were based on. It fails with ErrVersionConflict if the registration has
+// changed since.
+func (db *DatabaseService) UpdateTeamRegistration(ctx context.Context, id string, version int64, updateData bson.M) (*TeamRegistration, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid team registration ID format")
+		return nil, ErrInvalidTeamID
 	}
 
 	updateData["updatedAt"] = time.Now()
-	update := bson.M{"$set": updateData}
-	filter := bson.M{"_id": objectID}
+	update := versioned(bson.M{"$set": updateData})
+	filter := atVersion(notDeleted(bson.M{"_id": objectID}), version)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
-	_, err = db.TeamCollection.UpdateOne(ctx, filter, update)
+	var team TeamRegistration
+	err = db.TeamCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&team)
+	if err == mongo.ErrNoDocuments {
+		if _, err := db.GetTeamRegistrationByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrVersionConflict
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return db.GetTeamRegistrationByID(id)
+	return &team, nil
 }
 
-// ApproveTeamRegistration approves a team registration
-func (db *DatabaseService) ApproveTeamRegistration(id, actionedBy string) (*TeamRegistration, error) {
-	team, err := db.GetTeamRegistrationByID(id)
+// ApproveTeamRegistration approves a team registration at version, or waitlists it when
+// its track is at capacity
+func (db *DatabaseService) ApproveTeamRegistration(ctx context.Context, id string, version int64, actionedBy string) (*TeamRegistration, error) {
+	team, err := db.GetTeamRegistrationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if team.Version != version {
+		return nil, ErrVersionConflict
+	}
+	if team.IsApproved() {
+		return team, nil
+	}
+
+	capacity, err := db.trackCapacity(ctx, team.EventID, team.Track)
 	if err != nil {
 		return nil, err
 	}
+	if capacity > 0 {
+		approved, err := db.countApprovedInTrack(ctx, team.EventID, team.Track)
+		if err != nil {
+			return nil, err
+		}
+		if approved >= int64(capacity) {
+			return db.waitlistTeamRegistration(ctx, team, actionedBy)
+		}
+	}
 
 	team.Approve(actionedBy)
 
@@ -365,15 +647,182 @@ func (db *DatabaseService) ApproveTeamRegistration(id, actionedBy string) (*Team
 		"updatedAt":          time.Now(),
 	}
 
-	return db.UpdateTeamRegistration(id, updateData)
+	updated, err := db.UpdateTeamRegistration(ctx, id, version, updateData)
+	if err != nil || capacity == 0 {
+		return updated, err
+	}
+
+	// Concurrent approvals can overshoot the capacity: yield the slot and let the
+	// waitlist order decide which team keeps it
+	approved, err := db.countApprovedInTrack(ctx, team.EventID, team.Track)
+	if err != nil || approved <= int64(capacity) {
+		return updated, err
+	}
+	if _, err := db.waitlistTeamRegistration(ctx, updated, actionedBy); err != nil {
+		return nil, err
+	}
+	if _, err := db.PromoteWaitlisted(ctx, team.EventID, team.Track); err != nil {
+		return nil, err
+	}
+	return db.GetTeamRegistrationByID(ctx, id)
+}
+
+// waitlistTeamRegistration puts a team on its track's waitlist, keeping its original position
+func (db *DatabaseService) waitlistTeamRegistration(ctx context.Context, team *TeamRegistration, actionedBy string) (*TeamRegistration, error) {
+	now := time.Now()
+	updateData := bson.M{
+		"registrationStatus": StatusWaitlisted,
+		"actionedBy":         actionedBy,
+	}
+	if team.WaitlistedAt == nil {
+		updateData["waitlistedAt"] = now
+	}
+	return db.UpdateTeamRegistration(ctx, team.ID.Hex(), team.Version, updateData)
+}
+
+// countApprovedInTrack returns the number of approved teams in an event's track
+func (db *DatabaseService) countApprovedInTrack(ctx context.Context, eventID primitive.ObjectID, track Track) (int64, error) {
+	return db.CountTeamRegistrationsWithFilter(ctx, bson.M{"eventId": eventID, "track": track, "registrationStatus": StatusApproved})
+}
+
+// trackCapacity returns the approval limit of a track in an event (0 = unlimited).
+// Teams of a deleted event or track are unrestricted by it.
+func (db *DatabaseService) trackCapacity(ctx context.Context, eventID primitive.ObjectID, track Track) (int, error) {
+	cfg := NewEventConfig()
+	event, err := db.GetEventByID(ctx, eventID.Hex())
+	if err == nil {
+		cfg = &event.Config
+	} else if !errors.Is(err, ErrNotFound) {
+		return 0, err
+	}
+
+	catalogCapacity := 0
+	entry, err := db.FindCatalogEntry(ctx, CatalogTrack, string(track))
+	if err == nil {
+		catalogCapacity = entry.Capacity
+	} else if !errors.Is(err, ErrNotFound) {
+		return 0, err
+	}
+	return cfg.CapacityFor(track, catalogCapacity), nil
+}
+
+// PromoteWaitlisted approves waitlisted teams of an event's track, oldest first, until the track is full
+func (db *DatabaseService) PromoteWaitlisted(ctx context.Context, eventID primitive.ObjectID, track Track) ([]*TeamRegistration, error) {
+	capacity, err := db.trackCapacity(ctx, eventID, track)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	promoted := make([]*TeamRegistration, 0)
+	for {
+		if capacity > 0 {
+			approved, err := db.countApprovedInTrack(ctx, eventID, track)
+			if err != nil {
+				return promoted, err
+			}
+			if approved >= int64(capacity) {
+				break
+			}
+		}
+
+		now := time.Now()
+		filter := notDeleted(bson.M{"eventId": eventID, "track": track, "registrationStatus": StatusWaitlisted})
+		update := versioned(bson.M{"$set": bson.M{
+			"registrationStatus": StatusApproved,
+			"approvedAt":         now,
+			"updatedAt":          now,
+		}})
+		opts := options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "waitlistedAt", Value: 1}, {Key: "_id", Value: 1}}).
+			SetReturnDocument(options.Before)
+
+		var before TeamRegistration
+		err := db.TeamCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			return promoted, err
+		}
+		team := before
+		team.RegistrationStatus = StatusApproved
+		team.ApprovedAt = &now
+		team.UpdatedAt = now
+		team.Version++
+		promoted = append(promoted, &team)
+		db.auditPromotion(ctx, &before, &team)
+	}
+	return promoted, nil
+}
+
+// auditPromotion records the approval of a waitlisted team. Promotions follow from
+// another change or run in the background, so they're recorded under the system,
+// with the ID of the request that caused them, if any.
+func (db *DatabaseService) auditPromotion(ctx context.Context, before, after *TeamRegistration) {
+	changes, err := AuditDiff(before, after)
+	if err != nil {
+		db.Logger.ErrorContext(ctx, "failed to compare audited record", "error", err, "action", AuditTeamPromote, "target_id", after.ID.Hex())
+	}
+	entry := &AuditEntry{
+		EventID:   after.EventID,
+		Actor:     AuditSystemActor,
+		Action:    AuditTeamPromote,
+		Target:    AuditTarget{Type: AuditTargetTeam, ID: after.ID.Hex()},
+		Changes:   changes,
+		RequestID: logging.RequestID(ctx),
+	}
+	if err := db.RecordAudit(ctx, entry); err != nil {
+		db.Logger.ErrorContext(ctx, "failed to record audit entry", "error", err, "action", AuditTeamPromote, "target_id", after.ID.Hex())
+	}
+}
+
+// PromoteAllWaitlisted runs PromoteWaitlisted for every track of an event that has a waitlist
+func (db *DatabaseService) PromoteAllWaitlisted(ctx context.Context, eventID primitive.ObjectID) ([]*TeamRegistration, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	tracks, err := db.TeamCollection.Distinct(ctx, "track", notDeleted(bson.M{"eventId": eventID, "registrationStatus": StatusWaitlisted}))
+	if err != nil {
+		return nil, err
+	}
+
+	promoted := make([]*TeamRegistration, 0)
+	for _, t := range tracks {
+		name, ok := t.(string)
+		if !ok {
+			continue
+		}
+		teams, err := db.PromoteWaitlisted(ctx, eventID, Track(name))
+		promoted = append(promoted, teams...)
+		if err != nil {
+			return promoted, err
+		}
+	}
+	return promoted, nil
+}
+
+// promoteAfterRelease fills the slot freed by a team that is no longer approved
+func (db *DatabaseService) promoteAfterRelease(ctx context.Context, eventID primitive.ObjectID, track Track) {
+	if promoted, err := db.PromoteWaitlisted(ctx, eventID, track); err != nil {
+		db.Logger.ErrorContext(ctx, "failed to promote waitlisted teams", "error", err, "event_id", eventID.Hex(), "track", track)
+	} else if len(promoted) > 0 {
+		db.Logger.InfoContext(ctx, "promoted waitlisted teams", "count", len(promoted), "event_id", eventID.Hex(), "track", track)
+	}
 }
 
-// RejectTeamRegistration rejects a team registration
-func (db *DatabaseService) RejectTeamRegistration(id, reason, actionedBy string) (*TeamRegistration, error) {
-	team, err := db.GetTeamRegistrationByID(id)
+// RejectTeamRegistration rejects a team registration at version
+func (db *DatabaseService) RejectTeamRegistration(ctx context.Context, id string, version int64, reason, actionedBy string) (*TeamRegistration, error) {
+	team, err := db.GetTeamRegistrationByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if team.Version != version {
+		return nil, ErrVersionConflict
+	}
+	wasApproved := team.IsApproved()
 
 	team.Reject(reason, actionedBy)
 
@@ -385,57 +834,122 @@ func (db *DatabaseService) RejectTeamRegistration(id, reason, actionedBy string)
 		"updatedAt":          time.Now(),
 	}
 
-	return db.UpdateTeamRegistration(id, updateData)
+	updated, err := db.UpdateTeamRegistration(ctx, id, version, updateData)
+	if err != nil {
+		return nil, err
+	}
+	if wasApproved {
+		db.promoteAfterRelease(ctx, team.EventID, team.Track)
+	}
+	return updated, nil
 }
 
-// DeleteTeamRegistration deletes a team registration by ID
-func (db *DatabaseService) DeleteTeamRegistration(id string) error {
-	ctx, cancel := db.getContext()
+// DeleteTeamRegistration moves a team registration to the trash on behalf of deletedBy,
+// which is required, and returns it as deleted. Its registration number stays reserved.
+func (db *DatabaseService) DeleteTeamRegistration(ctx context.Context, id, deletedBy string) (*TeamRegistration, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid team registration ID format")
+		return nil, ErrInvalidTeamID
+	}
+	if deletedBy == "" {
+		return nil, ErrActorRequired
 	}
 
-	filter := bson.M{"_id": objectID}
-	result, err := db.TeamCollection.DeleteOne(ctx, filter)
+	var team TeamRegistration
+	filter := notDeleted(bson.M{"_id": objectID})
+	update := versioned(bson.M{"$set": bson.M{"deletedAt": time.Now(), "deletedBy": deletedBy}})
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = db.TeamCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&team)
 	if err != nil {
-		return err
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTeamNotFound
+		}
+		return nil, err
 	}
 
-	if result.DeletedCount == 0 {
-		return errors.New("team registration not found")
+	if team.IsApproved() {
+		db.promoteAfterRelease(ctx, team.EventID, team.Track)
 	}
-
-	return nil
+	return &team, nil
 }
 
 // CountTeamRegistrations returns the total number of team registrations
-func (db *DatabaseService) CountTeamRegistrations() (int64, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) CountTeamRegistrations(ctx context.Context) (int64, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
-	count, err := db.TeamCollection.CountDocuments(ctx, bson.M{})
+	count, err := db.TeamCollection.CountDocuments(ctx, notDeleted(nil))
 	return count, err
 }
 
 // CountTeamRegistrationsWithFilter returns the number of team registrations matching a filter
-func (db *DatabaseService) CountTeamRegistrationsWithFilter(filter bson.M) (int64, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) CountTeamRegistrationsWithFilter(ctx context.Context, filter bson.M) (int64, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
-	if filter == nil {
-		filter = bson.M{}
-	}
-	count, err := db.TeamCollection.CountDocuments(ctx, filter)
+	count, err := db.TeamCollection.CountDocuments(ctx, notDeleted(filter))
 	return count, err
 }
 
+// Fields of the videos collection that may hold the registration number and the link
+var (
+	videoRegistrationFields = []string{"registrationId", "registration_id", "registrationID", "registrationNumber", "registration", "regNumber"}
+	videoLinkFields         = []string{"videoUrl", "videoURL", "videoLink", "link", "url", "video", "youtube", "youtubeUrl", "driveUrl"}
+)
+
+// VideoSubmittedFilter returns a team registration filter matching the teams with a
+// submitted video
+func (db *DatabaseService) VideoSubmittedFilter(ctx context.Context) (bson.M, error) {
+	videos, err := db.videoTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return videos.filter(), nil
+}
+
+// videoTeams reads the identifiers of the teams with a submitted video
+func (db *DatabaseService) videoTeams(ctx context.Context) (*videoTeams, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	hasLink := bson.A{}
+	for _, k := range videoLinkFields {
+		hasLink = append(hasLink, bson.M{k: bson.M{"$type": "string", "$ne": ""}})
+	}
+	videos := bson.M{"$or": hasLink}
+
+	distinct := func(fields ...string) (bson.A, error) {
+		values := bson.A{}
+		for _, field := range fields {
+			found, err := db.Videos.Distinct(ctx, field, videos)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, found...)
+		}
+		return values, nil
+	}
+	var v videoTeams
+	var err error
+	if v.registrations, err = distinct(videoRegistrationFields...); err != nil {
+		return nil, err
+	}
+	if v.teamIDs, err = distinct("teamId"); err != nil {
+		return nil, err
+	}
+	if v.teamNames, err = distinct("teamName"); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
 // GetVideoLinkForTeam returns the submitted video link for a team if present.
 // It looks up in the "videos" collection using common identifiers.
-func (db *DatabaseService) GetVideoLinkForTeam(team *TeamRegistration) (string, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) GetVideoLinkForTeam(ctx context.Context, team *TeamRegistration) (string, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	if db.Videos == nil || team == nil {
@@ -443,7 +957,7 @@ func (db *DatabaseService) GetVideoLinkForTeam(team *TeamRegistration) (string,
 	}
 
 	// Prefer strict lookup by registration id/number
-	if link, _, ok, err := db.FindVideoByRegistration(team.RegistrationNumber); err == nil && ok {
+	if link, _, ok, err := db.FindVideoByRegistration(ctx, team.RegistrationNumber); err == nil && ok {
 		return link, nil
 	} else if err != nil {
 		return "", err
@@ -477,8 +991,8 @@ func (db *DatabaseService) GetVideoLinkForTeam(team *TeamRegistration) (string,
 }
 
 // FindVideoByRegistration looks up a video by registration id/number and returns (link, teamName, exists, error)
-func (db *DatabaseService) FindVideoByRegistration(reg string) (string, string, bool, error) {
-	ctx, cancel := db.getContext()
+func (db *DatabaseService) FindVideoByRegistration(ctx context.Context, reg string) (string, string, bool, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
 	reg = strings.TrimSpace(reg)
@@ -486,14 +1000,11 @@ func (db *DatabaseService) FindVideoByRegistration(reg string) (string, string,
 		return "", "", false, nil
 	}
 
-	filter := bson.M{"$or": []bson.M{
-		{"registrationId": reg},
-		{"registration_id": reg},
-		{"registrationID": reg},
-		{"registrationNumber": reg},
-		{"registration": reg},
-		{"regNumber": reg},
-	}}
+	var match []bson.M
+	for _, k := range videoRegistrationFields {
+		match = append(match, bson.M{k: reg})
+	}
+	filter := bson.M{"$or": match}
 
 	var doc bson.M
 	err := db.Videos.FindOne(ctx, filter).Decode(&doc)
@@ -506,7 +1017,7 @@ func (db *DatabaseService) FindVideoByRegistration(reg string) (string, string,
 
 	// Extract video link
 	link := ""
-	for _, k := range []string{"videoUrl", "videoURL", "videoLink", "link", "url", "video", "youtube", "youtubeUrl", "driveUrl"} {
+	for _, k := range videoLinkFields {
 		if v, ok := doc[k]; ok {
 			if s, ok := v.(string); ok && s != "" {
 				link = s
@@ -529,55 +1040,1384 @@ func (db *DatabaseService) FindVideoByRegistration(reg string) (string, string,
 	return link, teamName, true, nil
 }
 
-// CountTeamRegistrationsByStatus returns count by status
-func (db *DatabaseService) CountTeamRegistrationsByStatus(status RegistrationStatus) (int64, error) {
-	ctx, cancel := db.getContext()
+// CountTeamRegistrationsByStatus returns count by status within an event
+func (db *DatabaseService) CountTeamRegistrationsByStatus(ctx context.Context, eventID primitive.ObjectID, status RegistrationStatus) (int64, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
-	filter := bson.M{"registrationStatus": status}
+	filter := notDeleted(bson.M{"eventId": eventID, "registrationStatus": status})
 	count, err := db.TeamCollection.CountDocuments(ctx, filter)
 	return count, err
 }
 
-// GetTeamRegistrationStats returns registration statistics
-func (db *DatabaseService) GetTeamRegistrationStats() (map[string]int64, error) {
-	stats := make(map[string]int64)
+// GetTeamRegistrationStats returns registration statistics of an event
+func (db *DatabaseService) GetTeamRegistrationStats(ctx context.Context, eventID primitive.ObjectID) (map[string]int64, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
 
-	// Count total registrations
-	total, err := db.CountTeamRegistrations()
+	cursor, err := db.TeamCollection.Aggregate(ctx, append(bson.A{bson.M{"$match": notDeleted(bson.M{"eventId": eventID})}},
+		countBy("$registrationStatus")...))
 	if err != nil {
 		return nil, err
 	}
-	stats["total"] = total
+	var counts []struct {
+		Status RegistrationStatus `bson:"_id"`
+		Count  int64              `bson:"count"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+
+	stats := map[string]int64{"total": 0}
+	for _, status := range []RegistrationStatus{StatusApproved, StatusPending, StatusRejected, StatusWaitlisted} {
+		stats[string(status)] = 0
+	}
+	for _, c := range counts {
+		stats["total"] += c.Count
+		if _, ok := stats[string(c.Status)]; ok {
+			stats[string(c.Status)] = c.Count
+		}
+	}
+	return stats, nil
+}
+
+// GetRegistrationAnalytics breaks down the registrations of an event matching filter
+func (db *DatabaseService) GetRegistrationAnalytics(ctx context.Context, eventID primitive.ObjectID, filter *AnalyticsFilter) (*RegistrationAnalytics, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
 
-	// Count by status
-	approved, err := db.CountTeamRegistrationsByStatus(StatusApproved)
+	videos, err := db.videoTeams(ctx)
 	if err != nil {
 		return nil, err
 	}
-	stats["approved"] = approved
-
-	pending, err := db.CountTeamRegistrationsByStatus(StatusPending)
+	cursor, err := db.TeamCollection.Aggregate(ctx, analyticsPipeline(notDeleted(filter.match(eventID)), videos))
 	if err != nil {
 		return nil, err
 	}
-	stats["pending"] = pending
+	var facets []analyticsFacets
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+	if len(facets) == 0 {
+		facets = append(facets, analyticsFacets{})
+	}
+	return facets[0].analytics(), nil
+}
+
+// FindDuplicateParticipants returns the participants of team who also appear in another
+// registration of the event. Rejected registrations are ignored.
+func (db *DatabaseService) FindDuplicateParticipants(ctx context.Context, eventID primitive.ObjectID, team *TeamRegistration) ([]DuplicateMatch, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	var emails, mobiles []string
+	for _, p := range team.Participants() {
+		if p.Email != "" {
+			emails = append(emails, p.Email)
+		}
+		if p.Mobile != "" {
+			mobiles = append(mobiles, p.Mobile)
+		}
+	}
+	// $in needs an array, so only match on the contacts the team has
+	var contacts bson.A
+	if len(emails) > 0 {
+		contacts = append(contacts,
+			bson.M{"leaderEmail": bson.M{"$in": emails}},
+			bson.M{"members.email": bson.M{"$in": emails}},
+		)
+	}
+	if len(mobiles) > 0 {
+		contacts = append(contacts,
+			bson.M{"leaderMobile": bson.M{"$in": mobiles}},
+			bson.M{"members.mobileNo": bson.M{"$in": mobiles}},
+		)
+	}
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+	filter := bson.M{
+		"eventId":            eventID,
+		"registrationStatus": bson.M{"$ne": StatusRejected},
+		"$or":                contacts,
+	}
+	if !team.ID.IsZero() {
+		filter["_id"] = bson.M{"$ne": team.ID}
+	}
 
-	rejected, err := db.CountTeamRegistrationsByStatus(StatusRejected)
+	cursor, err := db.TeamCollection.Find(ctx, notDeleted(filter))
 	if err != nil {
 		return nil, err
 	}
-	stats["rejected"] = rejected
+	defer cursor.Close(ctx)
 
-	return stats, nil
+	var others []*TeamRegistration
+	if err := cursor.All(ctx, &others); err != nil {
+		return nil, err
+	}
+	var matches []DuplicateMatch
+	for _, other := range others {
+		matches = append(matches, matchParticipants(team, other)...)
+	}
+	return matches, nil
 }
 
-// Close closes the database connection
-func (db *DatabaseService) Close() {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// GetDuplicateReport lists the people registered in more than one team of an event.
+// Rejected registrations are ignored.
+func (db *DatabaseService) GetDuplicateReport(ctx context.Context, eventID primitive.ObjectID) (*DuplicateReport, error) {
+	ctx, cancel := db.getContext(ctx)
 	defer cancel()
 
-	if err := db.Client.Disconnect(ctx); err != nil {
-		fmt.Printf("Error disconnecting from MongoDB: %v\n", err)
+	projection := bson.M{
+		"teamName": 1, "registrationNumber": 1, "members": 1,
+		"leaderName": 1, "leaderEmail": 1, "leaderMobile": 1,
+	}
+	cursor, err := db.TeamCollection.Find(ctx,
+		notDeleted(bson.M{"eventId": eventID, "registrationStatus": bson.M{"$ne": StatusRejected}}),
+		options.Find().SetProjection(projection).SetSort(bson.D{{Key: "registrationNumber", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var teams []*TeamRegistration
+	if err := cursor.All(ctx, &teams); err != nil {
+		return nil, err
+	}
+	return buildDuplicateReport(teams), nil
+}
+
+// Event Operations
+
+// CreateEvent stores a new event. The first event created becomes the active one.
+func (db *DatabaseService) CreateEvent(ctx context.Context, event *Event) (*Event, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	// Registration numbers are looked up globally (verification, participant login),
+	// so every edition needs its own prefix
+	count, err := db.Events.CountDocuments(ctx, bson.M{"$or": []bson.M{
+		{"slug": event.Slug},
+		{"numberPrefix": event.NumberPrefix},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrEventExists
+	}
+
+	total, err := db.Events.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		event.Active = true
+	}
+
+	now := time.Now()
+	event.ID = primitive.NewObjectID()
+	event.CreatedAt = now
+	event.UpdatedAt = now
+	if _, err := db.Events.InsertOne(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// GetEventByID retrieves an event by ID
+func (db *DatabaseService) GetEventByID(ctx context.Context, id string) (*Event, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidEventID
+	}
+	return db.findEvent(ctx, bson.M{"_id": objectID})
+}
+
+// GetEventBySlug retrieves an event by slug
+func (db *DatabaseService) GetEventBySlug(ctx context.Context, slug string) (*Event, error) {
+	return db.findEvent(ctx, bson.M{"slug": strings.ToLower(strings.TrimSpace(slug))})
+}
+
+// GetEvent retrieves an event by slug or ID
+func (db *DatabaseService) GetEvent(ctx context.Context, ref string) (*Event, error) {
+	if primitive.IsValidObjectID(ref) {
+		return db.GetEventByID(ctx, ref)
+	}
+	return db.GetEventBySlug(ctx, ref)
+}
+
+// GetActiveEvent retrieves the event used when a request does not name one
+func (db *DatabaseService) GetActiveEvent(ctx context.Context) (*Event, error) {
+	return db.findEvent(ctx, bson.M{"active": true})
+}
+
+func (db *DatabaseService) findEvent(ctx context.Context, filter bson.M) (*Event, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	var event Event
+	err := db.Events.FindOne(ctx, filter).Decode(&event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrEventNotFound
+		}
+		return nil, err
+	}
+	if event.Config.TrackCapacity == nil {
+		event.Config.TrackCapacity = map[string]int{}
+	}
+	return &event, nil
+}
+
+// GetEvents retrieves all events, newest first
+func (db *DatabaseService) GetEvents(ctx context.Context) ([]*Event, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := db.Events.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := make([]*Event, 0)
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// UpdateEvent stores an event and promotes waitlisted teams into any capacity
+// its new limits freed up
+func (db *DatabaseService) UpdateEvent(ctx context.Context, event *Event) (*Event, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	count, err := db.Events.CountDocuments(ctx, bson.M{
+		"_id":          bson.M{"$ne": event.ID},
+		"numberPrefix": event.NumberPrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrNumberPrefixInUse
+	}
+
+	event.UpdatedAt = time.Now()
+	result, err := db.Events.ReplaceOne(ctx, bson.M{"_id": event.ID}, event)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrEventNotFound
+	}
+
+	if _, err := db.PromoteAllWaitlisted(ctx, event.ID); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// SetActiveEvent makes an event the default one
+func (db *DatabaseService) SetActiveEvent(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	result, err := db.Events.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"active": true}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrEventNotFound
+	}
+
+	_, err = db.Events.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$ne": id}, "active": true},
+		bson.M{"$set": bson.M{"active": false}})
+	return err
+}
+
+// EnsureDefaultEvent migrates a single-edition database: it creates the first event
+// (from the legacy event settings, if any) and attaches records without an event to the
+// active event. It is safe to run on every start.
+func (db *DatabaseService) EnsureDefaultEvent(ctx context.Context) (*Event, error) {
+	event, err := db.GetActiveEvent(ctx)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if event == nil {
+		events, err := db.GetEvents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			event = events[0]
+			if err := db.SetActiveEvent(ctx, event.ID); err != nil {
+				return nil, err
+			}
+			event.Active = true
+		}
+	}
+
+	if event == nil {
+		event = NewEvent("igc", "PCCOE IGC")
+		if err := db.loadLegacyEventConfig(ctx, &event.Config); err != nil {
+			return nil, err
+		}
+		if event, err = db.CreateEvent(ctx, event); err != nil {
+			return nil, err
+		}
+		db.Logger.InfoContext(ctx, "created default event", "event", event.Slug)
+	}
+
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	orphans := bson.M{"eventId": bson.M{"$exists": false}}
+	attach := bson.M{"$set": bson.M{"eventId": event.ID}}
+	for _, coll := range []*mongo.Collection{db.TeamCollection, db.Certificates, db.ChangeRequests} {
+		result, err := coll.UpdateMany(ctx, orphans, attach)
+		if err != nil {
+			return nil, err
+		}
+		if result.ModifiedCount > 0 {
+			db.Logger.InfoContext(ctx, "attached records to event", "count", result.ModifiedCount, "collection", coll.Name(), "event", event.Slug)
+		}
+	}
+
+	// Judge allocations used to be stored as hex strings
+	_, err = db.TeamCollection.UpdateMany(ctx,
+		bson.M{"allocatedJudgeId": bson.M{"$type": "string"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"allocatedJudgeId": bson.M{"$convert": bson.M{
+			"input":   "$allocatedJudgeId",
+			"to":      "objectId",
+			"onError": "$allocatedJudgeId",
+		}}}}}})
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// NormalizeTeamContacts rewrites the phone numbers of every team registration to E.164
+// and trims and lowercases their emails. Numbers that can't be read are left as they
+// are and listed in the report. It is safe to run repeatedly.
+func (db *DatabaseService) NormalizeTeamContacts(ctx context.Context) (*ContactReport, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	projection := bson.M{
+		"registrationNumber": 1, "country": 1, "members": 1,
+		"leaderEmail": 1, "leaderMobile": 1, "mentorEmail": 1, "mentorMobile": 1,
+	}
+	cursor, err := db.TeamCollection.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team registrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	report := &ContactReport{}
+	var updates []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var team TeamRegistration
+		if err := cursor.Decode(&team); err != nil {
+			return nil, fmt.Errorf("failed to decode team registration: %w", err)
+		}
+		report.Scanned++
+
+		before := team
+		before.Members = slices.Clone(team.Members)
+		for _, fe := range team.NormalizeContacts() {
+			report.Invalid = append(report.Invalid, InvalidContact{
+				TeamID:             team.ID.Hex(),
+				RegistrationNumber: team.RegistrationNumber,
+				FieldError:         fe,
+			})
+		}
+
+		changed := bson.M{}
+		if team.LeaderEmail != before.LeaderEmail {
+			changed["leaderEmail"] = team.LeaderEmail
+		}
+		if team.LeaderMobile != before.LeaderMobile {
+			changed["leaderMobile"] = team.LeaderMobile
+		}
+		if team.MentorEmail != before.MentorEmail {
+			changed["mentorEmail"] = team.MentorEmail
+		}
+		if team.MentorMobile != before.MentorMobile {
+			changed["mentorMobile"] = team.MentorMobile
+		}
+		if !slices.Equal(team.Members, before.Members) {
+			changed["members"] = team.Members
+		}
+		if len(changed) > 0 {
+			changed["updatedAt"] = time.Now()
+			updates = append(updates, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": team.ID}).
+				SetUpdate(versioned(bson.M{"$set": changed})))
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list team registrations: %w", err)
+	}
+
+	if len(updates) > 0 {
+		result, err := db.TeamCollection.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return nil, fmt.Errorf("failed to update team registrations: %w", err)
+		}
+		report.Updated = result.ModifiedCount
+		db.Logger.InfoContext(ctx, "normalized team contacts", "count", result.ModifiedCount)
+	}
+	return report, nil
+}
+
+// loadLegacyEventConfig reads the configuration saved before events existed
+func (db *DatabaseService) loadLegacyEventConfig(ctx context.Context, cfg *EventConfig) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	err := db.Database.Collection("settings").FindOne(ctx, bson.M{"_id": "event"}).Decode(cfg)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	if cfg.TrackCapacity == nil {
+		cfg.TrackCapacity = map[string]int{}
+	}
+	return nil
+}
+
+// Evaluation Operations
+
+// CreateEvaluation stores a judge's evaluation
+func (db *DatabaseService) CreateEvaluation(ctx context.Context, evaluation *Evaluation) (*Evaluation, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	evaluation.ID = primitive.NewObjectID()
+	evaluation.CreatedAt = time.Now()
+	if _, err := db.Evaluations.InsertOne(ctx, evaluation); err != nil {
+		return nil, err
+	}
+	return evaluation, nil
+}
+
+// ListEvaluations retrieves a page of the evaluations matching filter
+func (db *DatabaseService) ListEvaluations(ctx context.Context, filter bson.M, q PageQuery) (*Page[*Evaluation], error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	return findPage[*Evaluation](ctx, db.Evaluations, filter, q)
+}
+
+// GetEvaluations retrieves evaluations matching a filter, newest first
+func (db *DatabaseService) GetEvaluations(ctx context.Context, filter bson.M) ([]*Evaluation, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := db.Evaluations.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	evaluations := make([]*Evaluation, 0)
+	if err := cursor.All(ctx, &evaluations); err != nil {
+		return nil, err
+	}
+	return evaluations, nil
+}
+
+// Catalogue Operations
+
+// CreateCatalogEntry stores a new track or program
+func (db *DatabaseService) CreateCatalogEntry(ctx context.Context, entry *CatalogEntry) (*CatalogEntry, error) {
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	count, err := db.Catalog.CountDocuments(ctx, bson.M{"kind": entry.Kind, "$or": []bson.M{
+		{"slug": entry.Slug},
+		{"name": entry.Name},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, catalogExists(entry.Kind)
+	}
+
+	now := time.Now()
+	entry.ID = primitive.NewObjectID()
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+	if _, err := db.Catalog.InsertOne(ctx, entry); err != nil {
+		return nil, err
+	}
+	if err := db.releaseCatalogName(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// releaseCatalogName stops other entries from claiming the entry's name as a previous one
+func (db *DatabaseService) releaseCatalogName(ctx context.Context, entry *CatalogEntry) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	_, err := db.Catalog.UpdateMany(ctx,
+		bson.M{"kind": entry.Kind, "_id": bson.M{"$ne": entry.ID}, "previousNames": entry.Name},
+		bson.M{"$pull": bson.M{"previousNames": entry.Name}})
+	return err
+}
+
+// GetCatalogEntry retrieves a track or program by slug
+func (db *DatabaseService) GetCatalogEntry(ctx context.Context, kind CatalogKind, slug string) (*CatalogEntry, error) {
+	return db.findCatalogEntry(ctx, bson.M{"kind": kind, "slug": strings.ToLower(strings.TrimSpace(slug))})
+}
+
+// FindCatalogEntry retrieves a track or program by its name, slug or a previous name
+func (db *DatabaseService) FindCatalogEntry(ctx context.Context, kind CatalogKind, nameOrSlug string) (*CatalogEntry, error) {
+	value := strings.TrimSpace(nameOrSlug)
+	entry, err := db.findCatalogEntry(ctx, bson.M{"kind": kind, "$or": []bson.M{
+		{"name": value},
+		{"slug": strings.ToLower(value)},
+	}})
+	if errors.Is(err, ErrNotFound) {
+		return db.findCatalogEntry(ctx, bson.M{"kind": kind, "previousNames": value})
+	}
+	return entry, err
+}
+
+func (db *DatabaseService) findCatalogEntry(ctx context.Context, filter bson.M) (*CatalogEntry, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	var entry CatalogEntry
+	err := db.Catalog.FindOne(ctx, filter).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			kind, _ := filter["kind"].(CatalogKind)
+			return nil, catalogNotFound(kind)
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetCatalogEntries retrieves the tracks or programs sorted by name
+func (db *DatabaseService) GetCatalogEntries(ctx context.Context, kind CatalogKind, includeInactive bool) ([]*CatalogEntry, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	filter := bson.M{"kind": kind}
+	if !includeInactive {
+		filter["active"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := db.Catalog.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := make([]*CatalogEntry, 0)
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// UpdateCatalogEntry stores a track or program and migrates every record holding one of
+// its previous names (see CatalogEntry.Rename) to the current name
+func (db *DatabaseService) UpdateCatalogEntry(ctx context.Context, entry *CatalogEntry) (*CatalogEntry, error) {
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	count, err := db.Catalog.CountDocuments(ctx, bson.M{
+		"kind": entry.Kind,
+		"_id":  bson.M{"$ne": entry.ID},
+		"$or":  []bson.M{{"slug": entry.Slug}, {"name": entry.Name}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, catalogExists(entry.Kind)
+	}
+
+	entry.UpdatedAt = time.Now()
+	result, err := db.Catalog.ReplaceOne(ctx, bson.M{"_id": entry.ID}, entry)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, catalogNotFound(entry.Kind)
+	}
+
+	if err := db.releaseCatalogName(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	// Migrate after the catalogue maps old names to the new one, so registrations created
+	// in between already use it. The migration is idempotent: if it fails, saving the
+	// entry again finishes it.
+	for _, previousName := range entry.PreviousNames {
+		if err := db.migrateCatalogName(ctx, entry.Kind, previousName, entry.Name); err != nil {
+			return nil, fmt.Errorf("renamed %s but failed to migrate existing records: %w", entry.Kind, err)
+		}
+	}
+
+	if entry.Kind == CatalogTrack {
+		if err := db.promoteTrackEverywhere(ctx, Track(entry.Name)); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// migrateCatalogName replaces a track or program name on the records of events that can
+// still be modified. Read-only past editions and issued certificates keep the name they
+// were recorded with; the entry's previous names still resolve it.
+func (db *DatabaseService) migrateCatalogName(ctx context.Context, kind CatalogKind, oldName, newName string) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	readOnly, err := db.Events.Distinct(ctx, "_id", bson.M{"readOnly": true})
+	if err != nil {
+		return err
+	}
+	if readOnly == nil {
+		readOnly = []interface{}{}
+	}
+
+	field := string(kind)
+	teams, err := db.TeamCollection.UpdateMany(ctx,
+		bson.M{field: oldName, "eventId": bson.M{"$nin": readOnly}},
+		versioned(bson.M{"$set": bson.M{field: newName, "updatedAt": time.Now()}}))
+	if err != nil {
+		return err
+	}
+	if teams.ModifiedCount > 0 {
+		db.Logger.InfoContext(ctx, "renamed catalogue entry on team registrations", "kind", kind, "from", oldName, "to", newName, "count", teams.ModifiedCount)
+	}
+
+	if kind != CatalogTrack {
+		return nil
+	}
+
+	// Events reference tracks in their offer list and capacity overrides
+	_, err = db.Events.UpdateMany(ctx,
+		bson.M{"tracks": oldName, "readOnly": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"tracks.$[t]": newName}},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"t": oldName}}}))
+	if err != nil {
+		return err
+	}
+	_, err = db.Events.UpdateMany(ctx,
+		bson.M{"config.trackCapacity." + oldName: bson.M{"$exists": true}, "readOnly": bson.M{"$ne": true}},
+		bson.M{"$rename": bson.M{"config.trackCapacity." + oldName: "config.trackCapacity." + newName}})
+	return err
+}
+
+// promoteTrackEverywhere fills any capacity a track gained in every event with a waitlist for it
+func (db *DatabaseService) promoteTrackEverywhere(ctx context.Context, track Track) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	eventIDs, err := db.TeamCollection.Distinct(ctx, "eventId", notDeleted(bson.M{"track": track, "registrationStatus": StatusWaitlisted}))
+	if err != nil {
+		return err
+	}
+	for _, v := range eventIDs {
+		eventID, ok := v.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		if _, err := db.PromoteWaitlisted(ctx, eventID, track); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteCatalogEntry deletes a track or program that no team registered for
+func (db *DatabaseService) DeleteCatalogEntry(ctx context.Context, kind CatalogKind, slug string) error {
+	entry, err := db.GetCatalogEntry(ctx, kind, slug)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	// Registrations in the trash count, as restoring them brings the name back
+	used, err := db.TeamCollection.CountDocuments(ctx, bson.M{string(kind): entry.Name})
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return ConflictError(CodeCatalogEntryInUse, fmt.Sprintf("%s is in use by %d team registration(s); deactivate it instead", catalogLabel(kind), used))
+	}
+
+	_, err = db.Catalog.DeleteOne(ctx, bson.M{"_id": entry.ID})
+	return err
+}
+
+// EnsureCatalog seeds the tracks and programs of the original edition into an empty catalogue
+func (db *DatabaseService) EnsureCatalog(ctx context.Context) error {
+	seeds := map[CatalogKind][]string{}
+	for _, t := range DefaultTracks {
+		seeds[CatalogTrack] = append(seeds[CatalogTrack], string(t))
+	}
+	for _, p := range DefaultPrograms {
+		seeds[CatalogProgram] = append(seeds[CatalogProgram], string(p))
+	}
+
+	for kind, names := range seeds {
+		existing, err := db.GetCatalogEntries(ctx, kind, true)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			continue
+		}
+		for _, name := range names {
+			if _, err := db.CreateCatalogEntry(ctx, NewCatalogEntry(kind, "", name)); err != nil {
+				return err
+			}
+		}
+		db.Logger.InfoContext(ctx, "seeded catalogue", "kind", kind, "count", len(names))
+	}
+	return nil
+}
+
+// VideoSubmission describes a team's submitted video
+type VideoSubmission struct {
+	Link        string     `json:"link"`
+	SubmittedAt *time.Time `json:"submittedAt,omitempty"`
+}
+
+// GetVideoSubmission returns the team's video submission, or nil if none was found
+func (db *DatabaseService) GetVideoSubmission(ctx context.Context, team *TeamRegistration) (*VideoSubmission, error) {
+	link, err := db.GetVideoLinkForTeam(ctx, team)
+	if err != nil || link == "" {
+		return nil, err
+	}
+
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	// The videos collection is written by another service; look for a common timestamp field
+	var doc bson.M
+	filter := bson.M{"$or": []bson.M{
+		{"registrationId": team.RegistrationNumber},
+		{"registrationNumber": team.RegistrationNumber},
+		{"teamId": team.TeamID},
+		{"teamName": team.TeamName},
+	}}
+	if err := db.Videos.FindOne(ctx, filter).Decode(&doc); err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	submission := &VideoSubmission{Link: link}
+	for _, k := range []string{"submittedAt", "createdAt", "timestamp", "uploadedAt"} {
+		if v, ok := doc[k].(primitive.DateTime); ok {
+			t := v.Time()
+			submission.SubmittedAt = &t
+			break
+		}
+	}
+	return submission, nil
+}
+
+// Certificate Operations
+
+// CreateCertificate stores a new certificate
+func (db *DatabaseService) CreateCertificate(ctx context.Context, cert *Certificate) (*Certificate, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	cert.ID = primitive.NewObjectID()
+	if cert.IssuedAt.IsZero() {
+		cert.IssuedAt = time.Now()
+	}
+
+	if _, err := db.Certificates.InsertOne(ctx, cert); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// GetCertificateByCode retrieves a certificate by its verification code
+func (db *DatabaseService) GetCertificateByCode(ctx context.Context, code string) (*Certificate, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	var cert Certificate
+	err := db.Certificates.FindOne(ctx, bson.M{"code": strings.ToUpper(strings.TrimSpace(code))}).Decode(&cert)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCertificateNotFound
+		}
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// FindCertificate looks up an already issued certificate for a recipient of a team.
+// A team can win several awards, so winner certificates are told apart by award; an
+// empty award matches certificates issued without one.
+func (db *DatabaseService) FindCertificate(ctx context.Context, teamID primitive.ObjectID, certType CertificateType, recipientName, award string) (*Certificate, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	var cert Certificate
+	filter := bson.M{"teamRegistrationId": teamID, "type": certType, "recipientName": recipientName, "award": nil}
+	if award != "" {
+		filter["award"] = award
+	}
+	err := db.Certificates.FindOne(ctx, filter).Decode(&cert)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// HasCertificate reports whether a team was issued a certificate of the given type
+func (db *DatabaseService) HasCertificate(ctx context.Context, teamID primitive.ObjectID, certType CertificateType) (bool, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	count, err := db.Certificates.CountDocuments(ctx, bson.M{"teamRegistrationId": teamID, "type": certType}, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// GetCertificates retrieves certificates matching a filter
+func (db *DatabaseService) GetCertificates(ctx context.Context, filter bson.M) ([]*Certificate, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "teamName", Value: 1}, {Key: "recipientRole", Value: 1}})
+	cursor, err := db.Certificates.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	certs := make([]*Certificate, 0)
+	if err := cursor.All(ctx, &certs); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+// Participant Portal Operations
+
+// CreateMagicLink stores a new one-time login token
+func (db *DatabaseService) CreateMagicLink(ctx context.Context, link *MagicLink) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	link.ID = primitive.NewObjectID()
+	link.CreatedAt = time.Now()
+	_, err := db.MagicLinks.InsertOne(ctx, link)
+	return err
+}
+
+// ConsumeMagicLink marks an unused, unexpired token as used and returns it
+func (db *DatabaseService) ConsumeMagicLink(ctx context.Context, tokenHash string) (*MagicLink, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{
+		"tokenHash": tokenHash,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"usedAt": now}}
+
+	var link MagicLink
+	err := db.MagicLinks.FindOneAndUpdate(ctx, filter, update).Decode(&link)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrLoginLinkInvalid
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+// CreateChangeRequest stores a new change request
+func (db *DatabaseService) CreateChangeRequest(ctx context.Context, cr *ChangeRequest) (*ChangeRequest, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	cr.ID = primitive.NewObjectID()
+	if _, err := db.ChangeRequests.InsertOne(ctx, cr); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// GetChangeRequestByID retrieves a change request by ID
+func (db *DatabaseService) GetChangeRequestByID(ctx context.Context, id string) (*ChangeRequest, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidChangeRequestID
+	}
+
+	var cr ChangeRequest
+	err = db.ChangeRequests.FindOne(ctx, bson.M{"_id": objectID}).Decode(&cr)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrChangeRequestNotFound
+		}
+		return nil, err
+	}
+	return &cr, nil
+}
+
+// ListChangeRequests retrieves a page of the change requests matching filter
+func (db *DatabaseService) ListChangeRequests(ctx context.Context, filter bson.M, q PageQuery) (*Page[*ChangeRequest], error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	return findPage[*ChangeRequest](ctx, db.ChangeRequests, filter, q)
+}
+
+// GetChangeRequests retrieves change requests matching a filter, newest first
+func (db *DatabaseService) GetChangeRequests(ctx context.Context, limit int64, skip int64, filter bson.M) ([]*ChangeRequest, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	opts := options.Find().SetLimit(limit).SetSkip(skip).SetSort(bson.M{"createdAt": -1})
+	cursor, err := db.ChangeRequests.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	requests := make([]*ChangeRequest, 0)
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ReviewChangeRequest records the admin decision on a pending change request.
+// Approved changes are applied to the team registration at the version they were
+// requested against.
+func (db *DatabaseService) ReviewChangeRequest(ctx context.Context, id string, approve bool, reason, reviewedBy string) (*ChangeRequest, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidChangeRequestID
+	}
+
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	status := ChangeRequestRejected
+	if approve {
+		status = ChangeRequestApproved
+	}
+
+	now := time.Now()
+	update := bson.M{"$set": bson.M{
+		"status":       status,
+		"reviewedBy":   reviewedBy,
+		"reviewReason": reason,
+		"reviewedAt":   now,
+		"updatedAt":    now,
+	}}
+	// Only transition from pending so concurrent reviews cannot both succeed
+	filter := bson.M{"_id": objectID, "status": ChangeRequestPending}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var cr ChangeRequest
+	if err := db.ChangeRequests.FindOneAndUpdate(ctx, filter, update, opts).Decode(&cr); err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, err := db.GetChangeRequestByID(ctx, id); err != nil {
+				return nil, err
+			}
+			return nil, ErrChangeRequestReviewed
+		}
+		return nil, err
+	}
+
+	if !approve {
+		return &cr, nil
+	}
+
+	updateData, err := cr.Changes.UpdateData()
+	if err == nil && len(updateData) > 0 {
+		err = db.applyChangeRequest(ctx, &cr, updateData)
+	}
+	if err != nil {
+		// Put the request back in the queue so it can be reviewed again
+		revert := bson.M{
+			"$set":   bson.M{"status": ChangeRequestPending, "updatedAt": time.Now()},
+			"$unset": bson.M{"reviewedBy": "", "reviewReason": "", "reviewedAt": ""},
+		}
+		if _, revertErr := db.ChangeRequests.UpdateOne(ctx, bson.M{"_id": cr.ID}, revert); revertErr != nil {
+			db.Logger.ErrorContext(ctx, "failed to return change request to pending", "error", revertErr, "change_request_id", id)
+			return nil, errors.Join(err, fmt.Errorf("failed to return change request to pending: %w", revertErr))
+		}
+		return nil, err
+	}
+	return &cr, nil
+}
+
+// applyChangeRequest writes approved changes to their team registration. Changes made
+// to other fields since the request are kept, the requested ones are rebased onto the
+// current version; a requested field changed since fails with ErrChangeRequestConflict
+// naming it rather than silently overwriting the newer value.
+func (db *DatabaseService) applyChangeRequest(ctx context.Context, cr *ChangeRequest, updateData bson.M) error {
+	version := cr.TeamVersion
+	if cr.Base != nil {
+		team, err := db.GetTeamRegistrationByID(ctx, cr.TeamRegistrationID.Hex())
+		if err != nil {
+			return err
+		}
+		conflicts, err := cr.ConflictingFields(team)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return ErrChangeRequestConflict.With("fields", conflicts)
+		}
+		version = team.Version
+	}
+	_, err := db.UpdateTeamRegistration(ctx, cr.TeamRegistrationID.Hex(), version, updateData)
+	return err
+}
+
+// Metrics Operations
+
+// MetricsStats computes the registration, evaluation and video figures exported as
+// Prometheus gauges
+func (db *DatabaseService) MetricsStats(ctx context.Context) (*metrics.Stats, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	events, err := db.GetEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*metrics.EventStats, len(events))
+	stats := &metrics.Stats{Events: make([]metrics.EventStats, len(events))}
+	for i, event := range events {
+		stats.Events[i] = metrics.EventStats{
+			Event:         event.Slug,
+			TeamsByStatus: make(map[string]int64),
+			TeamsByTrack:  make(map[string]int64),
+		}
+		byID[event.ID] = &stats.Events[i]
+	}
+
+	var counts []struct {
+		ID struct {
+			EventID primitive.ObjectID `bson:"eventId"`
+			Value   string             `bson:"value"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	group := func(field string) error {
+		cursor, err := db.TeamCollection.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: notDeleted(nil)}},
+			{{Key: "$group", Value: bson.M{
+				"_id":   bson.M{"eventId": "$eventId", "value": "$" + field},
+				"count": bson.M{"$sum": 1},
+			}}},
+		})
+		if err != nil {
+			return err
+		}
+		counts = counts[:0]
+		return cursor.All(ctx, &counts)
+	}
+
+	if err := group("registrationStatus"); err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		if e, ok := byID[c.ID.EventID]; ok {
+			e.TeamsByStatus[c.ID.Value] = c.Count
+		}
+	}
+	if err := group("track"); err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		if e, ok := byID[c.ID.EventID]; ok {
+			e.TeamsByTrack[c.ID.Value] = c.Count
+		}
+	}
+
+	for id, e := range byID {
+		e.PendingAllocations, err = db.TeamCollection.CountDocuments(ctx, notDeleted(bson.M{
+			"eventId":            id,
+			"registrationStatus": StatusPending,
+			"allocatedJudgeId":   bson.M{"$exists": false},
+		}))
+		if err != nil {
+			return nil, err
+		}
+		e.EvaluationsSubmitted, err = db.Evaluations.CountDocuments(ctx, bson.M{"eventId": id})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stats.VideosSubmitted, err = db.Videos.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Close closes the database connection
+func (db *DatabaseService) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.Client.Disconnect(ctx); err != nil {
+		db.Logger.ErrorContext(ctx, "failed to disconnect from MongoDB", "error", err)
+	}
+}
+
+// RecordAudit appends an entry to the audit log. The log is append-only: there are no
+// methods to change or remove entries.
+func (db *DatabaseService) RecordAudit(ctx context.Context, entry *AuditEntry) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	entry.ID = primitive.NewObjectID()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	_, err := db.AuditLog.InsertOne(ctx, entry)
+	return err
+}
+
+// ListAuditLog retrieves a page of the audit log entries matching filter
+func (db *DatabaseService) ListAuditLog(ctx context.Context, filter *AuditFilter, q PageQuery) (*Page[*AuditEntry], error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	return findPage[*AuditEntry](ctx, db.AuditLog, filter.query(), q)
+}
+
+// ExportAuditLog calls fn with every audit log entry matching filter, oldest first
+func (db *DatabaseService) ExportAuditLog(ctx context.Context, filter *AuditFilter, fn func(*AuditEntry) error) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := db.AuditLog.Find(ctx, filter.query(), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Trash Operations
+
+// ListDeletedTeamRegistrations retrieves a page of the team registrations of an event in the trash
+func (db *DatabaseService) ListDeletedTeamRegistrations(ctx context.Context, eventID primitive.ObjectID, q PageQuery) (*Page[*TeamRegistration], error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	return findPage[*TeamRegistration](ctx, db.TeamCollection, inTrash(bson.M{"eventId": eventID}), q)
+}
+
+// RestoreTeamRegistration takes a team registration of an event out of the trash. A
+// team approved when it was deleted goes to the waitlist if its slot has been taken
+// since.
+func (db *DatabaseService) RestoreTeamRegistration(ctx context.Context, eventID primitive.ObjectID, id string) (*TeamRegistration, error) {
+	team, err := db.GetDeletedTeamRegistration(ctx, eventID, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.GetTeamRegistrationByTeamName(ctx, eventID, team.TeamName); err == nil {
+		return nil, ConflictError(CodeTeamNameExists, "Another team has registered with this name since the registration was deleted")
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if err := db.restore(ctx, db.TeamCollection, team.ID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, err
+	}
+	restored, err := db.GetTeamRegistrationByID(ctx, id)
+	if err != nil || !restored.IsApproved() {
+		return restored, err
+	}
+
+	capacity, err := db.trackCapacity(ctx, eventID, restored.Track)
+	if err != nil || capacity == 0 {
+		return restored, err
+	}
+	approved, err := db.countApprovedInTrack(ctx, eventID, restored.Track)
+	if err != nil || approved <= int64(capacity) {
+		return restored, err
+	}
+	return db.waitlistTeamRegistration(ctx, restored, restored.ActionedBy)
+}
+
+// GetDeletedTeamRegistration retrieves a team registration of an event in the trash
+func (db *DatabaseService) GetDeletedTeamRegistration(ctx context.Context, eventID primitive.ObjectID, id string) (*TeamRegistration, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidTeamID
+	}
+
+	var team TeamRegistration
+	err = db.TeamCollection.FindOne(ctx, inTrash(bson.M{"_id": objectID, "eventId": eventID})).Decode(&team)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrTeamNotFound
+		}
+		return nil, err
+	}
+	return &team, nil
+}
+
+// ListDeletedUsers retrieves a page of the users in the trash
+func (db *DatabaseService) ListDeletedUsers(ctx context.Context, q PageQuery) (*Page[*User], error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	return findPage[*User](ctx, db.UserCollection, inTrash(nil), q)
+}
+
+// GetDeletedUser retrieves a user in the trash
+func (db *DatabaseService) GetDeletedUser(ctx context.Context, id string) (*User, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	var user User
+	err = db.UserCollection.FindOne(ctx, inTrash(bson.M{"_id": objectID})).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// RestoreUser takes a user out of the trash
+func (db *DatabaseService) RestoreUser(ctx context.Context, id string) (*User, error) {
+	user, err := db.GetDeletedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.GetUserByUsername(ctx, user.Username); err == nil {
+		return nil, ConflictError(CodeUserExists, "Another user has taken this username since the user was deleted")
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if err := db.restore(ctx, db.UserCollection, user.ID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return db.GetUserByID(ctx, id)
+}
+
+// restore takes the record with id out of the trash of coll
+func (db *DatabaseService) restore(ctx context.Context, coll *mongo.Collection, id primitive.ObjectID) error {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	result, err := coll.UpdateOne(ctx, inTrash(bson.M{"_id": id}), versioned(bson.M{
+		"$unset": bson.M{"deletedAt": "", "deletedBy": ""},
+		"$set":   bson.M{"updatedAt": time.Now()},
+	}))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// PurgeReport counts the records a purge removed for good
+type PurgeReport struct {
+	Teams int64 `json:"teams"`
+	Users int64 `json:"users"`
+}
+
+// PurgeDeleted permanently removes the users and team registrations deleted before cutoff
+func (db *DatabaseService) PurgeDeleted(ctx context.Context, cutoff time.Time) (*PurgeReport, error) {
+	ctx, cancel := db.getContext(ctx)
+	defer cancel()
+
+	filter := bson.M{"deletedAt": bson.M{"$lt": cutoff}}
+	teams, err := db.TeamCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	users, err := db.UserCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		return nil, err
 	}
+	return &PurgeReport{Teams: teams.DeletedCount, Users: users.DeletedCount}, nil
 }
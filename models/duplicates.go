@@ -0,0 +1,188 @@
+package models
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DuplicatePolicy decides what happens to a registration that shares a participant
+// with another team of the event
+type DuplicatePolicy string
+
+const (
+	// DuplicatesAllow skips the check
+	DuplicatesAllow DuplicatePolicy = "allow"
+	// DuplicatesFlag saves the registration and records the matches on it
+	DuplicatesFlag DuplicatePolicy = "flag"
+	// DuplicatesBlock rejects the registration
+	DuplicatesBlock DuplicatePolicy = "block"
+)
+
+// Participant is the leader or a member of a team. Mentors may support several teams,
+// so they aren't participants.
+type Participant struct {
+	// Field is the participant's path in the registration: leader or members[i]
+	Field  string
+	Name   string
+	Email  string
+	Mobile string
+}
+
+// Participants returns the leader and the members of the team
+func (t *TeamRegistration) Participants() []Participant {
+	people := []Participant{{Field: "leader", Name: t.LeaderName, Email: t.LeaderEmail, Mobile: t.LeaderMobile}}
+	for i, m := range t.Members {
+		people = append(people, Participant{Field: fmt.Sprintf("members[%d]", i), Name: m.FullName, Email: m.Email, Mobile: m.MobileNo})
+	}
+	return people
+}
+
+// DuplicateMatch is a participant of a team who also appears in another team
+type DuplicateMatch struct {
+	// Field is the participant in this team, e.g. members[1]
+	Field string `bson:"field" json:"field"`
+	// Key is what matched, email or mobile
+	Key                string             `bson:"key" json:"key"`
+	Value              string             `bson:"value" json:"value"`
+	TeamID             primitive.ObjectID `bson:"teamId" json:"teamId"`
+	TeamName           string             `bson:"teamName" json:"teamName"`
+	RegistrationNumber string             `bson:"registrationNumber,omitempty" json:"registrationNumber,omitempty"`
+	// MatchedField is the participant in the other team
+	MatchedField string `bson:"matchedField" json:"matchedField"`
+}
+
+// matchParticipants returns the participants of team that also appear in other
+func matchParticipants(team, other *TeamRegistration) []DuplicateMatch {
+	var matches []DuplicateMatch
+	add := func(p, q Participant, key, value string) {
+		matches = append(matches, DuplicateMatch{
+			Field:              p.Field,
+			Key:                key,
+			Value:              value,
+			TeamID:             other.ID,
+			TeamName:           other.TeamName,
+			RegistrationNumber: other.RegistrationNumber,
+			MatchedField:       q.Field,
+		})
+	}
+	for _, p := range team.Participants() {
+		for _, q := range other.Participants() {
+			if p.Email != "" && p.Email == q.Email {
+				add(p, q, "email", p.Email)
+			}
+			if p.Mobile != "" && p.Mobile == q.Mobile {
+				add(p, q, "mobile", p.Mobile)
+			}
+		}
+	}
+	return matches
+}
+
+// DuplicateReport lists the people registered in more than one team of an event
+type DuplicateReport struct {
+	People []DuplicatePerson `json:"people"`
+	Teams  []DuplicateTeam   `json:"teams"`
+}
+
+// DuplicatePerson is an email or phone number shared by participants of several teams
+type DuplicatePerson struct {
+	Key     string           `json:"key"`
+	Value   string           `json:"value"`
+	Entries []DuplicateEntry `json:"entries"`
+}
+
+// DuplicateEntry is one appearance of a duplicate person
+type DuplicateEntry struct {
+	TeamID             primitive.ObjectID `json:"teamId"`
+	TeamName           string             `json:"teamName"`
+	RegistrationNumber string             `json:"registrationNumber,omitempty"`
+	Field              string             `json:"field"`
+	Name               string             `json:"name"`
+}
+
+// DuplicateTeam is a team sharing participants with other teams
+type DuplicateTeam struct {
+	TeamID             primitive.ObjectID `json:"teamId"`
+	TeamName           string             `json:"teamName"`
+	RegistrationNumber string             `json:"registrationNumber,omitempty"`
+	// Matches counts the shared emails and phone numbers
+	Matches    int                  `json:"matches"`
+	SharedWith []primitive.ObjectID `json:"sharedWith"`
+}
+
+// buildDuplicateReport groups the participants of teams by email and phone number and
+// keeps the groups that span several teams
+func buildDuplicateReport(teams []*TeamRegistration) *DuplicateReport {
+	type group struct {
+		key, value string
+		entries    []DuplicateEntry
+	}
+	groups := map[string]*group{}
+	var order []*group
+	add := func(team *TeamRegistration, p Participant, key, value string) {
+		if value == "" {
+			return
+		}
+		g, ok := groups[key+":"+value]
+		if !ok {
+			g = &group{key: key, value: value}
+			groups[key+":"+value] = g
+			order = append(order, g)
+		}
+		g.entries = append(g.entries, DuplicateEntry{
+			TeamID:             team.ID,
+			TeamName:           team.TeamName,
+			RegistrationNumber: team.RegistrationNumber,
+			Field:              p.Field,
+			Name:               p.Name,
+		})
+	}
+	for _, team := range teams {
+		for _, p := range team.Participants() {
+			add(team, p, "email", p.Email)
+			add(team, p, "mobile", p.Mobile)
+		}
+	}
+
+	report := &DuplicateReport{People: []DuplicatePerson{}, Teams: []DuplicateTeam{}}
+	byTeam := map[primitive.ObjectID]*DuplicateTeam{}
+	for _, g := range order {
+		ids := map[primitive.ObjectID]bool{}
+		for _, e := range g.entries {
+			ids[e.TeamID] = true
+		}
+		if len(ids) < 2 {
+			continue
+		}
+		report.People = append(report.People, DuplicatePerson{Key: g.key, Value: g.value, Entries: g.entries})
+
+		for _, e := range g.entries {
+			t, ok := byTeam[e.TeamID]
+			if !ok {
+				t = &DuplicateTeam{TeamID: e.TeamID, TeamName: e.TeamName, RegistrationNumber: e.RegistrationNumber}
+				byTeam[e.TeamID] = t
+			}
+			t.Matches++
+			for id := range ids {
+				if id != e.TeamID && !slices.Contains(t.SharedWith, id) {
+					t.SharedWith = append(t.SharedWith, id)
+				}
+			}
+		}
+	}
+
+	for _, t := range byTeam {
+		slices.SortFunc(t.SharedWith, func(a, b primitive.ObjectID) int { return cmp.Compare(a.Hex(), b.Hex()) })
+		report.Teams = append(report.Teams, *t)
+	}
+	slices.SortFunc(report.Teams, func(a, b DuplicateTeam) int {
+		if c := cmp.Compare(b.Matches, a.Matches); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.RegistrationNumber, b.RegistrationNumber)
+	})
+	return report
+}
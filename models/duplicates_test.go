@@ -0,0 +1,142 @@
+package models
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// teamWith returns a registration led by leader with the given members, each an
+// email and mobile pair
+func teamWith(name, leaderEmail, leaderMobile string, members ...[2]string) *TeamRegistration {
+	t := &TeamRegistration{
+		ID:                 primitive.NewObjectID(),
+		TeamName:           name,
+		RegistrationNumber: "IGC-" + name,
+		LeaderName:         name + " leader",
+		LeaderEmail:        leaderEmail,
+		LeaderMobile:       leaderMobile,
+	}
+	for _, m := range members {
+		t.Members = append(t.Members, TeamMember{FullName: name + " member", Email: m[0], MobileNo: m[1]})
+	}
+	return t
+}
+
+func TestMatchParticipants(t *testing.T) {
+	a := teamWith("A", "asha@example.org", "+911111111111", [2]string{"ravi@example.org", "+912222222222"})
+	b := teamWith("B", "ravi@example.org", "+913333333333", [2]string{"mira@example.org", "+911111111111"})
+
+	want := []DuplicateMatch{
+		{Field: "leader", Key: "mobile", Value: "+911111111111", TeamID: b.ID, TeamName: "B", RegistrationNumber: "IGC-B", MatchedField: "members[0]"},
+		{Field: "members[0]", Key: "email", Value: "ravi@example.org", TeamID: b.ID, TeamName: "B", RegistrationNumber: "IGC-B", MatchedField: "leader"},
+	}
+	if got := matchParticipants(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("matchParticipants = %+v, want %+v", got, want)
+	}
+
+	c := teamWith("C", "", "", [2]string{"", ""})
+	d := teamWith("D", "", "", [2]string{"", ""})
+	if got := matchParticipants(c, d); len(got) != 0 {
+		t.Errorf("empty contacts matched: %+v", got)
+	}
+}
+
+func TestFindDuplicateParticipants(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("queries other active teams of the event by contact", func(mt *mtest.T) {
+		eventID := primitive.NewObjectID()
+		mine := teamWith("A", "asha@example.org", "+911111111111", [2]string{"ravi@example.org", "+912222222222"})
+		other := teamWith("B", "ravi@example.org", "+913333333333")
+		mt.AddMockResponses(found("teamregistrations", doc(mt, other)))
+
+		matches, err := newTestDB(mt).FindDuplicateParticipants(context.Background(), eventID, mine)
+		if err != nil {
+			mt.Fatalf("FindDuplicateParticipants: %v", err)
+		}
+		if len(matches) != 1 || matches[0].Field != "members[0]" || matches[0].TeamID != other.ID {
+			mt.Errorf("matches = %+v, want members[0] matching team B", matches)
+		}
+
+		filter := nextCommand(mt, "find").Lookup("filter").Document()
+		if got := filter.Lookup("eventId").ObjectID(); got != eventID {
+			mt.Errorf("eventId = %s, want %s", got.Hex(), eventID.Hex())
+		}
+		if got := filter.Lookup("registrationStatus", "$ne").StringValue(); got != string(StatusRejected) {
+			mt.Errorf("status filter excludes %s, want rejected", got)
+		}
+		if got := filter.Lookup("_id", "$ne").ObjectID(); got != mine.ID {
+			mt.Error("the team itself isn't excluded")
+		}
+
+		var query struct {
+			Or []bson.M `bson:"$or"`
+		}
+		if err := bson.Unmarshal(filter, &query); err != nil {
+			mt.Fatalf("decode filter: %v", err)
+		}
+		emails := bson.A{"asha@example.org", "ravi@example.org"}
+		mobiles := bson.A{"+911111111111", "+912222222222"}
+		want := []bson.M{
+			{"leaderEmail": bson.M{"$in": emails}},
+			{"members.email": bson.M{"$in": emails}},
+			{"leaderMobile": bson.M{"$in": mobiles}},
+			{"members.mobileNo": bson.M{"$in": mobiles}},
+		}
+		if !reflect.DeepEqual(query.Or, want) {
+			mt.Errorf("$or = %v, want %v", query.Or, want)
+		}
+	})
+
+	mt.Run("a new registration has no ID to exclude", func(mt *mtest.T) {
+		mine := teamWith("A", "asha@example.org", "+911111111111")
+		mine.ID = primitive.NilObjectID
+		mt.AddMockResponses(found("teamregistrations"))
+
+		matches, err := newTestDB(mt).FindDuplicateParticipants(context.Background(), primitive.NewObjectID(), mine)
+		if err != nil || len(matches) != 0 {
+			mt.Fatalf("FindDuplicateParticipants = %v, %v; want no matches", matches, err)
+		}
+		if _, err := nextCommand(mt, "find").LookupErr("filter", "_id"); err == nil {
+			mt.Error("filter excludes an ID for an unsaved team")
+		}
+	})
+}
+
+func TestBuildDuplicateReport(t *testing.T) {
+	a := teamWith("A", "asha@example.org", "+911111111111")
+	b := teamWith("B", "ravi@example.org", "+912222222222", [2]string{"asha@example.org", "+911111111111"})
+	c := teamWith("C", "mira@example.org", "+913333333333", [2]string{"ravi@example.org", "+914444444444"})
+	d := teamWith("D", "solo@example.org", "+915555555555")
+
+	report := buildDuplicateReport([]*TeamRegistration{a, b, c, d})
+
+	if len(report.People) != 3 {
+		t.Fatalf("people = %+v, want asha's email and mobile and ravi's email", report.People)
+	}
+	if p := report.People[0]; p.Key != "email" || p.Value != "asha@example.org" || len(p.Entries) != 2 ||
+		p.Entries[0].TeamID != a.ID || p.Entries[1].Field != "members[0]" {
+		t.Errorf("first person = %+v", p)
+	}
+
+	if len(report.Teams) != 3 {
+		t.Fatalf("teams = %+v, want A, B and C", report.Teams)
+	}
+	// B shares three contacts, A two and C one
+	if report.Teams[0].TeamID != b.ID || report.Teams[0].Matches != 3 || len(report.Teams[0].SharedWith) != 2 {
+		t.Errorf("first team = %+v, want B with 3 matches shared with A and C", report.Teams[0])
+	}
+	if report.Teams[1].TeamID != a.ID || report.Teams[2].TeamID != c.ID {
+		t.Errorf("teams ordered %s, %s, want A then C", report.Teams[1].TeamName, report.Teams[2].TeamName)
+	}
+
+	empty := buildDuplicateReport(nil)
+	if empty.People == nil || empty.Teams == nil {
+		t.Error("an empty report must render [] rather than null")
+	}
+}
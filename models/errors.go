@@ -0,0 +1,239 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// Error kinds. Every domain error matches exactly one of them with errors.Is, so
+// callers can branch on what went wrong without looking at messages.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrInvalidID         = errors.New("invalid ID")
+	ErrConflict          = errors.New("conflict")
+	ErrValidation        = errors.New("validation failed")
+	ErrForbidden         = errors.New("forbidden")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrInvalidTransition = errors.New("invalid state transition")
+	// ErrPreconditionFailed is the kind of requests whose preconditions, such as
+	// If-Match, don't hold
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// Error codes are stable identifiers clients can rely on; messages may change
+const (
+	CodeInternal       = "internal_error"
+	CodeInvalidID      = "invalid_id"
+	CodeInvalidRequest = "invalid_request"
+
+	CodeInvalidEvent        = "invalid_event"
+	CodeInvalidEventConfig  = "invalid_event_config"
+	CodeInvalidCatalogEntry = "invalid_catalog_entry"
+	CodeInvalidScores       = "invalid_scores"
+
+	CodeUserNotFound          = "user_not_found"
+	CodeTeamNotFound          = "team_not_found"
+	CodeEventNotFound         = "event_not_found"
+	CodeTrackNotFound         = "track_not_found"
+	CodeProgramNotFound       = "program_not_found"
+	CodeCertificateNotFound   = "certificate_not_found"
+	CodeChangeRequestNotFound = "change_request_not_found"
+
+	CodeUserExists           = "user_exists"
+	CodeTeamNameExists       = "team_name_exists"
+	CodeEventExists          = "event_exists"
+	CodeNumberPrefixInUse    = "number_prefix_in_use"
+	CodeCatalogEntryExists   = "catalog_entry_exists"
+	CodeCatalogEntryInUse    = "catalog_entry_in_use"
+	CodeProgramFull          = "program_full"
+	CodeDuplicateParticipant = "duplicate_participant"
+	CodeEventReadOnly        = "event_read_only"
+
+	CodeChangeRequestPending  = "change_request_pending"
+	CodeChangeRequestReviewed = "change_request_reviewed"
+	CodeChangeRequestConflict = "change_request_conflict"
+
+	CodeVersionConflict = "version_conflict"
+	CodeVersionMismatch = "version_mismatch"
+
+	CodeInvalidCredentials         = "invalid_credentials"
+	CodeTokenInvalid               = "token_invalid"
+	CodeLoginLinkInvalid           = "login_link_invalid"
+	CodeInsufficientPermissions    = "insufficient_permissions"
+	CodeParticipantSessionRequired = "participant_session_required"
+	CodeStaffSessionRequired       = "staff_session_required"
+	CodeJudgeNotInPool             = "judge_not_in_pool"
+	CodeActorRequired              = "actor_required"
+)
+
+// Error is a domain error. Message is written for API clients; Cause holds the
+// underlying error for the logs and is never shown to clients.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Cause   error
+	// Extensions are additional members of the problem response, e.g. the conflicting record
+	Extensions map[string]any
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+// Is matches the kind of e, and other domain errors with the same code
+func (e *Error) Is(target error) bool {
+	if target == e.Kind {
+		return true
+	}
+	other, ok := target.(*Error)
+	return ok && other.Code == e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// WithCause returns a copy of e caused by err
+func (e *Error) WithCause(err error) *Error {
+	copied := *e
+	copied.Cause = err
+	return &copied
+}
+
+// With returns a copy of e with an additional member in its problem response
+func (e *Error) With(key string, value any) *Error {
+	copied := *e
+	copied.Extensions = maps.Clone(e.Extensions)
+	if copied.Extensions == nil {
+		copied.Extensions = map[string]any{}
+	}
+	copied.Extensions[key] = value
+	return &copied
+}
+
+// NotFoundError reports a record that doesn't exist
+func NotFoundError(code, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+// InvalidIDError reports a malformed record ID
+func InvalidIDError(message string) *Error {
+	return &Error{Kind: ErrInvalidID, Code: CodeInvalidID, Message: message}
+}
+
+// ConflictError reports a change that clashes with existing records
+func ConflictError(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// ValidationError reports input that breaks a rule
+func ValidationError(code, message string) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message}
+}
+
+func validationErrorf(code, format string, args ...any) *Error {
+	return ValidationError(code, fmt.Sprintf(format, args...))
+}
+
+// ForbiddenError reports an action the caller isn't allowed to perform
+func ForbiddenError(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+// UnauthorizedError reports missing or invalid credentials
+func UnauthorizedError(code, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+// InvalidTransitionError reports a status change the record's current status doesn't allow
+func InvalidTransitionError(code, message string) *Error {
+	return &Error{Kind: ErrInvalidTransition, Code: code, Message: message}
+}
+
+// PreconditionFailedError reports a request whose preconditions don't hold
+func PreconditionFailedError(code, message string) *Error {
+	return &Error{Kind: ErrPreconditionFailed, Code: code, Message: message}
+}
+
+// Field error codes identify the rule an invalid field breaks
+const (
+	FieldRequired      = "required"
+	FieldInvalidEmail  = "invalid_email"
+	FieldInvalidPhone  = "invalid_phone"
+	FieldNotLowercase  = "not_lowercase"
+	FieldInvalidURL    = "invalid_url"
+	FieldInvalidChoice = "invalid_choice"
+	FieldTooShort      = "too_short"
+	FieldTooLong       = "too_long"
+	FieldTooFew        = "too_few"
+	FieldTooMany       = "too_many"
+	FieldOutOfRange    = "out_of_range"
+	FieldInvalidType   = "invalid_type"
+	FieldInvalid       = "invalid"
+)
+
+// FieldError describes one invalid field of a request by its JSON path, e.g. members[2].email
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// InvalidFieldsError reports a request with invalid fields; the fields are listed
+// under "errors" in the problem response
+func InvalidFieldsError(fields []FieldError) *Error {
+	return ValidationError(CodeInvalidRequest, "The request has invalid fields").With("errors", fields)
+}
+
+// Errors returned by the DatabaseService
+var (
+	ErrUserNotFound          = NotFoundError(CodeUserNotFound, "User not found")
+	ErrTeamNotFound          = NotFoundError(CodeTeamNotFound, "Team registration not found")
+	ErrEventNotFound         = NotFoundError(CodeEventNotFound, "Event not found")
+	ErrCertificateNotFound   = NotFoundError(CodeCertificateNotFound, "Certificate not found")
+	ErrChangeRequestNotFound = NotFoundError(CodeChangeRequestNotFound, "Change request not found")
+	ErrLoginLinkInvalid      = UnauthorizedError(CodeLoginLinkInvalid, "Login link is invalid or has expired")
+	// ErrActorRequired reports a change that must be attributed to a user but has none
+	ErrActorRequired = UnauthorizedError(CodeActorRequired, "The change must be made by an authenticated user")
+
+	ErrInvalidUserID          = InvalidIDError("Invalid user ID")
+	ErrInvalidTeamID          = InvalidIDError("Invalid team registration ID")
+	ErrInvalidEventID         = InvalidIDError("Invalid event ID")
+	ErrInvalidChangeRequestID = InvalidIDError("Invalid change request ID")
+
+	ErrEventExists           = ConflictError(CodeEventExists, "Event slug or number prefix already exists")
+	ErrNumberPrefixInUse     = ConflictError(CodeNumberPrefixInUse, "Number prefix already used by another event")
+	ErrChangeRequestReviewed = InvalidTransitionError(CodeChangeRequestReviewed, "Change request has already been reviewed")
+	// ErrChangeRequestConflict reports requested changes to fields of a registration that
+	// have been changed since the request
+	ErrChangeRequestConflict = ConflictError(CodeChangeRequestConflict, "The team registration has changed the requested fields since the request; reject it or ask the team to submit a new one")
+
+	// ErrVersionConflict reports a write lost to a concurrent change of the record
+	ErrVersionConflict = ConflictError(CodeVersionConflict, "The record was changed by someone else in the meantime; reload it and try again")
+	// ErrVersionMismatch reports a write based on an outdated version of the record
+	ErrVersionMismatch = PreconditionFailedError(CodeVersionMismatch, "The record has changed since it was read; reload it and try again")
+)
+
+// catalogNotFound is the not-found error of a catalogue kind
+func catalogNotFound(kind CatalogKind) *Error {
+	if kind == CatalogProgram {
+		return NotFoundError(CodeProgramNotFound, "Program not found")
+	}
+	return NotFoundError(CodeTrackNotFound, "Track not found")
+}
+
+func catalogExists(kind CatalogKind) *Error {
+	return ConflictError(CodeCatalogEntryExists, catalogLabel(kind)+" slug or name already exists")
+}
+
+func catalogLabel(kind CatalogKind) string {
+	if kind == CatalogProgram {
+		return "Program"
+	}
+	return "Track"
+}
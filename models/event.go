@@ -0,0 +1,163 @@
+package models
+
+import (
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultTracks are the tracks of the original edition, used when seeding the first event
+var DefaultTracks = []Track{
+	TrackClimateForecasting,
+	TrackSmartAgriculture,
+	TrackDisasterManagement,
+	TrackGreenTransportation,
+	TrackEnergyOptimization,
+	TrackWaterConservation,
+	TrackCarbonTracking,
+	TrackBiodiversityMonitoring,
+	TrackSustainableCities,
+	TrackWasteManagement,
+	TrackAirQuality,
+	TrackDeforestationPrevention,
+	TrackClimateEducation,
+	TrackAIEnvironmentalData,
+	TrackPublicHealthClimate,
+	TrackOceanMarine,
+}
+
+// RubricCriterion is one scored aspect of a judge's evaluation
+type RubricCriterion struct {
+	Key         string  `bson:"key" json:"key" binding:"required"`
+	Name        string  `bson:"name" json:"name" binding:"required"`
+	Description string  `bson:"description,omitempty" json:"description,omitempty"`
+	MaxScore    float64 `bson:"maxScore" json:"maxScore" binding:"required,gt=0"`
+	Weight      float64 `bson:"weight" json:"weight" binding:"gte=0"`
+}
+
+// Event represents one edition of the challenge.
+// Teams, allocations, evaluations and certificates all belong to exactly one event.
+type Event struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	Slug         string               `bson:"slug" json:"slug"`
+	Name         string               `bson:"name" json:"name"`
+	NumberPrefix string               `bson:"numberPrefix" json:"numberPrefix"`
+	TeamIDPrefix string               `bson:"teamIdPrefix" json:"teamIdPrefix"`
+	Tracks       []Track              `bson:"tracks" json:"tracks"`
+	Rubric       []RubricCriterion    `bson:"rubric" json:"rubric"`
+	JudgeIDs     []primitive.ObjectID `bson:"judgeIds" json:"judgeIds"`
+	Config       EventConfig          `bson:"config" json:"config"`
+	// Active marks the event used when a request does not name one
+	Active bool `bson:"active" json:"active"`
+	// ReadOnly marks a past edition: it stays queryable but cannot be modified
+	ReadOnly  bool      `bson:"readOnly" json:"readOnly"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+var prefixPattern = regexp.MustCompile(`^[A-Z0-9]{2,20}$`)
+
+// NewEvent creates an event with default values
+func NewEvent(slug, name string) *Event {
+	now := time.Now()
+	return &Event{
+		Slug:         slug,
+		Name:         name,
+		NumberPrefix: "PCCOEIGC",
+		TeamIDPrefix: "IGC",
+		Tracks:       append([]Track(nil), DefaultTracks...),
+		Rubric:       make([]RubricCriterion, 0),
+		JudgeIDs:     make([]primitive.ObjectID, 0),
+		Config:       *NewEventConfig(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// Validate checks the event for inconsistent values
+func (e *Event) Validate() error {
+	if !slugPattern.MatchString(e.Slug) {
+		return validationErrorf(CodeInvalidEvent, "Slug must be lowercase letters, digits and dashes")
+	}
+	if !prefixPattern.MatchString(e.NumberPrefix) || !prefixPattern.MatchString(e.TeamIDPrefix) {
+		return validationErrorf(CodeInvalidEvent, "Number prefixes must be 2-20 uppercase letters or digits")
+	}
+	seen := make(map[string]bool)
+	for _, c := range e.Rubric {
+		if seen[c.Key] {
+			return validationErrorf(CodeInvalidEvent, "Duplicate rubric criterion %q", c.Key)
+		}
+		seen[c.Key] = true
+		if c.MaxScore <= 0 {
+			return validationErrorf(CodeInvalidEvent, "Rubric criterion %q must have a positive maxScore", c.Key)
+		}
+	}
+	return e.Config.Validate()
+}
+
+// HasTrack reports whether the event offers a track (events without tracks accept any)
+func (e *Event) HasTrack(track Track) bool {
+	if len(e.Tracks) == 0 {
+		return true
+	}
+	for _, t := range e.Tracks {
+		if t == track {
+			return true
+		}
+	}
+	return false
+}
+
+// HasJudge reports whether a judge belongs to the event's pool (an empty pool accepts all judges)
+func (e *Event) HasJudge(judgeID primitive.ObjectID) bool {
+	if len(e.JudgeIDs) == 0 {
+		return true
+	}
+	for _, id := range e.JudgeIDs {
+		if id == judgeID {
+			return true
+		}
+	}
+	return false
+}
+
+// ScoreEvaluation validates rubric scores and returns the weighted total.
+// Criteria with a zero weight count once.
+func (e *Event) ScoreEvaluation(scores map[string]float64) (float64, error) {
+	criteria := make(map[string]RubricCriterion, len(e.Rubric))
+	for _, c := range e.Rubric {
+		criteria[c.Key] = c
+	}
+
+	total := 0.0
+	for key, score := range scores {
+		c, ok := criteria[key]
+		if !ok {
+			return 0, validationErrorf(CodeInvalidScores, "Unknown rubric criterion %q", key)
+		}
+		if score < 0 || score > c.MaxScore {
+			return 0, validationErrorf(CodeInvalidScores, "Score for %q must be between 0 and %g", key, c.MaxScore)
+		}
+		weight := c.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		total += score * weight
+	}
+	return total, nil
+}
+
+// Evaluation is a judge's decision and rubric scores for a team
+type Evaluation struct {
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID            primitive.ObjectID `bson:"eventId" json:"eventId"`
+	TeamRegistrationID primitive.ObjectID `bson:"teamRegistrationId" json:"teamRegistrationId"`
+	JudgeID            primitive.ObjectID `bson:"judgeId" json:"judgeId"`
+	Decision           string             `bson:"decision" json:"decision"`
+	Reason             string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	Scores             map[string]float64 `bson:"scores,omitempty" json:"scores,omitempty"`
+	Total              float64            `bson:"total" json:"total"`
+	CreatedAt          time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+)
+
+// EventConfig holds the registration schedule and capacity limits of an event.
+// Nil times and zero capacities mean "no restriction".
+type EventConfig struct {
+	RegistrationOpensAt     *time.Time     `bson:"registrationOpensAt,omitempty" json:"registrationOpensAt,omitempty"`
+	RegistrationClosesAt    *time.Time     `bson:"registrationClosesAt,omitempty" json:"registrationClosesAt,omitempty"`
+	VideoSubmissionDeadline *time.Time     `bson:"videoSubmissionDeadline,omitempty" json:"videoSubmissionDeadline,omitempty"`
+	ParticipantEditDeadline *time.Time     `bson:"participantEditDeadline,omitempty" json:"participantEditDeadline,omitempty"`
+	DefaultTrackCapacity    int            `bson:"defaultTrackCapacity" json:"defaultTrackCapacity"`
+	TrackCapacity           map[string]int `bson:"trackCapacity,omitempty" json:"trackCapacity,omitempty"`
+	// DuplicatePolicy handles registrations sharing a participant with another team (default: flag)
+	DuplicatePolicy DuplicatePolicy `bson:"duplicatePolicy,omitempty" json:"duplicatePolicy,omitempty"`
+}
+
+// NewEventConfig returns a configuration without any restrictions
+func NewEventConfig() *EventConfig {
+	return &EventConfig{TrackCapacity: map[string]int{}}
+}
+
+// CapacityFor returns the maximum number of approved teams for a track (0 = unlimited):
+// the event's own limit for the track, else the catalogue capacity, else the event default
+func (ec *EventConfig) CapacityFor(track Track, catalogCapacity int) int {
+	if capacity, ok := ec.TrackCapacity[string(track)]; ok {
+		return capacity
+	}
+	if catalogCapacity > 0 {
+		return catalogCapacity
+	}
+	return ec.DefaultTrackCapacity
+}
+
+// Duplicates returns the duplicate participant policy of the event
+func (ec *EventConfig) Duplicates() DuplicatePolicy {
+	if ec.DuplicatePolicy == "" {
+		return DuplicatesFlag
+	}
+	return ec.DuplicatePolicy
+}
+
+// Validate checks the configuration for inconsistent values
+func (ec *EventConfig) Validate() error {
+	if ec.RegistrationOpensAt != nil && ec.RegistrationClosesAt != nil && !ec.RegistrationClosesAt.After(*ec.RegistrationOpensAt) {
+		return validationErrorf(CodeInvalidEventConfig, "registrationClosesAt must be after registrationOpensAt")
+	}
+	if ec.DefaultTrackCapacity < 0 {
+		return validationErrorf(CodeInvalidEventConfig, "defaultTrackCapacity cannot be negative")
+	}
+	switch ec.DuplicatePolicy {
+	case "", DuplicatesAllow, DuplicatesFlag, DuplicatesBlock:
+	default:
+		return validationErrorf(CodeInvalidEventConfig, "duplicatePolicy must be allow, flag or block")
+	}
+	for track, capacity := range ec.TrackCapacity {
+		if capacity < 0 {
+			return validationErrorf(CodeInvalidEventConfig, "Capacity for track %q cannot be negative", track)
+		}
+	}
+	return nil
+}
+
+// Deadline error codes
+const (
+	CodeRegistrationNotOpen = "registration_not_open"
+	CodeRegistrationClosed  = "registration_closed"
+	CodeEditDeadlinePassed  = "edit_deadline_passed"
+	CodeVideoDeadlinePassed = "video_deadline_passed"
+)
+
+// DeadlineError reports an action attempted outside its allowed window
+type DeadlineError struct {
+	Code     string    `json:"code"`
+	Message  string    `json:"error"`
+	Deadline time.Time `json:"deadline"`
+}
+
+func (e *DeadlineError) Error() string {
+	return e.Message
+}
+
+// CheckRegistrationOpen returns a DeadlineError if registrations are not accepted at t
+func (ec *EventConfig) CheckRegistrationOpen(t time.Time) error {
+	if ec.RegistrationOpensAt != nil && t.Before(*ec.RegistrationOpensAt) {
+		return &DeadlineError{Code: CodeRegistrationNotOpen, Message: "Registration has not opened yet", Deadline: *ec.RegistrationOpensAt}
+	}
+	if ec.RegistrationClosesAt != nil && !t.Before(*ec.RegistrationClosesAt) {
+		return &DeadlineError{Code: CodeRegistrationClosed, Message: "Registration is closed", Deadline: *ec.RegistrationClosesAt}
+	}
+	return nil
+}
+
+// CheckParticipantEdit returns a DeadlineError if participant changes are not accepted at t
+func (ec *EventConfig) CheckParticipantEdit(t time.Time) error {
+	if ec.ParticipantEditDeadline != nil && !t.Before(*ec.ParticipantEditDeadline) {
+		return &DeadlineError{Code: CodeEditDeadlinePassed, Message: "The deadline for registration changes has passed", Deadline: *ec.ParticipantEditDeadline}
+	}
+	return nil
+}
+
+// CheckVideoSubmission returns a DeadlineError if a video submitted at t is late
+func (ec *EventConfig) CheckVideoSubmission(t time.Time) error {
+	if ec.VideoSubmissionDeadline != nil && t.After(*ec.VideoSubmissionDeadline) {
+		return &DeadlineError{Code: CodeVideoDeadlinePassed, Message: "The video was submitted after the deadline", Deadline: *ec.VideoSubmissionDeadline}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckRegistrationOpen(t *testing.T) {
+	opens := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	closes := time.Date(2026, 2, 10, 18, 0, 0, 0, time.UTC)
+	cfg := &EventConfig{RegistrationOpensAt: &opens, RegistrationClosesAt: &closes}
+
+	tests := []struct {
+		name     string
+		cfg      *EventConfig
+		at       time.Time
+		wantCode string
+	}{
+		{"before opening", cfg, opens.Add(-time.Second), CodeRegistrationNotOpen},
+		{"at opening", cfg, opens, ""},
+		{"while open", cfg, opens.Add(24 * time.Hour), ""},
+		{"at closing", cfg, closes, CodeRegistrationClosed},
+		{"after closing", cfg, closes.Add(time.Hour), CodeRegistrationClosed},
+		{"no window", NewEventConfig(), closes.Add(time.Hour), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkDeadline(t, tt.cfg.CheckRegistrationOpen(tt.at), tt.wantCode)
+		})
+	}
+}
+
+func TestCheckDeadlines(t *testing.T) {
+	deadline := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &EventConfig{ParticipantEditDeadline: &deadline, VideoSubmissionDeadline: &deadline}
+
+	edit := []struct {
+		at       time.Time
+		wantCode string
+	}{
+		{deadline.Add(-time.Second), ""},
+		{deadline, CodeEditDeadlinePassed},
+	}
+	for _, tt := range edit {
+		checkDeadline(t, cfg.CheckParticipantEdit(tt.at), tt.wantCode)
+	}
+
+	// A video submitted exactly at the deadline is on time
+	video := []struct {
+		at       time.Time
+		wantCode string
+	}{
+		{deadline, ""},
+		{deadline.Add(time.Second), CodeVideoDeadlinePassed},
+	}
+	for _, tt := range video {
+		checkDeadline(t, cfg.CheckVideoSubmission(tt.at), tt.wantCode)
+	}
+}
+
+// checkDeadline fails unless err is a DeadlineError with wantCode, or nil for ""
+func checkDeadline(t *testing.T, err error, wantCode string) {
+	t.Helper()
+	if wantCode == "" {
+		if err != nil {
+			t.Errorf("error = %v, want none", err)
+		}
+		return
+	}
+	var deadlineErr *DeadlineError
+	if !errors.As(err, &deadlineErr) || deadlineErr.Code != wantCode {
+		t.Errorf("error = %v, want deadline error %s", err, wantCode)
+	}
+}
+
+func TestCapacityFor(t *testing.T) {
+	cfg := &EventConfig{DefaultTrackCapacity: 20, TrackCapacity: map[string]int{"AI": 5, "IoT": 0}}
+	tests := []struct {
+		track   Track
+		catalog int
+		want    int
+	}{
+		{"AI", 8, 5},
+		{"IoT", 8, 0},
+		{"Web", 8, 8},
+		{"Web", 0, 20},
+	}
+	for _, tt := range tests {
+		if got := cfg.CapacityFor(tt.track, tt.catalog); got != tt.want {
+			t.Errorf("CapacityFor(%s, %d) = %d, want %d", tt.track, tt.catalog, got, tt.want)
+		}
+	}
+}
+
+func TestEventConfigValidate(t *testing.T) {
+	opens := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		cfg     EventConfig
+		wantErr bool
+	}{
+		{"empty", EventConfig{}, false},
+		{"window", EventConfig{RegistrationOpensAt: &opens, RegistrationClosesAt: ptr(opens.Add(time.Hour))}, false},
+		{"closes at opening", EventConfig{RegistrationOpensAt: &opens, RegistrationClosesAt: &opens}, true},
+		{"negative default capacity", EventConfig{DefaultTrackCapacity: -1}, true},
+		{"negative track capacity", EventConfig{TrackCapacity: map[string]int{"AI": -3}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// testDatabase is the database name of the mock deployment
+const testDatabase = "igc_test"
+
+// newTestDB returns a DatabaseService whose commands are answered by the mock
+// deployment of mt; queue the replies with mt.AddMockResponses
+func newTestDB(mt *mtest.T) *DatabaseService {
+	return NewDatabaseService(mt.Client, testDatabase, "videos")
+}
+
+// found is the reply to a find command returning docs from collection
+func found(collection string, docs ...bson.D) bson.D {
+	return mtest.CreateCursorResponse(0, testDatabase+"."+collection, mtest.FirstBatch, docs...)
+}
+
+// counted is the reply to a CountDocuments of collection
+func counted(collection string, n int64) bson.D {
+	return found(collection, bson.D{{Key: "n", Value: n}})
+}
+
+// modified is the reply to a findAndModify returning v, or nothing for nil
+func modified(t testing.TB, v interface{}) bson.D {
+	if v == nil {
+		return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil})
+	}
+	return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc(t, v)})
+}
+
+// updated is the reply to an update command that changed n documents
+func updated(n int32) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+}
+
+// doc converts a record into the document the database would return for it
+func doc(t testing.TB, v interface{}) bson.D {
+	t.Helper()
+	data, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal %T: %v", v, err)
+	}
+	var d bson.D
+	if err := bson.Unmarshal(data, &d); err != nil {
+		t.Fatalf("unmarshal %T: %v", v, err)
+	}
+	return d
+}
+
+// nextCommand returns the next command sent to the mock deployment, failing the
+// test when it isn't named name
+func nextCommand(mt *mtest.T, name string) bson.Raw {
+	mt.Helper()
+	event := mt.GetStartedEvent()
+	if event == nil {
+		mt.Fatalf("no %s command was sent", name)
+	}
+	if event.CommandName != name {
+		mt.Fatalf("command = %s %v, want %s", event.CommandName, event.Command, name)
+	}
+	return event.Command
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrInvalidCursor is returned for cursors that weren't issued for the requested sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Sort orders a list by one field; ties are broken by _id in the same direction
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// String returns the sort as written in the sort query parameter, e.g. -submittedAt
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// SortFields lists the fields a list can be sorted by. The names are the document
+// fields, which are also the JSON field names.
+type SortFields []string
+
+// Sortable fields of the paged lists
+var (
+	UserSortFields          = SortFields{"username", "role", "createdAt"}
+	TeamSortFields          = SortFields{"submittedAt", "createdAt", "updatedAt", "teamName", "registrationNumber", "institution", "track"}
+	EvaluationSortFields    = SortFields{"createdAt", "total", "decision"}
+	ChangeRequestSortFields = SortFields{"createdAt", "updatedAt", "status", "registrationNumber"}
+)
+
+// Parse reads a sort like teamName (ascending) or -submittedAt (descending)
+func (f SortFields) Parse(s string) (Sort, bool) {
+	field, desc := strings.CutPrefix(s, "-")
+	if !slices.Contains(f, field) {
+		return Sort{}, false
+	}
+	return Sort{Field: field, Desc: desc}, true
+}
+
+// PageQuery selects a page of a list: up to Limit items in Sort order, starting after
+// Cursor, or after skipping Offset items when there is no cursor
+type PageQuery struct {
+	Limit  int64
+	Sort   Sort
+	Cursor *Cursor
+	Offset int64
+}
+
+// Page is one page of a list
+type Page[T any] struct {
+	Items []T
+	// Total counts the items of the whole list
+	Total   int64
+	HasMore bool
+	// NextCursor continues the list after the last item; empty on the last page
+	NextCursor string
+}
+
+// Cursor is the position of an item in a sorted list. Clients receive it as an opaque
+// string and must not depend on its contents.
+type Cursor struct {
+	Sort  string             `bson:"s"`
+	Value interface{}        `bson:"v"`
+	ID    primitive.ObjectID `bson:"id"`
+}
+
+// Encode returns the opaque form of the cursor
+func (c *Cursor) Encode() string {
+	data, err := bson.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads a cursor issued for a list in the given sort order
+func DecodeCursor(s string, sort Sort) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := bson.Unmarshal(data, &c); err != nil || c.Sort != sort.String() || c.ID.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// after matches the items that follow the cursor in sort order
+func (c *Cursor) after(sort Sort) bson.M {
+	op := "$gt"
+	if sort.Desc {
+		op = "$lt"
+	}
+	tie := bson.M{sort.Field: c.Value, "_id": bson.M{op: c.ID}}
+	// Null sorts before every other value, and comparisons with null match nothing
+	if c.Value == nil {
+		if sort.Desc {
+			return tie
+		}
+		return bson.M{"$or": bson.A{bson.M{sort.Field: bson.M{"$ne": nil}}, tie}}
+	}
+	beyond := bson.M{sort.Field: bson.M{op: c.Value}}
+	if sort.Desc {
+		// Missing values come last in descending order
+		beyond = bson.M{"$or": bson.A{beyond, bson.M{sort.Field: nil}}}
+	}
+	return bson.M{"$or": bson.A{beyond, tie}}
+}
+
+// findPage reads one page of the documents of coll matching filter
+func findPage[T any](ctx context.Context, coll *mongo.Collection, filter bson.M, q PageQuery) (*Page[T], error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	total, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	direction := 1
+	if q.Sort.Desc {
+		direction = -1
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: q.Sort.Field, Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(q.Limit + 1)
+	find := filter
+	if q.Cursor != nil {
+		find = bson.M{"$and": bson.A{filter, q.Cursor.after(q.Sort)}}
+	} else if q.Offset > 0 {
+		opts.SetSkip(q.Offset)
+	}
+
+	cursor, err := coll.Find(ctx, find, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	page := &Page[T]{Items: make([]T, 0, q.Limit), Total: total}
+	var last bson.Raw
+	for cursor.Next(ctx) {
+		if int64(len(page.Items)) == q.Limit {
+			page.HasMore = true
+			break
+		}
+		var item T
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, item)
+		last = slices.Clone(cursor.Current)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	if page.HasMore && last != nil {
+		next := &Cursor{Sort: q.Sort.String()}
+		next.ID, _ = last.Lookup("_id").ObjectIDOK()
+		if v, err := last.LookupErr(strings.Split(q.Sort.Field, ".")...); err == nil {
+			if err := v.Unmarshal(&next.Value); err != nil {
+				return nil, err
+			}
+		}
+		page.NextCursor = next.Encode()
+	}
+	return page, nil
+}
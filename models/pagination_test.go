@@ -0,0 +1,240 @@
+package models
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	id := primitive.NewObjectID()
+	byName := Sort{Field: "teamName"}
+	newest := Sort{Field: "submittedAt", Desc: true}
+	encode := func(c Cursor) string { return c.Encode() }
+	tests := []struct {
+		name    string
+		cursor  string
+		sort    Sort
+		want    *Cursor
+		wantErr bool
+	}{
+		{
+			name:   "ascending",
+			cursor: encode(Cursor{Sort: "teamName", Value: "Alpha", ID: id}),
+			sort:   byName,
+			want:   &Cursor{Sort: "teamName", Value: "Alpha", ID: id},
+		},
+		{
+			name:   "descending",
+			cursor: encode(Cursor{Sort: "-submittedAt", Value: int64(42), ID: id}),
+			sort:   newest,
+			want:   &Cursor{Sort: "-submittedAt", Value: int64(42), ID: id},
+		},
+		{
+			name:   "null value",
+			cursor: encode(Cursor{Sort: "teamName", ID: id}),
+			sort:   byName,
+			want:   &Cursor{Sort: "teamName", ID: id},
+		},
+		{
+			name:    "other sort field",
+			cursor:  encode(Cursor{Sort: "teamName", Value: "Alpha", ID: id}),
+			sort:    Sort{Field: "institution"},
+			wantErr: true,
+		},
+		{
+			name:    "other sort direction",
+			cursor:  encode(Cursor{Sort: "teamName", Value: "Alpha", ID: id}),
+			sort:    Sort{Field: "teamName", Desc: true},
+			wantErr: true,
+		},
+		{
+			name:    "missing ID",
+			cursor:  encode(Cursor{Sort: "teamName", Value: "Alpha"}),
+			sort:    byName,
+			wantErr: true,
+		},
+		{
+			name:    "not base64",
+			cursor:  "not a cursor!",
+			sort:    byName,
+			wantErr: true,
+		},
+		{
+			name:    "not BSON",
+			cursor:  base64.RawURLEncoding.EncodeToString([]byte("garbage")),
+			sort:    byName,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.cursor, tt.sort)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Errorf("DecodeCursor error = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeCursor: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeCursor = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorAfter(t *testing.T) {
+	id := primitive.NewObjectID()
+	tests := []struct {
+		name   string
+		cursor Cursor
+		sort   Sort
+		want   bson.M
+	}{
+		{
+			name:   "ascending",
+			cursor: Cursor{Value: "Alpha", ID: id},
+			sort:   Sort{Field: "teamName"},
+			want: bson.M{"$or": bson.A{
+				bson.M{"teamName": bson.M{"$gt": "Alpha"}},
+				bson.M{"teamName": "Alpha", "_id": bson.M{"$gt": id}},
+			}},
+		},
+		{
+			name:   "descending includes missing values",
+			cursor: Cursor{Value: "Alpha", ID: id},
+			sort:   Sort{Field: "teamName", Desc: true},
+			want: bson.M{"$or": bson.A{
+				bson.M{"$or": bson.A{
+					bson.M{"teamName": bson.M{"$lt": "Alpha"}},
+					bson.M{"teamName": nil},
+				}},
+				bson.M{"teamName": "Alpha", "_id": bson.M{"$lt": id}},
+			}},
+		},
+		{
+			name:   "ascending from null continues with every value",
+			cursor: Cursor{ID: id},
+			sort:   Sort{Field: "teamName"},
+			want: bson.M{"$or": bson.A{
+				bson.M{"teamName": bson.M{"$ne": nil}},
+				bson.M{"teamName": nil, "_id": bson.M{"$gt": id}},
+			}},
+		},
+		{
+			name:   "descending from null only has nulls left",
+			cursor: Cursor{ID: id},
+			sort:   Sort{Field: "teamName", Desc: true},
+			want:   bson.M{"teamName": nil, "_id": bson.M{"$lt": id}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cursor.after(tt.sort); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("after = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// users returns n users named user1, user2, ... in ascending order
+func users(t testing.TB, n int) []bson.D {
+	docs := make([]bson.D, n)
+	for i := range docs {
+		docs[i] = doc(t, User{ID: primitive.NewObjectID(), Username: fmt.Sprintf("user%d", i+1), Role: "admin"})
+	}
+	return docs
+}
+
+func TestFindPage(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	byName := Sort{Field: "username"}
+
+	mt.Run("first page of several", func(mt *mtest.T) {
+		docs := users(mt, 3)
+		mt.AddMockResponses(counted("users", 7), found("users", docs...))
+
+		page, err := newTestDB(mt).ListUsers(context.Background(), PageQuery{Limit: 2, Sort: byName})
+		if err != nil {
+			mt.Fatalf("ListUsers: %v", err)
+		}
+		if page.Total != 7 || len(page.Items) != 2 || !page.HasMore {
+			mt.Fatalf("page = %d items of %d, more %v; want 2 of 7 with more", len(page.Items), page.Total, page.HasMore)
+		}
+		next, err := DecodeCursor(page.NextCursor, byName)
+		if err != nil {
+			mt.Fatalf("next cursor: %v", err)
+		}
+		if next.Value != "user2" || next.ID != page.Items[1].ID {
+			mt.Errorf("next cursor = %+v, want after user2", next)
+		}
+
+		count := nextCommand(mt, "aggregate")
+		match := count.Lookup("pipeline").Array().Index(0).Value().Document().Lookup("$match").Document()
+		if elems, _ := match.Elements(); len(elems) != 1 || match.Lookup("deletedAt").Type != bson.TypeNull {
+			mt.Errorf("counted %v, want every user outside the trash", match)
+		}
+		find := nextCommand(mt, "find")
+		if got := find.Lookup("limit").Int64(); got != 3 {
+			mt.Errorf("limit = %d, want one extra to detect more items", got)
+		}
+		if _, err := find.LookupErr("skip"); err == nil {
+			mt.Error("first page skips items")
+		}
+		sort := find.Lookup("sort").Document()
+		if keys, _ := sort.Elements(); len(keys) != 2 || keys[0].Key() != "username" || keys[1].Key() != "_id" {
+			mt.Errorf("sort = %v, want username then _id", sort)
+		}
+	})
+
+	mt.Run("numbered page", func(mt *mtest.T) {
+		mt.AddMockResponses(counted("users", 7), found("users", users(mt, 1)...))
+
+		page, err := newTestDB(mt).ListUsers(context.Background(), PageQuery{Limit: 2, Sort: byName, Offset: 6})
+		if err != nil {
+			mt.Fatalf("ListUsers: %v", err)
+		}
+		if page.HasMore || page.NextCursor != "" || len(page.Items) != 1 {
+			mt.Errorf("last page = %d items, more %v, next %q", len(page.Items), page.HasMore, page.NextCursor)
+		}
+		nextCommand(mt, "aggregate")
+		if got := nextCommand(mt, "find").Lookup("skip").Int64(); got != 6 {
+			mt.Errorf("skip = %d, want 6", got)
+		}
+	})
+
+	mt.Run("page after a cursor keeps the total of the whole list", func(mt *mtest.T) {
+		mt.AddMockResponses(counted("users", 7), found("users", users(mt, 2)...))
+
+		after := &Cursor{Sort: "-username", Value: "user5", ID: primitive.NewObjectID()}
+		q := PageQuery{Limit: 2, Sort: Sort{Field: "username", Desc: true}, Cursor: after, Offset: 4}
+		page, err := newTestDB(mt).ListUsers(context.Background(), q)
+		if err != nil {
+			mt.Fatalf("ListUsers: %v", err)
+		}
+		if page.Total != 7 {
+			mt.Errorf("total = %d, want 7", page.Total)
+		}
+		nextCommand(mt, "aggregate")
+		find := nextCommand(mt, "find")
+		if _, err := find.LookupErr("filter", "$and"); err != nil {
+			mt.Errorf("filter = %v, want the list filter and the cursor position", find.Lookup("filter"))
+		}
+		if _, err := find.LookupErr("skip"); err == nil {
+			mt.Error("a cursor page also skips by offset")
+		}
+		if got := find.Lookup("sort", "username").Int32(); got != -1 {
+			mt.Errorf("sort direction = %d, want -1", got)
+		}
+	})
+}
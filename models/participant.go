@@ -0,0 +1,147 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MagicLink is a one-time login token sent to a team leader.
+// Only the SHA-256 hash of the token is stored.
+type MagicLink struct {
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	TokenHash          string             `bson:"tokenHash" json:"-"`
+	Email              string             `bson:"email" json:"email"`
+	TeamRegistrationID primitive.ObjectID `bson:"teamRegistrationId" json:"teamRegistrationId"`
+	ExpiresAt          time.Time          `bson:"expiresAt" json:"expiresAt"`
+	UsedAt             *time.Time         `bson:"usedAt,omitempty" json:"usedAt,omitempty"`
+	CreatedAt          time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// ChangeRequestStatus enum type
+type ChangeRequestStatus string
+
+const (
+	ChangeRequestPending  ChangeRequestStatus = "pending"
+	ChangeRequestApproved ChangeRequestStatus = "approved"
+	ChangeRequestRejected ChangeRequestStatus = "rejected"
+)
+
+// TeamChanges holds the registration fields a team leader may change themselves.
+// Nil/empty fields are left untouched.
+type TeamChanges struct {
+	LeaderMobile      *string      `bson:"leaderMobile,omitempty" json:"leaderMobile,omitempty"`
+	Members           []TeamMember `bson:"members,omitempty" json:"members,omitempty"`
+	MentorName        *string      `bson:"mentorName,omitempty" json:"mentorName,omitempty"`
+	MentorEmail       *string      `bson:"mentorEmail,omitempty" json:"mentorEmail,omitempty"`
+	MentorMobile      *string      `bson:"mentorMobile,omitempty" json:"mentorMobile,omitempty"`
+	MentorInstitution *string      `bson:"mentorInstitution,omitempty" json:"mentorInstitution,omitempty"`
+	MentorDesignation *string      `bson:"mentorDesignation,omitempty" json:"mentorDesignation,omitempty"`
+	TopicName         *string      `bson:"topicName,omitempty" json:"topicName,omitempty"`
+	TopicDescription  *string      `bson:"topicDescription,omitempty" json:"topicDescription,omitempty"`
+	InstituteNOC      *DriveFile   `bson:"instituteNOC,omitempty" json:"instituteNOC,omitempty"`
+	IDCardsPDF        *DriveFile   `bson:"idCardsPDF,omitempty" json:"idCardsPDF,omitempty"`
+	PresentationPPT   *DriveFile   `bson:"presentationPPT,omitempty" json:"presentationPPT,omitempty"`
+}
+
+// UpdateData converts the changes into a $set document for UpdateTeamRegistration
+func (tc *TeamChanges) UpdateData() (bson.M, error) {
+	data, err := bson.Marshal(tc)
+	if err != nil {
+		return nil, err
+	}
+	updateData := bson.M{}
+	if err := bson.Unmarshal(data, &updateData); err != nil {
+		return nil, err
+	}
+	return updateData, nil
+}
+
+// ChangeRequest is a participant-submitted edit awaiting admin review
+type ChangeRequest struct {
+	ID                 primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID            primitive.ObjectID  `bson:"eventId" json:"eventId"`
+	TeamRegistrationID primitive.ObjectID  `bson:"teamRegistrationId" json:"teamRegistrationId"`
+	RegistrationNumber string              `bson:"registrationNumber" json:"registrationNumber"`
+	TeamName           string              `bson:"teamName" json:"teamName"`
+	RequestedBy        string              `bson:"requestedBy" json:"requestedBy"`
+	Changes            TeamChanges         `bson:"changes" json:"changes"`
+	Status             ChangeRequestStatus `bson:"status" json:"status"`
+	ReviewedBy         string              `bson:"reviewedBy,omitempty" json:"reviewedBy,omitempty"`
+	ReviewReason       string              `bson:"reviewReason,omitempty" json:"reviewReason,omitempty"`
+	ReviewedAt         *time.Time          `bson:"reviewedAt,omitempty" json:"reviewedAt,omitempty"`
+	CreatedAt          time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt          time.Time           `bson:"updatedAt" json:"updatedAt"`
+
+	// TeamVersion is the version of the registration the changes were requested against
+	TeamVersion int64 `bson:"teamVersion" json:"teamVersion"`
+	// Base holds the requested fields as the registration had them at TeamVersion.
+	// Approving applies the changes on top of later writes as long as none of these
+	// fields changed; requests without a base need the registration at TeamVersion.
+	Base bson.Raw `bson:"base,omitempty" json:"-"`
+}
+
+// NewChangeRequest creates a pending change request for a team
+func NewChangeRequest(team *TeamRegistration, requestedBy string, changes TeamChanges) *ChangeRequest {
+	now := time.Now()
+	// Without a base, approving falls back to requiring the registration at TeamVersion
+	base, _ := changes.fieldsOf(team)
+	return &ChangeRequest{
+		EventID:            team.EventID,
+		TeamRegistrationID: team.ID,
+		RegistrationNumber: team.RegistrationNumber,
+		TeamName:           team.TeamName,
+		RequestedBy:        requestedBy,
+		Changes:            changes,
+		TeamVersion:        team.Version,
+		Base:               base,
+		Status:             ChangeRequestPending,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+}
+
+// fieldsOf returns the fields the changes touch as team has them
+func (tc *TeamChanges) fieldsOf(team *TeamRegistration) (bson.Raw, error) {
+	updateData, err := tc.UpdateData()
+	if err != nil {
+		return nil, err
+	}
+	data, err := bson.Marshal(team)
+	if err != nil {
+		return nil, err
+	}
+	doc := bson.Raw(data)
+	fields := bson.D{}
+	for field := range updateData {
+		if value, err := doc.LookupErr(field); err == nil {
+			fields = append(fields, bson.E{Key: field, Value: value})
+		}
+	}
+	return bson.Marshal(fields)
+}
+
+// ConflictingFields lists the requested fields, sorted by name, that team has changed
+// since the request was made
+func (cr *ChangeRequest) ConflictingFields(team *TeamRegistration) ([]string, error) {
+	current, err := cr.Changes.fieldsOf(team)
+	if err != nil {
+		return nil, err
+	}
+	updateData, err := cr.Changes.UpdateData()
+	if err != nil {
+		return nil, err
+	}
+	conflicts := make([]string, 0)
+	for field := range updateData {
+		before, beforeErr := cr.Base.LookupErr(field)
+		now, nowErr := current.LookupErr(field)
+		if (beforeErr == nil) != (nowErr == nil) || beforeErr == nil && !before.Equal(now) {
+			conflicts = append(conflicts, field)
+		}
+	}
+	slices.Sort(conflicts)
+	return conflicts, nil
+}
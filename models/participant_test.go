@@ -0,0 +1,179 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// requestedTeam is a registration at version 2 and a pending request to change its
+// mentor name
+func requestedTeam() (*TeamRegistration, *ChangeRequest) {
+	team := NewTeamRegistration()
+	team.ID = primitive.NewObjectID()
+	team.EventID = primitive.NewObjectID()
+	team.TeamName = "Rockets"
+	team.MentorName = "Dr. Rao"
+	team.TopicName = "Clean air"
+	team.Version = 2
+	mentor := "Dr. Iyer"
+	cr := NewChangeRequest(team, "leader@example.org", TeamChanges{MentorName: &mentor})
+	cr.ID = primitive.NewObjectID()
+	return team, cr
+}
+
+func TestConflictingFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(team *TeamRegistration)
+		want   []string
+	}{
+		{
+			name:   "unchanged registration",
+			change: func(team *TeamRegistration) {},
+			want:   []string{},
+		},
+		{
+			name:   "other fields changed",
+			change: func(team *TeamRegistration) { team.TopicName = "Water quality"; team.Version = 3 },
+			want:   []string{},
+		},
+		{
+			name:   "requested field changed",
+			change: func(team *TeamRegistration) { team.MentorName = "Dr. Shah"; team.Version = 3 },
+			want:   []string{"mentorName"},
+		},
+		{
+			name:   "requested field changed back",
+			change: func(team *TeamRegistration) { team.MentorName = "Dr. Rao"; team.Version = 4 },
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			team, cr := requestedTeam()
+			tt.change(team)
+
+			got, err := cr.ConflictingFields(team)
+			if err != nil {
+				t.Fatalf("ConflictingFields: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ConflictingFields = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewChangeRequestBase(t *testing.T) {
+	team, cr := requestedTeam()
+	if cr.TeamVersion != team.Version {
+		t.Errorf("team version = %d, want %d", cr.TeamVersion, team.Version)
+	}
+	elems, err := cr.Base.Elements()
+	if err != nil {
+		t.Fatalf("base: %v", err)
+	}
+	if len(elems) != 1 || elems[0].Key() != "mentorName" || elems[0].Value().StringValue() != "Dr. Rao" {
+		t.Errorf("base = %v, want only the current mentor name", cr.Base)
+	}
+}
+
+func TestReviewChangeRequestApprove(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	approved := func(cr *ChangeRequest) *ChangeRequest {
+		reviewed := *cr
+		reviewed.Status = ChangeRequestApproved
+		reviewed.ReviewedBy = "admin"
+		return &reviewed
+	}
+
+	mt.Run("rebases onto an unrelated write", func(mt *mtest.T) {
+		team, cr := requestedTeam()
+		team.TopicName = "Water quality"
+		team.Version = 3
+		updated := *team
+		updated.MentorName = "Dr. Iyer"
+		updated.Version = 4
+		mt.AddMockResponses(
+			modified(mt, approved(cr)),
+			found("teamregistrations", doc(mt, team)),
+			modified(mt, &updated),
+		)
+
+		got, err := newTestDB(mt).ReviewChangeRequest(context.Background(), cr.ID.Hex(), true, "", "admin")
+		if err != nil {
+			mt.Fatalf("ReviewChangeRequest: %v", err)
+		}
+		if got.Status != ChangeRequestApproved {
+			mt.Errorf("status = %s, want approved", got.Status)
+		}
+
+		nextCommand(mt, "findAndModify")
+		nextCommand(mt, "find")
+		write := nextCommand(mt, "findAndModify")
+		if v := write.Lookup("query", "version").AsInt64(); v != 3 {
+			mt.Errorf("applied at version %d, want the current version 3", v)
+		}
+		set := write.Lookup("update", "$set").Document()
+		if set.Lookup("mentorName").StringValue() != "Dr. Iyer" {
+			mt.Errorf("set = %v, want the requested mentor name", set)
+		}
+		if _, err := set.LookupErr("topicName"); err == nil {
+			mt.Errorf("set = %v, overwrites the unrelated write", set)
+		}
+	})
+
+	mt.Run("refuses a requested field changed since", func(mt *mtest.T) {
+		team, cr := requestedTeam()
+		team.MentorName = "Dr. Shah"
+		team.Version = 3
+		mt.AddMockResponses(
+			modified(mt, approved(cr)),
+			found("teamregistrations", doc(mt, team)),
+			updated(1),
+		)
+
+		_, err := newTestDB(mt).ReviewChangeRequest(context.Background(), cr.ID.Hex(), true, "", "admin")
+		var conflict *Error
+		if !errors.As(err, &conflict) || !errors.Is(conflict, ErrChangeRequestConflict) {
+			mt.Fatalf("err = %v, want %v", err, ErrChangeRequestConflict)
+		}
+		if fields, _ := conflict.Extensions["fields"].([]string); !slices.Equal(fields, []string{"mentorName"}) {
+			mt.Errorf("fields = %v, want mentorName", conflict.Extensions["fields"])
+		}
+
+		nextCommand(mt, "findAndModify")
+		nextCommand(mt, "find")
+		revert := nextCommand(mt, "update").Lookup("updates").Array().Index(0).Value().Document()
+		if got := revert.Lookup("u", "$set", "status").StringValue(); got != string(ChangeRequestPending) {
+			mt.Errorf("request returned to %s, want pending", got)
+		}
+	})
+
+	mt.Run("requests without a base need the requested version", func(mt *mtest.T) {
+		team, cr := requestedTeam()
+		cr.Base = nil
+		mt.AddMockResponses(
+			modified(mt, approved(cr)),
+			modified(mt, nil),
+			found("teamregistrations", doc(mt, team)),
+			updated(1),
+		)
+
+		_, err := newTestDB(mt).ReviewChangeRequest(context.Background(), cr.ID.Hex(), true, "", "admin")
+		if !errors.Is(err, ErrVersionConflict) {
+			mt.Fatalf("err = %v, want %v", err, ErrVersionConflict)
+		}
+		nextCommand(mt, "findAndModify")
+		write := nextCommand(mt, "findAndModify")
+		if v := write.Lookup("query", "version").AsInt64(); v != 2 {
+			mt.Errorf("applied at version %d, want the requested version 2", v)
+		}
+	})
+}
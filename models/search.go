@@ -0,0 +1,182 @@
+package models
+
+import (
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GenderMix describes the genders of a team's leader and members: the single gender of
+// the team, or mixed
+type GenderMix string
+
+const (
+	GenderMixMale   GenderMix = "male"
+	GenderMixFemale GenderMix = "female"
+	GenderMixOther  GenderMix = "other"
+	GenderMixMixed  GenderMix = "mixed"
+)
+
+// TeamSearch combines the filters of a team search; zero values don't filter
+type TeamSearch struct {
+	// Text is matched against the team, topic, leader and member names and the topic description
+	Text        string
+	Status      RegistrationStatus
+	Track       Track
+	Program     Program
+	Country     string
+	State       string
+	Institution string
+	GenderMix   GenderMix
+	// MinSize and MaxSize bound the number of people in the team, leader included
+	MinSize int
+	MaxSize int
+	// HasVideo and Allocated filter on whether the team submitted a video or has a judge
+	HasVideo  *bool
+	Allocated *bool
+	JudgeID   primitive.ObjectID
+}
+
+// TeamFacets counts the teams matching a search by the value of each filter
+type TeamFacets struct {
+	Status    []FacetCount `bson:"status" json:"status"`
+	Track     []FacetCount `bson:"track" json:"track"`
+	Program   []FacetCount `bson:"program" json:"program"`
+	Country   []FacetCount `bson:"country" json:"country"`
+	State     []FacetCount `bson:"state" json:"state"`
+	GenderMix []FacetCount `bson:"genderMix" json:"genderMix"`
+	TeamSize  []FacetCount `bson:"teamSize" json:"teamSize"`
+	HasVideo  []FacetCount `bson:"hasVideo" json:"hasVideo"`
+	Allocated []FacetCount `bson:"allocated" json:"allocated"`
+	Judge     []FacetCount `bson:"judge" json:"judge"`
+}
+
+// FacetCount is the number of teams with one value of a filter
+type FacetCount struct {
+	Value interface{} `bson:"_id" json:"value"`
+	Count int64       `bson:"count" json:"count"`
+}
+
+// videoTeams identifies the teams with a submitted video by the registration numbers,
+// team IDs and team names found in the videos collection
+type videoTeams struct {
+	registrations, teamIDs, teamNames bson.A
+}
+
+// filter matches the teams with a video in a query
+func (v *videoTeams) filter() bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"registrationNumber": bson.M{"$in": v.registrations}},
+		bson.M{"teamId": bson.M{"$in": v.teamIDs}},
+		bson.M{"teamName": bson.M{"$in": v.teamNames}},
+	}}
+}
+
+// expr is true for the teams with a video in an aggregation expression
+func (v *videoTeams) expr() bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"$in": bson.A{"$registrationNumber", v.registrations}},
+		bson.M{"$in": bson.A{"$teamId", v.teamIDs}},
+		bson.M{"$in": bson.A{"$teamName", v.teamNames}},
+	}}
+}
+
+// Aggregation expressions of the derived team properties
+var (
+	teamSizeExpr  = bson.M{"$add": bson.A{1, bson.M{"$size": bson.M{"$ifNull": bson.A{"$members", bson.A{}}}}}}
+	gendersExpr   = bson.M{"$setUnion": bson.A{bson.A{"$leaderGender"}, bson.M{"$ifNull": bson.A{"$members.gender", bson.A{}}}}}
+	genderMixExpr = bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{bson.M{"$size": gendersExpr}, 1}},
+		string(GenderMixMixed),
+		bson.M{"$arrayElemAt": bson.A{gendersExpr, 0}},
+	}}
+	allocatedFilter = bson.M{"allocatedJudgeId": bson.M{"$type": "objectId"}}
+)
+
+// filter returns the query of the search in an event
+func (s *TeamSearch) filter(eventID primitive.ObjectID, videos *videoTeams) bson.M {
+	filter := bson.M{"eventId": eventID}
+	if s.Text != "" {
+		filter["$text"] = bson.M{"$search": s.Text}
+	}
+	for field, value := range map[string]string{
+		"registrationStatus": string(s.Status),
+		"track":              string(s.Track),
+		"program":            string(s.Program),
+		"country":            s.Country,
+		"state":              s.State,
+	} {
+		if value != "" {
+			filter[field] = value
+		}
+	}
+	if s.Institution != "" {
+		// User input is matched literally; it is never a pattern
+		filter["institution"] = bson.M{"$regex": regexp.QuoteMeta(s.Institution), "$options": "i"}
+	}
+	if !s.JudgeID.IsZero() {
+		filter["allocatedJudgeId"] = s.JudgeID
+	}
+
+	var and bson.A
+	var exprs bson.A
+	if s.GenderMix != "" {
+		exprs = append(exprs, bson.M{"$eq": bson.A{genderMixExpr, string(s.GenderMix)}})
+	}
+	if s.MinSize > 0 {
+		exprs = append(exprs, bson.M{"$gte": bson.A{teamSizeExpr, s.MinSize}})
+	}
+	if s.MaxSize > 0 {
+		exprs = append(exprs, bson.M{"$lte": bson.A{teamSizeExpr, s.MaxSize}})
+	}
+	if len(exprs) > 0 {
+		and = append(and, bson.M{"$expr": bson.M{"$and": exprs}})
+	}
+	if s.HasVideo != nil {
+		if *s.HasVideo {
+			and = append(and, videos.filter())
+		} else {
+			and = append(and, bson.M{"$nor": bson.A{videos.filter()}})
+		}
+	}
+	if s.Allocated != nil {
+		if *s.Allocated {
+			and = append(and, allocatedFilter)
+		} else {
+			and = append(and, bson.M{"$nor": bson.A{allocatedFilter}})
+		}
+	}
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+	return filter
+}
+
+// countBy groups documents by expr and counts them, most frequent first
+func countBy(expr interface{}) bson.A {
+	return bson.A{
+		bson.M{"$group": bson.M{"_id": expr, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+}
+
+// facetPipeline counts the teams matching filter by every facet
+func facetPipeline(filter bson.M, videos *videoTeams) bson.A {
+	return bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$facet": bson.M{
+			"status":    countBy("$registrationStatus"),
+			"track":     countBy("$track"),
+			"program":   countBy("$program"),
+			"country":   countBy("$country"),
+			"state":     countBy("$state"),
+			"genderMix": countBy(genderMixExpr),
+			"teamSize":  countBy(teamSizeExpr),
+			"hasVideo":  countBy(videos.expr()),
+			"allocated": countBy(bson.M{"$eq": bson.A{bson.M{"$type": "$allocatedJudgeId"}, "objectId"}}),
+			"judge": append(bson.A{bson.M{"$match": allocatedFilter}},
+				countBy("$allocatedJudgeId")...),
+		}},
+	}
+}
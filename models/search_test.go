@@ -0,0 +1,146 @@
+package models
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestTeamSearchFilter(t *testing.T) {
+	eventID := primitive.NewObjectID()
+	judgeID := primitive.NewObjectID()
+	videos := &videoTeams{registrations: bson.A{"IGC-0001"}, teamIDs: bson.A{}, teamNames: bson.A{"Rocket"}}
+	yes, no := true, false
+
+	tests := []struct {
+		name   string
+		search TeamSearch
+		want   bson.M
+	}{
+		{"no filters", TeamSearch{}, bson.M{"eventId": eventID}},
+		{
+			name:   "text and exact fields",
+			search: TeamSearch{Text: "solar drone", Status: StatusApproved, Track: "AI", Country: "India", JudgeID: judgeID},
+			want: bson.M{
+				"eventId":            eventID,
+				"$text":              bson.M{"$search": "solar drone"},
+				"registrationStatus": "approved",
+				"track":              "AI",
+				"country":            "India",
+				"allocatedJudgeId":   judgeID,
+			},
+		},
+		{
+			name:   "institution is matched literally",
+			search: TeamSearch{Institution: "P.C. (COE)"},
+			want:   bson.M{"eventId": eventID, "institution": bson.M{"$regex": `P\.C\. \(COE\)`, "$options": "i"}},
+		},
+		{
+			name:   "derived properties",
+			search: TeamSearch{GenderMix: GenderMixMixed, MinSize: 2, MaxSize: 4},
+			want: bson.M{"eventId": eventID, "$and": bson.A{
+				bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{genderMixExpr, "mixed"}},
+					bson.M{"$gte": bson.A{teamSizeExpr, 2}},
+					bson.M{"$lte": bson.A{teamSizeExpr, 4}},
+				}}},
+			}},
+		},
+		{
+			name:   "with video, not allocated",
+			search: TeamSearch{HasVideo: &yes, Allocated: &no},
+			want: bson.M{"eventId": eventID, "$and": bson.A{
+				videos.filter(),
+				bson.M{"$nor": bson.A{allocatedFilter}},
+			}},
+		},
+		{
+			name:   "without video, allocated",
+			search: TeamSearch{HasVideo: &no, Allocated: &yes},
+			want: bson.M{"eventId": eventID, "$and": bson.A{
+				bson.M{"$nor": bson.A{videos.filter()}},
+				allocatedFilter,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.search.filter(eventID, videos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFacetPipeline(t *testing.T) {
+	filter := bson.M{"eventId": primitive.NewObjectID()}
+	pipeline := facetPipeline(filter, &videoTeams{})
+
+	if len(pipeline) != 2 || !reflect.DeepEqual(pipeline[0], bson.M{"$match": filter}) {
+		t.Fatalf("pipeline = %v, want the search filter then the facets", pipeline)
+	}
+	facets := pipeline[1].(bson.M)["$facet"].(bson.M)
+	for _, name := range []string{"status", "track", "program", "country", "state", "genderMix", "teamSize", "hasVideo", "allocated", "judge"} {
+		if _, ok := facets[name]; !ok {
+			t.Errorf("facet %s is missing", name)
+		}
+	}
+	if len(facets) != 10 {
+		t.Errorf("%d facets, want 10", len(facets))
+	}
+	// Only allocated teams are counted by judge
+	if judge := facets["judge"].(bson.A); !reflect.DeepEqual(judge[0], bson.M{"$match": allocatedFilter}) {
+		t.Errorf("judge facet starts with %v, want the allocated teams", judge[0])
+	}
+}
+
+func TestSearchTeamRegistrations(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("pages the matches and counts the facets", func(mt *mtest.T) {
+		eventID := primitive.NewObjectID()
+		for range len(videoRegistrationFields) + 2 {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{}}))
+		}
+		team := TeamRegistration{ID: primitive.NewObjectID(), EventID: eventID, TeamName: "Rocket", Track: "AI"}
+		facets := bson.D{
+			{Key: "track", Value: bson.A{bson.D{{Key: "_id", Value: "AI"}, {Key: "count", Value: int64(1)}}}},
+			{Key: "status", Value: bson.A{}},
+		}
+		mt.AddMockResponses(
+			counted("teamregistrations", 1),
+			found("teamregistrations", doc(mt, team)),
+			found("teamregistrations", facets),
+		)
+
+		search := &TeamSearch{Track: "AI"}
+		page, got, err := newTestDB(mt).SearchTeamRegistrations(context.Background(), eventID, search, PageQuery{Limit: 10, Sort: Sort{Field: "teamName"}})
+		if err != nil {
+			mt.Fatalf("SearchTeamRegistrations: %v", err)
+		}
+		if page.Total != 1 || len(page.Items) != 1 || page.Items[0].ID != team.ID {
+			mt.Errorf("page = %d of %d, want Rocket", len(page.Items), page.Total)
+		}
+		if len(got.Track) != 1 || got.Track[0].Value != "AI" || got.Track[0].Count != 1 {
+			mt.Errorf("track facet = %+v, want AI: 1", got.Track)
+		}
+
+		for range len(videoRegistrationFields) + 2 {
+			nextCommand(mt, "distinct")
+		}
+		nextCommand(mt, "aggregate")
+		find := nextCommand(mt, "find")
+		if got := find.Lookup("filter", "track").StringValue(); got != "AI" {
+			mt.Errorf("find filter track = %q, want AI", got)
+		}
+		facet := nextCommand(mt, "aggregate")
+		match := facet.Lookup("pipeline").Array().Index(0).Value().Document().Lookup("$match")
+		if match.Document().Lookup("track").StringValue() != "AI" || match.Document().Lookup("eventId").ObjectID() != eventID {
+			mt.Errorf("facets counted over %v, want the search filter", match)
+		}
+	})
+}
@@ -15,7 +15,7 @@ const (
 	GenderOther  Gender = "other"
 )
 
-// Program enum type for validation
+// Program is the name of a catalogue program; the constants seed the catalogue
 type Program string
 
 const (
@@ -33,7 +33,7 @@ const (
 	ProgramOther      Program = "Other"
 )
 
-// Track enum type for validation
+// Track is the name of a catalogue track; the constants seed the catalogue
 type Track string
 
 const (
@@ -62,24 +62,37 @@ const (
 	StatusPending  RegistrationStatus = "pending"
 	StatusApproved RegistrationStatus = "approved"
 	StatusRejected RegistrationStatus = "rejected"
+	// StatusWaitlisted marks approved teams held back because their track is at capacity
+	StatusWaitlisted RegistrationStatus = "waitlisted"
+)
+
+// Stage describes how far a team progressed in the event
+type Stage string
+
+const (
+	StageRegistered     Stage = "registered"
+	StageShortlisted    Stage = "shortlisted"
+	StageVideoSubmitted Stage = "video_submitted"
+	StageWinner         Stage = "winner"
 )
 
 // TeamMember represents a team member (excluding leader)
 type TeamMember struct {
-	FullName string `bson:"fullName" json:"fullName" validate:"required,max=100"`
-	Gender   Gender `bson:"gender" json:"gender" validate:"required,oneof=male female other"`
-	MobileNo string `bson:"mobileNo" json:"mobileNo" validate:"required,e164"`
-	Email    string `bson:"email" json:"email" validate:"required,email,lowercase"`
+	FullName string `bson:"fullName" json:"fullName" binding:"required,max=100"`
+	Gender   Gender `bson:"gender" json:"gender" binding:"required,oneof=male female other"`
+	MobileNo string `bson:"mobileNo" json:"mobileNo" binding:"required,e164"`
+	Email    string `bson:"email" json:"email" binding:"required,email,lowercase"`
 }
 
 // DriveFile represents a Cloudinary file URL
 type DriveFile struct {
-	FileURL string `bson:"fileUrl" json:"fileUrl" validate:"required,url"`
+	FileURL string `bson:"fileUrl" json:"fileUrl" binding:"required,url"`
 }
 
 // TeamRegistration represents the complete team registration
 type TeamRegistration struct {
 	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID           primitive.ObjectID `bson:"eventId" json:"eventId"`
 	TeamName          string             `bson:"teamName" json:"teamName" validate:"required,max=100"`
 	LeaderName        string             `bson:"leaderName" json:"leaderName" validate:"required,max=100"`
 	LeaderEmail       string             `bson:"leaderEmail" json:"leaderEmail" validate:"required,email,lowercase"`
@@ -106,19 +119,30 @@ type TeamRegistration struct {
 	RegistrationStatus RegistrationStatus `bson:"registrationStatus" json:"registrationStatus"`
 	RegistrationNumber string             `bson:"registrationNumber,omitempty" json:"registrationNumber,omitempty"`
 	TeamID             string             `bson:"teamId,omitempty" json:"teamId,omitempty"`
+	// Version counts the writes to the registration; it is the ETag of the registration
+	Version int64 `bson:"version" json:"version"`
 
 	// Timestamps
 	SubmittedAt time.Time  `bson:"submittedAt" json:"submittedAt"`
 	ApprovedAt  *time.Time `bson:"approvedAt,omitempty" json:"approvedAt,omitempty"`
 	RejectedAt  *time.Time `bson:"rejectedAt,omitempty" json:"rejectedAt,omitempty"`
-	CreatedAt   time.Time  `bson:"createdAt" json:"createdAt"`
-	UpdatedAt   time.Time  `bson:"updatedAt" json:"updatedAt"`
+	// WaitlistedAt orders the waitlist of a track (first in, first promoted)
+	WaitlistedAt *time.Time `bson:"waitlistedAt,omitempty" json:"waitlistedAt,omitempty"`
+	CreatedAt    time.Time  `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time  `bson:"updatedAt" json:"updatedAt"`
+	// DeletedAt is set while the registration is in the trash
+	DeletedAt *time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy string     `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
 
 	// Action tracking
 	RejectionReason  string             `bson:"rejectionReason,omitempty" json:"rejectionReason,omitempty" validate:"max=500"`
 	ActionedBy       string             `bson:"actionedBy,omitempty" json:"actionedBy,omitempty" validate:"max=100"`
 	AllocatedJudgeID primitive.ObjectID `bson:"allocatedJudgeId,omitempty" json:"allocatedJudgeId,omitempty"`
 
+	// Duplicates lists participants also registered in other teams, recorded when the
+	// event's duplicate policy is flag
+	Duplicates []DuplicateMatch `bson:"duplicates,omitempty" json:"duplicates,omitempty"`
+
 	// Derived field (not stored): Video submission link if any
 	VideoLink string `bson:"-" json:"videoLink"`
 }
@@ -180,7 +204,25 @@ func (tr *TeamRegistration) IsRejected() bool {
 	return tr.RegistrationStatus == StatusRejected
 }
 
+// IsWaitlisted checks if the team registration is waitlisted
+func (tr *TeamRegistration) IsWaitlisted() bool {
+	return tr.RegistrationStatus == StatusWaitlisted
+}
+
 // IsPending checks if the team registration is pending
 func (tr *TeamRegistration) IsPending() bool {
 	return tr.RegistrationStatus == StatusPending
 }
+
+// StageReached returns the furthest stage the team reached
+func (tr *TeamRegistration) StageReached(hasVideo, isWinner bool) Stage {
+	switch {
+	case isWinner:
+		return StageWinner
+	case tr.IsApproved() && hasVideo:
+		return StageVideoSubmitted
+	case tr.IsApproved():
+		return StageShortlisted
+	}
+	return StageRegistered
+}
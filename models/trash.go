@@ -0,0 +1,34 @@
+package models
+
+import (
+	"maps"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Users and team registrations are soft-deleted: deleting one moves it to the trash by
+// setting deletedAt, from where an admin can restore it until the retention job purges
+// it. Records in the trash are left out of every query except the trash listings.
+
+// TrashSortFields lists the fields the trash listings can be sorted by
+var TrashSortFields = SortFields{"deletedAt", "createdAt"}
+
+// notDeleted restricts a query to records that aren't in the trash
+func notDeleted(filter bson.M) bson.M {
+	filter = maps.Clone(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+	filter["deletedAt"] = nil
+	return filter
+}
+
+// inTrash restricts a query to records in the trash
+func inTrash(filter bson.M) bson.M {
+	filter = maps.Clone(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+	filter["deletedAt"] = bson.M{"$ne": nil}
+	return filter
+}
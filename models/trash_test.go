@@ -0,0 +1,271 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestTrashFilters(t *testing.T) {
+	filter := bson.M{"eventId": "e1"}
+
+	live := notDeleted(filter)
+	if live["deletedAt"] != nil || live["eventId"] != "e1" {
+		t.Errorf("notDeleted = %v, want the event's records without deletedAt", live)
+	}
+	if _, ok := live["deletedAt"]; !ok {
+		t.Error("notDeleted doesn't match on deletedAt")
+	}
+	trashed := inTrash(filter)
+	if ne, ok := trashed["deletedAt"].(bson.M); !ok || ne["$ne"] != nil || len(ne) != 1 {
+		t.Errorf("inTrash = %v, want deletedAt set", trashed)
+	}
+	if len(filter) != 1 {
+		t.Errorf("filters changed the query they extend: %v", filter)
+	}
+	if got := notDeleted(nil); len(got) != 1 {
+		t.Errorf("notDeleted(nil) = %v, want only the deletedAt match", got)
+	}
+}
+
+// deletedUser is a user moved to the trash by admin
+func deletedUser(username string) *User {
+	deletedAt := time.Now().Add(-time.Hour)
+	user := NewUser(username, "secret")
+	user.ID = primitive.NewObjectID()
+	user.DeletedAt = &deletedAt
+	user.DeletedBy = "admin"
+	return user
+}
+
+func TestDeleteUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("moves the user to the trash", func(mt *mtest.T) {
+		db := newTestDB(mt)
+		user := deletedUser("judge1")
+		mt.AddMockResponses(modified(mt, user))
+
+		got, err := db.DeleteUser(context.Background(), user.ID.Hex(), "admin")
+		if err != nil {
+			mt.Fatalf("DeleteUser: %v", err)
+		}
+		if got.DeletedAt == nil || got.DeletedBy != "admin" {
+			mt.Errorf("deleted user = %+v, want in the trash", got)
+		}
+
+		cmd := nextCommand(mt, "findAndModify")
+		if cmd.Lookup("query", "deletedAt").Type != bson.TypeNull {
+			mt.Errorf("query = %v, want only users outside the trash", cmd.Lookup("query"))
+		}
+		set := cmd.Lookup("update", "$set").Document()
+		if set.Lookup("deletedBy").StringValue() != "admin" || set.Lookup("deletedAt").Type != bson.TypeDateTime {
+			mt.Errorf("update = %v, want deletedAt and deletedBy set", set)
+		}
+		if mt.GetStartedEvent() != nil {
+			mt.Error("the user was removed for good")
+		}
+	})
+
+	mt.Run("already deleted", func(mt *mtest.T) {
+		db := newTestDB(mt)
+		mt.AddMockResponses(modified(mt, nil))
+
+		_, err := db.DeleteUser(context.Background(), primitive.NewObjectID().Hex(), "admin")
+		if !errors.Is(err, ErrUserNotFound) {
+			mt.Errorf("err = %v, want %v", err, ErrUserNotFound)
+		}
+	})
+}
+
+func TestRestoreUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("restores the user", func(mt *mtest.T) {
+		db := newTestDB(mt)
+		user := deletedUser("judge1")
+		restored := *user
+		restored.DeletedAt, restored.DeletedBy = nil, ""
+		mt.AddMockResponses(
+			found("users", doc(mt, user)),
+			found("users"),
+			updated(1),
+			found("users", doc(mt, restored)),
+		)
+
+		got, err := db.RestoreUser(context.Background(), user.ID.Hex())
+		if err != nil {
+			mt.Fatalf("RestoreUser: %v", err)
+		}
+		if got.DeletedAt != nil {
+			mt.Errorf("restored user = %+v, still in the trash", got)
+		}
+
+		lookup := nextCommand(mt, "find")
+		if lookup.Lookup("filter", "deletedAt", "$ne").Type != bson.TypeNull {
+			mt.Errorf("lookup = %v, want only users in the trash", lookup.Lookup("filter"))
+		}
+		taken := nextCommand(mt, "find")
+		if taken.Lookup("filter", "username").StringValue() != "judge1" || taken.Lookup("filter", "deletedAt").Type != bson.TypeNull {
+			mt.Errorf("username check = %v, want judge1 outside the trash", taken.Lookup("filter"))
+		}
+		update := nextCommand(mt, "update").Lookup("updates").Array().Index(0).Value().Document()
+		unset := update.Lookup("u", "$unset").Document()
+		if _, err := unset.LookupErr("deletedAt"); err != nil {
+			mt.Errorf("update = %v, want deletedAt unset", update)
+		}
+		if _, err := unset.LookupErr("deletedBy"); err != nil {
+			mt.Errorf("update = %v, want deletedBy unset", update)
+		}
+	})
+
+	mt.Run("username taken since", func(mt *mtest.T) {
+		db := newTestDB(mt)
+		user := deletedUser("judge1")
+		other := NewUser("judge1", "other")
+		other.ID = primitive.NewObjectID()
+		mt.AddMockResponses(
+			found("users", doc(mt, user)),
+			found("users", doc(mt, other)),
+		)
+
+		_, err := db.RestoreUser(context.Background(), user.ID.Hex())
+		var conflict *Error
+		if !errors.As(err, &conflict) || conflict.Kind != ErrConflict || conflict.Code != CodeUserExists {
+			mt.Fatalf("err = %v, want a %s conflict", err, CodeUserExists)
+		}
+		nextCommand(mt, "find")
+		nextCommand(mt, "find")
+		if event := mt.GetStartedEvent(); event != nil {
+			mt.Errorf("sent %s after the conflict", event.CommandName)
+		}
+	})
+
+	mt.Run("not in the trash", func(mt *mtest.T) {
+		db := newTestDB(mt)
+		mt.AddMockResponses(found("users"))
+
+		_, err := db.RestoreUser(context.Background(), primitive.NewObjectID().Hex())
+		if !errors.Is(err, ErrUserNotFound) {
+			mt.Errorf("err = %v, want %v", err, ErrUserNotFound)
+		}
+	})
+}
+
+func TestRestoreTeamRegistration(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	eventID := primitive.NewObjectID()
+	deletedTeam := func() *TeamRegistration {
+		deletedAt := time.Now().Add(-time.Hour)
+		team := NewTeamRegistration()
+		team.ID = primitive.NewObjectID()
+		team.EventID = eventID
+		team.TeamName = "Rockets"
+		team.RegistrationNumber = "IGC-0007"
+		team.DeletedAt = &deletedAt
+		team.DeletedBy = "admin"
+		return team
+	}
+
+	mt.Run("keeps the registration number", func(mt *mtest.T) {
+		db := newTestDB(mt)
+		team := deletedTeam()
+		restored := *team
+		restored.DeletedAt, restored.DeletedBy = nil, ""
+		mt.AddMockResponses(
+			found("teamregistrations", doc(mt, team)),
+			found("teamregistrations"),
+			updated(1),
+			found("teamregistrations", doc(mt, restored)),
+		)
+
+		got, err := db.RestoreTeamRegistration(context.Background(), eventID, team.ID.Hex())
+		if err != nil {
+			mt.Fatalf("RestoreTeamRegistration: %v", err)
+		}
+		if got.DeletedAt != nil || got.RegistrationNumber != "IGC-0007" {
+			mt.Errorf("restored team = %+v, want IGC-0007 out of the trash", got)
+		}
+
+		lookup := nextCommand(mt, "find").Lookup("filter").Document()
+		if lookup.Lookup("eventId").ObjectID() != eventID || lookup.Lookup("deletedAt", "$ne").Type != bson.TypeNull {
+			mt.Errorf("lookup = %v, want the event's trash", lookup)
+		}
+		taken := nextCommand(mt, "find").Lookup("filter").Document()
+		if taken.Lookup("teamName").StringValue() != "Rockets" || taken.Lookup("eventId").ObjectID() != eventID {
+			mt.Errorf("name check = %v, want Rockets in the event", taken)
+		}
+		nextCommand(mt, "update")
+	})
+
+	mt.Run("team name taken since", func(mt *mtest.T) {
+		db := newTestDB(mt)
+		team := deletedTeam()
+		other := NewTeamRegistration()
+		other.ID = primitive.NewObjectID()
+		other.EventID = eventID
+		other.TeamName = "Rockets"
+		mt.AddMockResponses(
+			found("teamregistrations", doc(mt, team)),
+			found("teamregistrations", doc(mt, other)),
+		)
+
+		_, err := db.RestoreTeamRegistration(context.Background(), eventID, team.ID.Hex())
+		var conflict *Error
+		if !errors.As(err, &conflict) || conflict.Code != CodeTeamNameExists {
+			mt.Fatalf("err = %v, want a %s conflict", err, CodeTeamNameExists)
+		}
+	})
+
+	mt.Run("team of another event", func(mt *mtest.T) {
+		db := newTestDB(mt)
+		mt.AddMockResponses(found("teamregistrations"))
+
+		_, err := db.RestoreTeamRegistration(context.Background(), eventID, primitive.NewObjectID().Hex())
+		if !errors.Is(err, ErrTeamNotFound) {
+			mt.Errorf("err = %v, want %v", err, ErrTeamNotFound)
+		}
+	})
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("removes records deleted before the cutoff", func(mt *mtest.T) {
+		db := newTestDB(mt)
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+		cutoff := time.Now().Add(-30 * 24 * time.Hour).Truncate(time.Millisecond)
+
+		report, err := db.PurgeDeleted(context.Background(), cutoff)
+		if err != nil {
+			mt.Fatalf("PurgeDeleted: %v", err)
+		}
+		if report.Teams != 3 || report.Users != 1 {
+			mt.Errorf("report = %+v, want 3 teams and 1 user", report)
+		}
+
+		for _, collection := range []string{"teamregistrations", "users"} {
+			cmd := nextCommand(mt, "delete")
+			if got := cmd.Lookup("delete").StringValue(); got != collection {
+				mt.Errorf("purged %s, want %s", got, collection)
+			}
+			del := cmd.Lookup("deletes").Array().Index(0).Value().Document()
+			lt := del.Lookup("q", "deletedAt", "$lt").Time()
+			if !lt.Equal(cutoff) {
+				mt.Errorf("%s purge cutoff = %v, want %v", collection, lt, cutoff)
+			}
+			if del.Lookup("limit").AsInt64() != 0 {
+				mt.Errorf("%s purge removes a single record", collection)
+			}
+		}
+	})
+}
@@ -13,6 +13,11 @@ type User struct {
     Role      string             `bson:"role" json:"role" validate:"required,oneof=admin judge"`
 	CreatedAt time.Time          `bson:"createdAt,omitempty" json:"createdAt,omitempty"`
 	UpdatedAt time.Time          `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+	// Version counts the writes to the user; it is the ETag of the user
+	Version   int64              `bson:"version" json:"version"`
+	// DeletedAt is set while the user is in the trash
+	DeletedAt *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy string             `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
 }
 
 // NewUser creates a new user with default values
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"maps"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Users and team registrations carry a version that every write increments. Updates
+// name the version they were based on and only apply while the record is still at it,
+// so concurrent writers can't silently overwrite each other.
+
+// atVersion restricts a query to the record at version. Records written before they
+// were versioned have no version field and are at version 0.
+func atVersion(filter bson.M, version int64) bson.M {
+	filter = maps.Clone(filter)
+	if filter == nil {
+		filter = bson.M{}
+	}
+	if version == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	} else {
+		filter["version"] = version
+	}
+	return filter
+}
+
+// versioned adds the version increment to an update of a versioned record
+func versioned(update bson.M) bson.M {
+	update = maps.Clone(update)
+	update["$inc"] = bson.M{"version": 1}
+	return update
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestAtVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  bson.M
+		version int64
+		want    bson.M
+	}{
+		{
+			name:    "unversioned records are at version 0",
+			filter:  bson.M{"_id": "a"},
+			version: 0,
+			want:    bson.M{"_id": "a", "version": bson.M{"$in": bson.A{0, nil}}},
+		},
+		{
+			name:    "exact version",
+			filter:  bson.M{"_id": "a"},
+			version: 3,
+			want:    bson.M{"_id": "a", "version": int64(3)},
+		},
+		{
+			name:    "nil filter",
+			filter:  nil,
+			version: 1,
+			want:    bson.M{"version": int64(1)},
+		},
+		{
+			name:    "replaces a version in the filter",
+			filter:  bson.M{"version": 7},
+			version: 2,
+			want:    bson.M{"version": int64(2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := maps.Clone(tt.filter)
+			got := atVersion(tt.filter, tt.version)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("atVersion = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.filter, original) {
+				t.Errorf("atVersion changed its filter to %v", tt.filter)
+			}
+		})
+	}
+}
+
+func TestVersioned(t *testing.T) {
+	tests := []struct {
+		name   string
+		update bson.M
+		want   bson.M
+	}{
+		{
+			name:   "set",
+			update: bson.M{"$set": bson.M{"role": "admin"}},
+			want:   bson.M{"$set": bson.M{"role": "admin"}, "$inc": bson.M{"version": 1}},
+		},
+		{
+			name:   "set and unset",
+			update: bson.M{"$set": bson.M{"status": "pending"}, "$unset": bson.M{"reason": ""}},
+			want:   bson.M{"$set": bson.M{"status": "pending"}, "$unset": bson.M{"reason": ""}, "$inc": bson.M{"version": 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := maps.Clone(tt.update)
+			got := versioned(tt.update)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("versioned = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.update, original) {
+				t.Errorf("versioned changed its update to %v", tt.update)
+			}
+		})
+	}
+}
+
+func TestUpdateTeamRegistrationVersion(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	team := TeamRegistration{ID: primitive.NewObjectID(), TeamName: "Rockets", Version: 2}
+
+	mt.Run("writes the version it was based on", func(mt *mtest.T) {
+		written := team
+		written.Version = 3
+		mt.AddMockResponses(modified(mt, written))
+
+		got, err := newTestDB(mt).UpdateTeamRegistration(context.Background(), team.ID.Hex(), 2, bson.M{"teamName": "Rockets"})
+		if err != nil {
+			mt.Fatalf("UpdateTeamRegistration: %v", err)
+		}
+		if got.Version != 3 {
+			mt.Errorf("version = %d, want 3", got.Version)
+		}
+		cmd := nextCommand(mt, "findAndModify")
+		if v := cmd.Lookup("query", "version").AsInt64(); v != 2 {
+			mt.Errorf("query version = %d, want 2", v)
+		}
+		if cmd.Lookup("query", "deletedAt").Type != bson.TypeNull {
+			mt.Error("the write reaches registrations in the trash")
+		}
+		if inc := cmd.Lookup("update", "$inc", "version").AsInt64(); inc != 1 {
+			mt.Errorf("version increment = %d, want 1", inc)
+		}
+	})
+
+	mt.Run("changed in the meantime", func(mt *mtest.T) {
+		mt.AddMockResponses(modified(mt, nil), found("teamregistrations", doc(mt, team)))
+
+		_, err := newTestDB(mt).UpdateTeamRegistration(context.Background(), team.ID.Hex(), 1, bson.M{"teamName": "Comets"})
+		if !errors.Is(err, ErrVersionConflict) {
+			mt.Errorf("err = %v, want %v", err, ErrVersionConflict)
+		}
+	})
+
+	mt.Run("deleted in the meantime", func(mt *mtest.T) {
+		mt.AddMockResponses(modified(mt, nil), found("teamregistrations"))
+
+		_, err := newTestDB(mt).UpdateTeamRegistration(context.Background(), team.ID.Hex(), 1, bson.M{"teamName": "Comets"})
+		if !errors.Is(err, ErrTeamNotFound) {
+			mt.Errorf("err = %v, want %v", err, ErrTeamNotFound)
+		}
+	})
+}
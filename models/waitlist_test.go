@@ -0,0 +1,233 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// eventWithCapacity is an event whose configuration limits every track to capacity teams
+func eventWithCapacity(t testing.TB, eventID primitive.ObjectID, capacity int) bson.D {
+	event := Event{ID: eventID, Slug: "igc-2026", Config: *NewEventConfig()}
+	event.Config.DefaultTrackCapacity = capacity
+	return doc(t, event)
+}
+
+// trackEntry is the catalogue entry of the tested track, leaving its capacity to the event
+func trackEntry(t testing.TB) bson.D {
+	return doc(t, CatalogEntry{ID: primitive.NewObjectID(), Kind: CatalogTrack, Slug: "air-quality", Name: string(TrackAirQuality), Active: true})
+}
+
+func TestApproveTeamRegistration(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("approves while the track has room", func(mt *mtest.T) {
+		eventID := primitive.NewObjectID()
+		team := TeamRegistration{ID: primitive.NewObjectID(), EventID: eventID, Track: TrackAirQuality, RegistrationStatus: StatusPending}
+		approved := team
+		approved.RegistrationStatus = StatusApproved
+		mt.AddMockResponses(
+			found("teamregistrations", doc(mt, team)),
+			found("events", eventWithCapacity(mt, eventID, 2)),
+			found("catalog", trackEntry(mt)),
+			counted("teamregistrations", 1),
+			modified(mt, approved),
+			counted("teamregistrations", 2),
+		)
+
+		got, err := newTestDB(mt).ApproveTeamRegistration(context.Background(), team.ID.Hex(), 0, "admin")
+		if err != nil {
+			mt.Fatalf("ApproveTeamRegistration: %v", err)
+		}
+		if got.RegistrationStatus != StatusApproved {
+			mt.Errorf("status = %s, want approved", got.RegistrationStatus)
+		}
+		nextCommand(mt, "find")
+		nextCommand(mt, "find")
+		nextCommand(mt, "find")
+		count := nextCommand(mt, "aggregate")
+		match := count.Lookup("pipeline").Array().Index(0).Value().Document().Lookup("$match").Document()
+		if match.Lookup("registrationStatus").StringValue() != string(StatusApproved) || match.Lookup("track").StringValue() != string(team.Track) ||
+			match.Lookup("eventId").ObjectID() != eventID {
+			mt.Errorf("counted %v, want the approved teams of the event's track", match)
+		}
+		update := nextCommand(mt, "findAndModify")
+		set := update.Lookup("update", "$set").Document()
+		if got := set.Lookup("registrationStatus").StringValue(); got != string(StatusApproved) {
+			mt.Errorf("set status %s, want approved", got)
+		}
+	})
+
+	mt.Run("waitlists when the track is full", func(mt *mtest.T) {
+		eventID := primitive.NewObjectID()
+		team := TeamRegistration{ID: primitive.NewObjectID(), EventID: eventID, Track: TrackAirQuality, RegistrationStatus: StatusPending}
+		waitlisted := team
+		waitlisted.RegistrationStatus = StatusWaitlisted
+		mt.AddMockResponses(
+			found("teamregistrations", doc(mt, team)),
+			found("events", eventWithCapacity(mt, eventID, 2)),
+			found("catalog", trackEntry(mt)),
+			counted("teamregistrations", 2),
+			modified(mt, waitlisted),
+		)
+
+		got, err := newTestDB(mt).ApproveTeamRegistration(context.Background(), team.ID.Hex(), 0, "admin")
+		if err != nil {
+			mt.Fatalf("ApproveTeamRegistration: %v", err)
+		}
+		if got.RegistrationStatus != StatusWaitlisted {
+			mt.Errorf("status = %s, want waitlisted", got.RegistrationStatus)
+		}
+		nextCommand(mt, "find")
+		nextCommand(mt, "find")
+		nextCommand(mt, "find")
+		nextCommand(mt, "aggregate")
+		update := nextCommand(mt, "findAndModify")
+		set := update.Lookup("update", "$set").Document()
+		if got := set.Lookup("registrationStatus").StringValue(); got != string(StatusWaitlisted) {
+			mt.Errorf("set status %s, want waitlisted", got)
+		}
+		if _, err := set.LookupErr("waitlistedAt"); err != nil {
+			mt.Error("the waitlist position isn't recorded")
+		}
+	})
+
+	mt.Run("keeps the waitlist position of a team waitlisted before", func(mt *mtest.T) {
+		eventID := primitive.NewObjectID()
+		since := time.Now().Add(-time.Hour)
+		team := TeamRegistration{ID: primitive.NewObjectID(), EventID: eventID, Track: TrackAirQuality, RegistrationStatus: StatusWaitlisted, WaitlistedAt: &since}
+		mt.AddMockResponses(
+			found("teamregistrations", doc(mt, team)),
+			found("events", eventWithCapacity(mt, eventID, 1)),
+			found("catalog", trackEntry(mt)),
+			counted("teamregistrations", 1),
+			modified(mt, team),
+		)
+
+		if _, err := newTestDB(mt).ApproveTeamRegistration(context.Background(), team.ID.Hex(), 0, "admin"); err != nil {
+			mt.Fatalf("ApproveTeamRegistration: %v", err)
+		}
+		for i := 0; i < 4; i++ {
+			mt.GetStartedEvent()
+		}
+		update := nextCommand(mt, "findAndModify")
+		if _, err := update.LookupErr("update", "$set", "waitlistedAt"); err == nil {
+			mt.Error("the waitlist position was reset")
+		}
+	})
+
+	mt.Run("unlimited tracks don't count approvals", func(mt *mtest.T) {
+		eventID := primitive.NewObjectID()
+		team := TeamRegistration{ID: primitive.NewObjectID(), EventID: eventID, Track: TrackAirQuality, RegistrationStatus: StatusPending}
+		mt.AddMockResponses(
+			found("teamregistrations", doc(mt, team)),
+			found("events", eventWithCapacity(mt, eventID, 0)),
+			found("catalog", trackEntry(mt)),
+			modified(mt, team),
+		)
+
+		if _, err := newTestDB(mt).ApproveTeamRegistration(context.Background(), team.ID.Hex(), 0, "admin"); err != nil {
+			mt.Fatalf("ApproveTeamRegistration: %v", err)
+		}
+		nextCommand(mt, "find")
+		nextCommand(mt, "find")
+		nextCommand(mt, "find")
+		nextCommand(mt, "findAndModify")
+	})
+}
+
+func TestPromoteWaitlisted(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("promotes the oldest waitlisted teams until the track is full", func(mt *mtest.T) {
+		eventID := primitive.NewObjectID()
+		first := TeamRegistration{ID: primitive.NewObjectID(), EventID: eventID, Track: TrackAirQuality, RegistrationStatus: StatusWaitlisted, Version: 2}
+		second := TeamRegistration{ID: primitive.NewObjectID(), EventID: eventID, Track: TrackAirQuality, RegistrationStatus: StatusWaitlisted}
+		mt.AddMockResponses(
+			found("events", eventWithCapacity(mt, eventID, 3)),
+			found("catalog", trackEntry(mt)),
+			counted("teamregistrations", 1),
+			modified(mt, first),
+			mtest.CreateSuccessResponse(),
+			counted("teamregistrations", 2),
+			modified(mt, second),
+			mtest.CreateSuccessResponse(),
+			counted("teamregistrations", 3),
+		)
+
+		ctx := logging.WithRequestID(context.Background(), "req-7")
+		promoted, err := newTestDB(mt).PromoteWaitlisted(ctx, eventID, TrackAirQuality)
+		if err != nil {
+			mt.Fatalf("PromoteWaitlisted: %v", err)
+		}
+		if len(promoted) != 2 || promoted[0].ID != first.ID || promoted[1].ID != second.ID {
+			mt.Fatalf("promoted %v, want the two teams in waitlist order", promoted)
+		}
+		if got := promoted[0]; got.RegistrationStatus != StatusApproved || got.ApprovedAt == nil || got.Version != 3 {
+			mt.Errorf("promoted team = %+v, want approved at version 3", got)
+		}
+
+		nextCommand(mt, "find")
+		nextCommand(mt, "find")
+		nextCommand(mt, "aggregate")
+		promote := nextCommand(mt, "findAndModify")
+		if got := promote.Lookup("query", "eventId").ObjectID(); got != eventID {
+			mt.Errorf("promoted a team of event %s, want %s", got.Hex(), eventID.Hex())
+		}
+		if got := promote.Lookup("query", "registrationStatus").StringValue(); got != string(StatusWaitlisted) {
+			mt.Errorf("promoted a %s team, want a waitlisted one", got)
+		}
+		sort := promote.Lookup("sort").Document()
+		if keys, _ := sort.Elements(); len(keys) != 2 || keys[0].Key() != "waitlistedAt" || keys[0].Value().Int32() != 1 {
+			mt.Errorf("sort = %v, want the longest waiting team first", sort)
+		}
+		if got := promote.Lookup("update", "$set", "registrationStatus").StringValue(); got != string(StatusApproved) {
+			mt.Errorf("promoted to %s, want approved", got)
+		}
+
+		audit := nextCommand(mt, "insert")
+		if got := audit.Lookup("insert").StringValue(); got != "audit_log" {
+			mt.Fatalf("inserted into %s, want the audit log", got)
+		}
+		var entry AuditEntry
+		if err := bson.Unmarshal(audit.Lookup("documents").Array().Index(0).Value().Document(), &entry); err != nil {
+			mt.Fatalf("decode audit entry: %v", err)
+		}
+		if entry.Action != AuditTeamPromote || entry.Actor != AuditSystemActor || entry.EventID != eventID {
+			mt.Errorf("audit entry = %+v, want a system promotion in the event", entry)
+		}
+		if entry.Target != (AuditTarget{Type: AuditTargetTeam, ID: first.ID.Hex()}) || entry.RequestID != "req-7" {
+			mt.Errorf("audit entry = %+v, want the first team under request req-7", entry)
+		}
+		status := AuditChange{Field: "registrationStatus", Before: `"waitlisted"`, After: `"approved"`}
+		if !slices.Contains(entry.Changes, status) {
+			mt.Errorf("changes = %+v, want the status change", entry.Changes)
+		}
+	})
+
+	mt.Run("stops when the waitlist is empty", func(mt *mtest.T) {
+		eventID := primitive.NewObjectID()
+		team := TeamRegistration{ID: primitive.NewObjectID(), EventID: eventID, Track: TrackAirQuality, RegistrationStatus: StatusWaitlisted}
+		mt.AddMockResponses(
+			found("events", eventWithCapacity(mt, eventID, 0)),
+			found("catalog", trackEntry(mt)),
+			modified(mt, team),
+			mtest.CreateSuccessResponse(),
+			modified(mt, nil),
+		)
+
+		promoted, err := newTestDB(mt).PromoteWaitlisted(context.Background(), eventID, TrackAirQuality)
+		if err != nil {
+			mt.Fatalf("PromoteWaitlisted: %v", err)
+		}
+		if len(promoted) != 1 {
+			mt.Errorf("promoted %d teams, want 1", len(promoted))
+		}
+	})
+}
@@ -3,21 +3,20 @@ package main
 import (
 	"context"
 	"fmt"
-	"os"
+	"log/slog"
 	"time"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
-	"github.com/joho/godotenv"
+	"github.com/Mastermind730/igc-admin-backend/metrics"
+	"github.com/Mastermind730/igc-admin-backend/tracing"
 )
 
-func SetupMongoDB() (*mongo.Client, error) {
-	_ = godotenv.Load()
-	uri := os.Getenv("MONGODB_URI")
+func SetupMongoDB(uri string) (*mongo.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetMonitor(tracing.CombineMonitors(tracing.MongoMonitor(), metrics.CommandMonitor())))
 	if err != nil {
 		return nil, fmt.Errorf("MongoDB connect issue: %v", err)
 	}
@@ -27,7 +26,7 @@ func SetupMongoDB() (*mongo.Client, error) {
 		return nil, fmt.Errorf("MongoDB ping issue: %v", err)
 	}
 	
-	fmt.Println("Successfully connected to MongoDB!")
+	slog.Info("connected to MongoDB")
 	return client, nil
 }
 
@@ -48,6 +47,6 @@ func CloseConnection(client *mongo.Client, context context.Context, cancel conte
   if err := client.Disconnect(context); err != nil {
    panic(err)
   }
-  fmt.Println("Close connection is called")
+  slog.Info("MongoDB connection closed")
  }()
 }
\ No newline at end of file
@@ -0,0 +1,281 @@
+// Package annotations reads the swag-style comments (@Summary, @Param, @Router, ...)
+// that document the HTTP handlers.
+package annotations
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operation is one documented route, read from the swag-style comment of its handler
+type Operation struct {
+	Method      string
+	Path        string
+	Handler     string
+	Summary     string
+	Description string
+	Tags        []string
+	Accept      []string
+	Produce     []string
+	Params      []Param
+	Responses   []Response
+	Security    []string
+}
+
+// Param is an @Param annotation; body parameters name their type in Type
+type Param struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// Response is an @Success or @Failure annotation. Kind is object, array, file or string.
+type Response struct {
+	Status      int
+	Kind        string
+	Type        string
+	Description string
+}
+
+// Packages are the packages, relative to the module root, whose handlers are annotated
+var Packages = []string{"handlers", "metrics"}
+
+// PrimitiveTypes are the types path, query and header parameters can have
+var PrimitiveTypes = []string{"string", "int", "integer", "bool", "boolean", "number"}
+
+// Parse reads the annotated handlers of Packages under the module root. A handler
+// documented for several routes (one @Router line each) yields one operation per route.
+func Parse(root string) ([]Operation, error) {
+	var ops []Operation
+	for _, pkg := range Packages {
+		dir := filepath.Join(root, pkg)
+		fset := token.NewFileSet()
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range files {
+			if strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			src, err := os.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+			file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Doc == nil {
+					continue
+				}
+				parsed, err := parseComment(file.Name.Name, fn.Doc.Text())
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %w", filepath.Base(name), fn.Name.Name, err)
+				}
+				for i := range parsed {
+					parsed[i].Handler = file.Name.Name + "." + funcName(fn)
+				}
+				ops = append(ops, parsed...)
+			}
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+	for i := 1; i < len(ops); i++ {
+		if ops[i].Path == ops[i-1].Path && ops[i].Method == ops[i-1].Method {
+			return nil, fmt.Errorf("%s %s is documented by both %s and %s", ops[i].Method, ops[i].Path, ops[i-1].Handler, ops[i].Handler)
+		}
+	}
+	return ops, nil
+}
+
+// funcName returns Type.Method for methods and the function name otherwise
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// parseComment parses the annotations of one doc comment; comments without @Router yield nothing
+func parseComment(pkg, text string) ([]Operation, error) {
+	var op Operation
+	var routes [][2]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "@Summary":
+			op.Summary = value
+		case "@Description":
+			op.Description = strings.TrimSpace(op.Description + " " + value)
+		case "@Tags":
+			op.Tags = append(op.Tags, splitList(value)...)
+		case "@Accept":
+			op.Accept = append(op.Accept, mimeTypes(value)...)
+		case "@Produce":
+			op.Produce = append(op.Produce, mimeTypes(value)...)
+		case "@Security":
+			op.Security = append(op.Security, value)
+		case "@Param":
+			p, err := parseParam(pkg, value)
+			if err != nil {
+				return nil, err
+			}
+			op.Params = append(op.Params, p)
+		case "@Success", "@Failure":
+			r, err := parseResponse(pkg, value)
+			if err != nil {
+				return nil, err
+			}
+			op.Responses = append(op.Responses, r)
+		case "@Router":
+			fields := strings.Fields(value)
+			if len(fields) != 2 || !strings.HasPrefix(fields[1], "[") || !strings.HasSuffix(fields[1], "]") {
+				return nil, fmt.Errorf("invalid @Router %q, want: /path [method]", value)
+			}
+			routes = append(routes, [2]string{strings.ToUpper(strings.Trim(fields[1], "[]")), fields[0]})
+		default:
+			return nil, fmt.Errorf("unknown annotation %s", key)
+		}
+	}
+
+	if len(routes) == 0 {
+		return nil, nil
+	}
+	if op.Summary == "" {
+		return nil, fmt.Errorf("missing @Summary")
+	}
+	if len(op.Responses) == 0 {
+		return nil, fmt.Errorf("missing @Success")
+	}
+
+	ops := make([]Operation, 0, len(routes))
+	for _, route := range routes {
+		o := op
+		o.Method, o.Path = route[0], route[1]
+		ops = append(ops, o)
+	}
+	return ops, nil
+}
+
+// parseParam parses `name in type required "description"`
+func parseParam(pkg, value string) (Param, error) {
+	fields, desc := splitDescription(value)
+	if len(fields) != 4 {
+		return Param{}, fmt.Errorf("invalid @Param %q, want: name in type required \"description\"", value)
+	}
+	required, err := strconv.ParseBool(fields[3])
+	if err != nil {
+		return Param{}, fmt.Errorf("invalid @Param %q: required must be true or false", value)
+	}
+	p := Param{Name: fields[0], In: fields[1], Type: fields[2], Required: required, Description: desc}
+	switch p.In {
+	case "body":
+		p.Type = qualify(pkg, p.Type)
+	case "path", "query", "header":
+		if !slices.Contains(PrimitiveTypes, p.Type) {
+			return Param{}, fmt.Errorf("invalid @Param %q: unsupported type %s", value, p.Type)
+		}
+	default:
+		return Param{}, fmt.Errorf("invalid @Param %q: unsupported location %s", value, p.In)
+	}
+	return p, nil
+}
+
+// parseResponse parses `status {kind} type "description"`
+func parseResponse(pkg, value string) (Response, error) {
+	fields, desc := splitDescription(value)
+	if len(fields) != 3 {
+		return Response{}, fmt.Errorf("invalid response %q, want: status {kind} type", value)
+	}
+	status, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return Response{}, fmt.Errorf("invalid response %q: bad status", value)
+	}
+	r := Response{Status: status, Kind: strings.Trim(fields[1], "{}"), Type: fields[2], Description: desc}
+	switch r.Kind {
+	case "object", "array":
+		if r.Type != "object" {
+			r.Type = qualify(pkg, r.Type)
+		}
+	case "file", "string":
+	default:
+		return Response{}, fmt.Errorf("invalid response %q: unsupported kind %s", value, r.Kind)
+	}
+	return r, nil
+}
+
+// splitDescription separates the fields of an annotation from its quoted description
+func splitDescription(value string) ([]string, string) {
+	before, after, found := strings.Cut(value, `"`)
+	if !found {
+		return strings.Fields(value), ""
+	}
+	return strings.Fields(before), strings.TrimSuffix(strings.TrimSpace(after), `"`)
+}
+
+// qualify prefixes types declared in the annotated package with its name
+func qualify(pkg, typ string) string {
+	if strings.Contains(typ, ".") {
+		return typ
+	}
+	return pkg + "." + typ
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// mimeTypes expands the swag shorthands (json, html, plain) to MIME types
+func mimeTypes(value string) []string {
+	var types []string
+	for _, item := range splitList(value) {
+		switch item {
+		case "json":
+			item = "application/json"
+		case "html":
+			item = "text/html"
+		case "plain":
+			item = "text/plain"
+		}
+		types = append(types, item)
+	}
+	return types
+}
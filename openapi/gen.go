@@ -0,0 +1,110 @@
+//go:build ignore
+
+// gen parses the handler annotations and writes operations_gen.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Mastermind730/igc-admin-backend/openapi/annotations"
+)
+
+const module = "github.com/Mastermind730/igc-admin-backend/"
+
+func main() {
+	ops, err := annotations.Parse("..")
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(generate(ops))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("operations_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(ops []annotations.Operation) []byte {
+	types := map[string]bool{}
+	for _, op := range ops {
+		for _, p := range op.Params {
+			if p.In == "body" {
+				types[p.Type] = true
+			}
+		}
+		for _, r := range op.Responses {
+			if (r.Kind == "object" || r.Kind == "array") && r.Type != "object" {
+				types[r.Type] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(types))
+	pkgs := map[string]bool{"openapi/annotations": true}
+	for name := range types {
+		names = append(names, name)
+		pkgs[name[:strings.Index(name, ".")]] = true
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by go generate ./openapi; DO NOT EDIT.\n\npackage openapi\n\nimport (\n\t\"reflect\"\n\n")
+	imports := make([]string, 0, len(pkgs))
+	for pkg := range pkgs {
+		imports = append(imports, pkg)
+	}
+	sort.Strings(imports)
+	for _, pkg := range imports {
+		fmt.Fprintf(&b, "\t%q\n", module+pkg)
+	}
+	b.WriteString(")\n\nvar operations = []annotations.Operation{\n")
+	for _, op := range ops {
+		fmt.Fprintf(&b, "\t{\n\t\tMethod: %q,\n\t\tPath: %q,\n\t\tHandler: %q,\n\t\tSummary: %q,\n", op.Method, op.Path, op.Handler, op.Summary)
+		if op.Description != "" {
+			fmt.Fprintf(&b, "\t\tDescription: %q,\n", op.Description)
+		}
+		writeStrings(&b, "Tags", op.Tags)
+		writeStrings(&b, "Accept", op.Accept)
+		writeStrings(&b, "Produce", op.Produce)
+		if len(op.Params) > 0 {
+			b.WriteString("\t\tParams: []annotations.Param{\n")
+			for _, p := range op.Params {
+				fmt.Fprintf(&b, "\t\t\t{Name: %q, In: %q, Type: %q, Required: %t, Description: %q},\n", p.Name, p.In, p.Type, p.Required, p.Description)
+			}
+			b.WriteString("\t\t},\n")
+		}
+		b.WriteString("\t\tResponses: []annotations.Response{\n")
+		for _, r := range op.Responses {
+			fmt.Fprintf(&b, "\t\t\t{Status: %d, Kind: %q, Type: %q, Description: %q},\n", r.Status, r.Kind, r.Type, r.Description)
+		}
+		b.WriteString("\t\t},\n")
+		writeStrings(&b, "Security", op.Security)
+		b.WriteString("\t},\n")
+	}
+	b.WriteString("}\n\nvar schemaTypes = map[string]reflect.Type{\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "\t%q: typeOf[%s](),\n", name, name)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func writeStrings(b *bytes.Buffer, field string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\t\t%s: []string{", field)
+	for i, v := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(b, "%q", v)
+	}
+	b.WriteString("},\n")
+}
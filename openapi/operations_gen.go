@@ -0,0 +1,1476 @@
+// Code generated by go generate ./openapi; DO NOT EDIT.
+
+package openapi
+
+import (
+	"reflect"
+
+	"github.com/Mastermind730/igc-admin-backend/handlers"
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/Mastermind730/igc-admin-backend/openapi/annotations"
+)
+
+var operations = []annotations.Operation{
+	{
+		Method:      "GET",
+		Path:        "/",
+		Handler:     "handlers.HealthHandler.Index",
+		Summary:     "API root",
+		Description: "Welcome message with the API version and a link to the documentation",
+		Tags:        []string{"health"},
+		Produce:     []string{"application/json"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.IndexResponse", Description: ""},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/audit-log/",
+		Handler:     "handlers.AuditHandler.GetAuditLog",
+		Summary:     "List audit log",
+		Description: "List the recorded administrative and judging actions, newest first by default (admin only)",
+		Tags:        []string{"audit"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "eventId", In: "query", Type: "string", Required: false, Description: "Filter by event ID"},
+			{Name: "actorId", In: "query", Type: "string", Required: false, Description: "Filter by actor user ID (team ID for participants)"},
+			{Name: "actor", In: "query", Type: "string", Required: false, Description: "Filter by actor username"},
+			{Name: "action", In: "query", Type: "string", Required: false, Description: "Filter by action, e.g. team.approve"},
+			{Name: "targetType", In: "query", Type: "string", Required: false, Description: "Filter by target type (user/team/changeRequest/event/catalog/certificate)"},
+			{Name: "targetId", In: "query", Type: "string", Required: false, Description: "Filter by target ID"},
+			{Name: "from", In: "query", Type: "string", Required: false, Description: "First day, YYYY-MM-DD (UTC)"},
+			{Name: "to", In: "query", Type: "string", Required: false, Description: "Last day, YYYY-MM-DD (UTC)"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: -createdAt)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.AuditLogResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid filter or paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/audit-log/export",
+		Handler:     "handlers.AuditHandler.ExportAuditLog",
+		Summary:     "Export audit log",
+		Description: "Download the audit log entries matching the filters as CSV, oldest first, one row per entry with the changes as JSON (admin only)",
+		Tags:        []string{"audit"},
+		Produce:     []string{"text/csv"},
+		Params: []annotations.Param{
+			{Name: "eventId", In: "query", Type: "string", Required: false, Description: "Filter by event ID"},
+			{Name: "actorId", In: "query", Type: "string", Required: false, Description: "Filter by actor user ID (team ID for participants)"},
+			{Name: "actor", In: "query", Type: "string", Required: false, Description: "Filter by actor username"},
+			{Name: "action", In: "query", Type: "string", Required: false, Description: "Filter by action, e.g. team.approve"},
+			{Name: "targetType", In: "query", Type: "string", Required: false, Description: "Filter by target type (user/team/changeRequest/event/catalog/certificate)"},
+			{Name: "targetId", In: "query", Type: "string", Required: false, Description: "Filter by target ID"},
+			{Name: "from", In: "query", Type: "string", Required: false, Description: "First day, YYYY-MM-DD (UTC)"},
+			{Name: "to", In: "query", Type: "string", Required: false, Description: "Last day, YYYY-MM-DD (UTC)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "file", Type: "file", Description: "CSV of audit log entries"},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid filter"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/auth/login",
+		Handler:     "handlers.UserHandler.Login",
+		Summary:     "Login user",
+		Description: "Authenticate an admin or judge with username and password",
+		Tags:        []string{"auth"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "loginData", In: "body", Type: "handlers.LoginRequest", Required: true, Description: "Login credentials"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.LoginResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Invalid credentials"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/certificates/",
+		Handler:     "handlers.CertificateHandler.GetCertificates",
+		Summary:     "List certificates",
+		Description: "List issued certificates filtered by team, type, track or award (admin only)",
+		Tags:        []string{"certificates"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "teamId", In: "query", Type: "string", Required: false, Description: "Team registration ID"},
+			{Name: "type", In: "query", Type: "string", Required: false, Description: "Certificate type (participation/winner)"},
+			{Name: "track", In: "query", Type: "string", Required: false, Description: "Track"},
+			{Name: "award", In: "query", Type: "string", Required: false, Description: "Award"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.CertificateListResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/certificates/bulk",
+		Handler:     "handlers.CertificateHandler.GenerateCertificates",
+		Summary:     "Bulk generate certificates",
+		Description: "Issue participation or winner certificates for all teams of a stage result (admin only). Recipients already holding a certificate of the same type and award are skipped.",
+		Tags:        []string{"certificates"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "request", In: "body", Type: "handlers.BulkCertificateRequest", Required: true, Description: "Selection"},
+		},
+		Responses: []annotations.Response{
+			{Status: 201, Kind: "object", Type: "handlers.BulkCertificateResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Event is read-only"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/certificates/verify/{code}",
+		Handler:     "handlers.VerificationHandler.VerifyCertificate",
+		Summary:     "Verify certificate",
+		Description: "Check a certificate verification code without exposing contact details",
+		Tags:        []string{"verification"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "code", In: "path", Type: "string", Required: true, Description: "Verification code"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.CertificateVerificationResponse", Description: ""},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 429, Kind: "object", Type: "handlers.Problem", Description: "Rate limit exceeded"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/certificates/zip",
+		Handler:     "handlers.CertificateHandler.DownloadCertificatesZIP",
+		Summary:     "Download certificates as ZIP",
+		Description: "Download the PDFs of all certificates matching the filters (admin only)",
+		Tags:        []string{"certificates"},
+		Produce:     []string{"application/zip"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "teamId", In: "query", Type: "string", Required: false, Description: "Team registration ID"},
+			{Name: "type", In: "query", Type: "string", Required: false, Description: "Certificate type (participation/winner)"},
+			{Name: "track", In: "query", Type: "string", Required: false, Description: "Track"},
+			{Name: "award", In: "query", Type: "string", Required: false, Description: "Award"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "file", Type: "file", Description: "ZIP archive of certificate PDFs"},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event not found or no matching certificates"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/certificates/{code}/pdf",
+		Handler:     "handlers.CertificateHandler.DownloadCertificatePDF",
+		Summary:     "Download certificate PDF",
+		Description: "Render a single certificate as PDF (admin only)",
+		Tags:        []string{"certificates"},
+		Produce:     []string{"application/pdf"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "code", In: "path", Type: "string", Required: true, Description: "Verification code"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "file", Type: "file", Description: "Certificate PDF"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or certificate not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/change-requests/",
+		Handler:     "handlers.ParticipantHandler.GetChangeRequests",
+		Summary:     "List change requests",
+		Description: "List participant change requests (admin only)",
+		Tags:        []string{"change-requests"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "status", In: "query", Type: "string", Required: false, Description: "Filter by status (pending/approved/rejected)"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: -createdAt)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.ChangeRequestListResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/change-requests/{id}/action",
+		Handler:     "handlers.ParticipantHandler.ReviewChangeRequest",
+		Summary:     "Review change request",
+		Description: "Approve (and apply) or reject a participant change request (admin only). Approved changes are applied on top of later changes to other fields of the team registration. If a requested field itself changed since the request, approving fails with the conflicting fields and the request stays pending.",
+		Tags:        []string{"change-requests"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Change Request ID"},
+			{Name: "review", In: "body", Type: "handlers.ReviewChangeRequestRequest", Required: true, Description: "Decision"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.ChangeRequestEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Already reviewed, the event is read-only, or the team registration changed the requested fields since the request (current registration and fields included)"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/docs",
+		Handler:     "handlers.DocsHandler.UI",
+		Summary:     "API documentation",
+		Description: "Interactive documentation of the API (Swagger UI)",
+		Tags:        []string{"docs"},
+		Produce:     []string{"text/html"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "string", Type: "string", Description: ""},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/evaluations/",
+		Handler:     "handlers.EventHandler.GetEvaluations",
+		Summary:     "List evaluations",
+		Description: "List judge evaluations and rubric scores of an event (admin only)",
+		Tags:        []string{"events"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "teamId", In: "query", Type: "string", Required: false, Description: "Filter by team registration ID"},
+			{Name: "judgeId", In: "query", Type: "string", Required: false, Description: "Filter by judge ID"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: -createdAt)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.EvaluationListResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid filter or paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/event-config",
+		Handler:     "handlers.EventConfigHandler.GetEventConfig",
+		Summary:     "Get event configuration",
+		Description: "Get registration windows, deadlines and per-track capacity of an event",
+		Tags:        []string{"event-config"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.EventConfigResponse", Description: ""},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event not found"},
+		},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/event-config",
+		Handler:     "handlers.EventConfigHandler.UpdateEventConfig",
+		Summary:     "Update event configuration",
+		Description: "Replace registration windows, deadlines, per-track capacity and the duplicate participant policy (admin only). Raising a capacity promotes waitlisted teams immediately.",
+		Tags:        []string{"event-config"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "config", In: "body", Type: "handlers.UpdateEventConfigRequest", Required: true, Description: "Event configuration"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.EventConfigUpdateResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Event is read-only"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/events/",
+		Handler:     "handlers.EventHandler.GetEvents",
+		Summary:     "List events",
+		Description: "List all editions of the challenge, newest first",
+		Tags:        []string{"events"},
+		Produce:     []string{"application/json"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.EventListResponse", Description: ""},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/events/",
+		Handler:     "handlers.EventHandler.CreateEvent",
+		Summary:     "Create event",
+		Description: "Create a new edition (admin only). The first event becomes the active one.",
+		Tags:        []string{"events"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "body", Type: "handlers.EventRequest", Required: true, Description: "Event data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 201, Kind: "object", Type: "handlers.EventEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Event already exists"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/events/{event}",
+		Handler:     "handlers.EventHandler.GetEvent",
+		Summary:     "Get event",
+		Description: "Get an event with its tracks, rubric and deadlines",
+		Tags:        []string{"events"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "path", Type: "string", Required: true, Description: "Event slug or ID"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.EventDetailResponse", Description: ""},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/events/{event}",
+		Handler:     "handlers.EventHandler.UpdateEvent",
+		Summary:     "Update event",
+		Description: "Update an event's tracks, rubric, deadlines, prefixes and judge pool (admin only). Raising a capacity promotes waitlisted teams immediately.",
+		Tags:        []string{"events"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "path", Type: "string", Required: true, Description: "Event slug or ID"},
+			{Name: "data", In: "body", Type: "handlers.EventRequest", Required: true, Description: "Event data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.EventEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Number prefix already in use"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/events/{event}/activate",
+		Handler:     "handlers.EventHandler.ActivateEvent",
+		Summary:     "Activate event",
+		Description: "Make an event the active edition (admin only)",
+		Tags:        []string{"events"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "path", Type: "string", Required: true, Description: "Event slug or ID"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.EventEnvelope", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/health",
+		Handler:     "handlers.HealthHandler.Status",
+		Summary:     "Health check",
+		Description: "Reports that the API is running and its version",
+		Tags:        []string{"health"},
+		Produce:     []string{"application/json"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.StatusResponse", Description: ""},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/openapi.json",
+		Handler:     "handlers.DocsHandler.OpenAPI",
+		Summary:     "OpenAPI document",
+		Description: "The OpenAPI 3 description of this API",
+		Tags:        []string{"docs"},
+		Produce:     []string{"application/json"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "object", Description: ""},
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/participant/auth/request-link",
+		Handler:     "handlers.ParticipantHandler.RequestMagicLink",
+		Summary:     "Request participant login link",
+		Description: "Email a one-time login link to a team leader. Always succeeds to avoid leaking which emails are registered.",
+		Tags:        []string{"participant"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "request", In: "body", Type: "handlers.MagicLinkRequest", Required: true, Description: "Leader email"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.MessageResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 429, Kind: "object", Type: "handlers.Problem", Description: "Rate limit exceeded"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/participant/auth/verify",
+		Handler:     "handlers.ParticipantHandler.VerifyMagicLink",
+		Summary:     "Verify participant login link",
+		Description: "Exchange a one-time login token for a participant JWT",
+		Tags:        []string{"participant"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "request", In: "body", Type: "handlers.MagicLinkVerifyRequest", Required: true, Description: "Login token"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.ParticipantLoginResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Login link is invalid or has expired"},
+			{Status: 429, Kind: "object", Type: "handlers.Problem", Description: "Rate limit exceeded"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/participant/change-requests",
+		Handler:     "handlers.ParticipantHandler.GetMyChangeRequests",
+		Summary:     "List own change requests",
+		Description: "List change requests submitted by the participant's team",
+		Tags:        []string{"participant"},
+		Produce:     []string{"application/json"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.ChangeRequestListResponse", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Participant session required"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"ParticipantAuth"},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/participant/change-requests",
+		Handler:     "handlers.ParticipantHandler.CreateChangeRequest",
+		Summary:     "Request registration change",
+		Description: "Submit changes to a restricted set of fields; an admin must approve them",
+		Tags:        []string{"participant"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "changes", In: "body", Type: "handlers.ParticipantChangeRequestPayload", Required: true, Description: "Requested changes"},
+		},
+		Responses: []annotations.Response{
+			{Status: 201, Kind: "object", Type: "handlers.ChangeRequestEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Participant session required or edit deadline passed"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "A change request is already awaiting review, a participant is already in another team or the event is read-only"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"ParticipantAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/participant/registration",
+		Handler:     "handlers.ParticipantHandler.GetMyRegistration",
+		Summary:     "Get own registration",
+		Description: "View the participant's registration, status, rejection reason and video status",
+		Tags:        []string{"participant"},
+		Produce:     []string{"application/json"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.ParticipantRegistrationResponse", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Participant session required"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"ParticipantAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/programs/",
+		Handler:     "handlers.CatalogHandler.GetEntries",
+		Summary:     "List tracks or programs",
+		Description: "List the active tracks or programs (under \"tracks\" or \"programs\"); admins can include inactive ones",
+		Tags:        []string{"catalog"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "includeInactive", In: "query", Type: "bool", Required: false, Description: "Include inactive entries"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.CatalogListResponse", Description: ""},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/programs/",
+		Handler:     "handlers.CatalogHandler.CreateEntry",
+		Summary:     "Create track or program",
+		Description: "Add a track or program (admin only). The slug defaults to one derived from the name.",
+		Tags:        []string{"catalog"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "entry", In: "body", Type: "handlers.CatalogEntryRequest", Required: true, Description: "Entry data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 201, Kind: "object", Type: "handlers.CatalogEntryEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Slug or name already in use"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "DELETE",
+		Path:        "/api/v1/programs/{slug}",
+		Handler:     "handlers.CatalogHandler.DeleteEntry",
+		Summary:     "Delete track or program",
+		Description: "Delete a track or program no team registered for (admin only); deactivate used ones instead",
+		Tags:        []string{"catalog"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "slug", In: "path", Type: "string", Required: true, Description: "Slug"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.MessageResponse", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Entry is in use"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/programs/{slug}",
+		Handler:     "handlers.CatalogHandler.GetEntry",
+		Summary:     "Get track or program",
+		Description: "Get a track or program by slug (under \"track\" or \"program\")",
+		Tags:        []string{"catalog"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "slug", In: "path", Type: "string", Required: true, Description: "Slug"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.CatalogEntryEnvelope", Description: ""},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/programs/{slug}",
+		Handler:     "handlers.CatalogHandler.UpdateEntry",
+		Summary:     "Update track or program",
+		Description: "Update a track or program (admin only). Renaming moves the team registrations and events using the old name to the new one, except in read-only past events. Issued certificates keep the name they were issued with.",
+		Tags:        []string{"catalog"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "slug", In: "path", Type: "string", Required: true, Description: "Slug"},
+			{Name: "entry", In: "body", Type: "handlers.CatalogEntryRequest", Required: true, Description: "Entry data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.CatalogEntryEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Slug or name already in use"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/",
+		Handler:     "handlers.TeamRegistrationHandler.GetAllTeamRegistrations",
+		Summary:     "Get all team registrations",
+		Description: "Get the team registrations that submitted a video, with optional pagination and filtering",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: -submittedAt)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+			{Name: "status", In: "query", Type: "string", Required: false, Description: "Filter by status (pending/approved/rejected/waitlisted, default: approved)"},
+			{Name: "track", In: "query", Type: "string", Required: false, Description: "Filter by track"},
+			{Name: "institution", In: "query", Type: "string", Required: false, Description: "Filter by institution"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamListResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/team-registrations/",
+		Handler:     "handlers.TeamRegistrationHandler.CreateTeamRegistration",
+		Summary:     "Create team registration",
+		Description: "Create a new team registration for the IGC hackathon",
+		Tags:        []string{"team-registrations"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "teamData", In: "body", Type: "handlers.CreateTeamRegistrationRequest", Required: true, Description: "Team registration data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 201, Kind: "object", Type: "handlers.TeamEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Registration is closed"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Team name exists, program is full, a participant is already in another team or the event is read-only"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/allocated",
+		Handler:     "handlers.UserHandler.GetAllocatedTeamsForJudge",
+		Summary:     "List allocated teams",
+		Description: "List the teams allocated to the logged-in judge that submitted a video (judges only)",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: -submittedAt)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamListResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/analytics",
+		Handler:     "handlers.TeamRegistrationHandler.GetRegistrationAnalytics",
+		Summary:     "Get registration analytics",
+		Description: "Break the registrations down by status, track, institution, program, country and state, with the gender ratio of leaders and members, the team size distribution, the video submission rate and the daily registrations (admin only)",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "status", In: "query", Type: "string", Required: false, Description: "Filter by status (pending/approved/rejected/waitlisted)"},
+			{Name: "from", In: "query", Type: "string", Required: false, Description: "First submission day, YYYY-MM-DD (UTC)"},
+			{Name: "to", In: "query", Type: "string", Required: false, Description: "Last submission day, YYYY-MM-DD (UTC)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "models.RegistrationAnalytics", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid filters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/analytics/export",
+		Handler:     "handlers.TeamRegistrationHandler.ExportRegistrationAnalytics",
+		Summary:     "Export registration analytics",
+		Description: "Download the registration analytics as CSV, one row per breakdown value with its count and share (admin only)",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"text/csv"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "status", In: "query", Type: "string", Required: false, Description: "Filter by status (pending/approved/rejected/waitlisted)"},
+			{Name: "from", In: "query", Type: "string", Required: false, Description: "First submission day, YYYY-MM-DD (UTC)"},
+			{Name: "to", In: "query", Type: "string", Required: false, Description: "Last submission day, YYYY-MM-DD (UTC)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "file", Type: "file", Description: "CSV with the columns breakdown, value, count and share"},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid filters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/duplicates",
+		Handler:     "handlers.TeamRegistrationHandler.GetDuplicateReport",
+		Summary:     "Get duplicate participant report",
+		Description: "List the emails and phone numbers shared by participants of several teams, and the teams involved (admin only). Rejected registrations are ignored.",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "models.DuplicateReport", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/reg/{regNumber}",
+		Handler:     "handlers.TeamRegistrationHandler.GetTeamRegistrationByRegNumber",
+		Summary:     "Get team registration by registration number",
+		Description: "Get team registration information by registration number. The response carries the registration's ETag, to send as If-Match when changing it.",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "regNumber", In: "path", Type: "string", Required: true, Description: "Registration Number"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamEnvelope", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/search",
+		Handler:     "handlers.TeamRegistrationHandler.SearchTeams",
+		Summary:     "Search team registrations",
+		Description: "Search the teams by text over team, topic, leader and member names and the topic description, with combinable filters (admin only). Facets count the matching teams by the value of each filter.",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "q", In: "query", Type: "string", Required: false, Description: "Text to search for"},
+			{Name: "status", In: "query", Type: "string", Required: false, Description: "Filter by status (pending/approved/rejected/waitlisted)"},
+			{Name: "track", In: "query", Type: "string", Required: false, Description: "Filter by track"},
+			{Name: "program", In: "query", Type: "string", Required: false, Description: "Filter by program"},
+			{Name: "country", In: "query", Type: "string", Required: false, Description: "Filter by country"},
+			{Name: "state", In: "query", Type: "string", Required: false, Description: "Filter by state"},
+			{Name: "institution", In: "query", Type: "string", Required: false, Description: "Filter by institution (case-insensitive substring)"},
+			{Name: "genderMix", In: "query", Type: "string", Required: false, Description: "Filter by the genders of the participants (male/female/other/mixed)"},
+			{Name: "minSize", In: "query", Type: "int", Required: false, Description: "Minimum number of participants, leader included"},
+			{Name: "maxSize", In: "query", Type: "int", Required: false, Description: "Maximum number of participants, leader included"},
+			{Name: "hasVideo", In: "query", Type: "bool", Required: false, Description: "Filter by whether the team submitted a video"},
+			{Name: "allocated", In: "query", Type: "bool", Required: false, Description: "Filter by whether the team is allocated to a judge"},
+			{Name: "judgeId", In: "query", Type: "string", Required: false, Description: "Filter by allocated judge ID"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: -submittedAt)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamSearchResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid filters or paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/stats",
+		Handler:     "handlers.TeamRegistrationHandler.GetTeamRegistrationStats",
+		Summary:     "Get team registration statistics",
+		Description: "Count the registrations of an event by status",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamStatsResponse", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/track/{track}",
+		Handler:     "handlers.TeamRegistrationHandler.GetTeamRegistrationsByTrack",
+		Summary:     "Get team registrations by track",
+		Description: "Get the approved team registrations of a track that submitted a video",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "track", In: "path", Type: "string", Required: true, Description: "Track name"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: -submittedAt)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamListResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "DELETE",
+		Path:        "/api/v1/team-registrations/{id}",
+		Handler:     "handlers.TeamRegistrationHandler.DeleteTeamRegistration",
+		Summary:     "Delete team registration",
+		Description: "Move a team registration to the trash, from where it can be restored until the retention period ends; its registration number is never reused (admin only)",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Team Registration ID"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.MessageResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Event is read-only"},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/team-registrations/{id}",
+		Handler:     "handlers.TeamRegistrationHandler.GetTeamRegistration",
+		Summary:     "Get team registration by ID",
+		Description: "Get team registration information by ID. The response carries the registration's ETag, to send as If-Match when changing it.",
+		Tags:        []string{"team-registrations"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Team Registration ID"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/team-registrations/{id}",
+		Handler:     "handlers.TeamRegistrationHandler.UpdateTeamRegistration",
+		Summary:     "Update team registration",
+		Description: "Update team registration information. Send the ETag of the registration the changes are based on as If-Match; the response carries the ETag of the updated registration.",
+		Tags:        []string{"team-registrations"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Team Registration ID"},
+			{Name: "If-Match", In: "header", Type: "string", Required: false, Description: "ETag of the registration the changes are based on"},
+			{Name: "teamData", In: "body", Type: "handlers.UpdateTeamRegistrationRequest", Required: true, Description: "Updated team data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "A participant is already in another team, the registration changed concurrently (current registration included) or the event is read-only"},
+			{Status: 412, Kind: "object", Type: "handlers.Problem", Description: "If-Match doesn't match the current registration (current registration included)"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/team-registrations/{id}/action",
+		Handler:     "handlers.TeamRegistrationHandler.ApproveOrRejectTeamRegistration",
+		Summary:     "Approve or reject team registration",
+		Description: "Approve or reject a team registration (admin only). The decision is recorded under the authenticated admin. Send the ETag of the registration the decision is based on as If-Match.",
+		Tags:        []string{"team-registrations"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Team Registration ID"},
+			{Name: "If-Match", In: "header", Type: "string", Required: false, Description: "ETag of the registration the decision is based on"},
+			{Name: "actionData", In: "body", Type: "handlers.ApproveRejectRequest", Required: true, Description: "Action data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "The registration changed concurrently (current registration included) or the event is read-only"},
+			{Status: 412, Kind: "object", Type: "handlers.Problem", Description: "If-Match doesn't match the current registration (current registration included)"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/team-registrations/{id}/allocate",
+		Handler:     "handlers.UserHandler.AllocateTeamToJudge",
+		Summary:     "Allocate team to judge",
+		Description: "Assign a team to a judge of the event's judge pool (admin only). Send the ETag of the registration the allocation is based on as If-Match.",
+		Tags:        []string{"team-registrations"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Team Registration ID"},
+			{Name: "If-Match", In: "header", Type: "string", Required: false, Description: "ETag of the registration the allocation is based on"},
+			{Name: "allocation", In: "body", Type: "handlers.TeamAllocationRequest", Required: true, Description: "Judge to allocate"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid request or judge"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "The registration changed concurrently (current registration included) or the event is read-only"},
+			{Status: 412, Kind: "object", Type: "handlers.Problem", Description: "If-Match doesn't match the current registration (current registration included)"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/team-registrations/{id}/evaluate",
+		Handler:     "handlers.UserHandler.JudgeEvaluateTeam",
+		Summary:     "Evaluate team",
+		Description: "Approve or reject an allocated team, optionally scoring it against the event's rubric (judges only). Send the ETag of the registration the evaluation is based on as If-Match.",
+		Tags:        []string{"team-registrations"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Team Registration ID"},
+			{Name: "If-Match", In: "header", Type: "string", Required: false, Description: "ETag of the registration the evaluation is based on"},
+			{Name: "evaluation", In: "body", Type: "handlers.EvaluateTeamRequest", Required: true, Description: "Decision and rubric scores"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.EvaluationResultResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid request or scores"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or resource not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "The registration changed concurrently (current registration included) or the event is read-only"},
+			{Status: 412, Kind: "object", Type: "handlers.Problem", Description: "If-Match doesn't match the current registration (current registration included)"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/tracks/",
+		Handler:     "handlers.CatalogHandler.GetEntries",
+		Summary:     "List tracks or programs",
+		Description: "List the active tracks or programs (under \"tracks\" or \"programs\"); admins can include inactive ones",
+		Tags:        []string{"catalog"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "includeInactive", In: "query", Type: "bool", Required: false, Description: "Include inactive entries"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.CatalogListResponse", Description: ""},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/tracks/",
+		Handler:     "handlers.CatalogHandler.CreateEntry",
+		Summary:     "Create track or program",
+		Description: "Add a track or program (admin only). The slug defaults to one derived from the name.",
+		Tags:        []string{"catalog"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "entry", In: "body", Type: "handlers.CatalogEntryRequest", Required: true, Description: "Entry data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 201, Kind: "object", Type: "handlers.CatalogEntryEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Slug or name already in use"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "DELETE",
+		Path:        "/api/v1/tracks/{slug}",
+		Handler:     "handlers.CatalogHandler.DeleteEntry",
+		Summary:     "Delete track or program",
+		Description: "Delete a track or program no team registered for (admin only); deactivate used ones instead",
+		Tags:        []string{"catalog"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "slug", In: "path", Type: "string", Required: true, Description: "Slug"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.MessageResponse", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Entry is in use"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/tracks/{slug}",
+		Handler:     "handlers.CatalogHandler.GetEntry",
+		Summary:     "Get track or program",
+		Description: "Get a track or program by slug (under \"track\" or \"program\")",
+		Tags:        []string{"catalog"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "slug", In: "path", Type: "string", Required: true, Description: "Slug"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.CatalogEntryEnvelope", Description: ""},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/tracks/{slug}",
+		Handler:     "handlers.CatalogHandler.UpdateEntry",
+		Summary:     "Update track or program",
+		Description: "Update a track or program (admin only). Renaming moves the team registrations and events using the old name to the new one, except in read-only past events. Issued certificates keep the name they were issued with.",
+		Tags:        []string{"catalog"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "slug", In: "path", Type: "string", Required: true, Description: "Slug"},
+			{Name: "entry", In: "body", Type: "handlers.CatalogEntryRequest", Required: true, Description: "Entry data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.CatalogEntryEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Slug or name already in use"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/trash/teams",
+		Handler:     "handlers.TrashHandler.GetDeletedTeams",
+		Summary:     "List deleted team registrations",
+		Description: "List the team registrations of an event in the trash, most recently deleted first by default (admin only)",
+		Tags:        []string{"trash"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: -deletedAt)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamListResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event not found"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/trash/teams/{id}/restore",
+		Handler:     "handlers.TrashHandler.RestoreTeam",
+		Summary:     "Restore team registration",
+		Description: "Restore a deleted team registration of an event with its original registration number. A team that was approved goes to the waitlist if its track has filled up since (admin only)",
+		Tags:        []string{"trash"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "event", In: "query", Type: "string", Required: false, Description: "Event slug or ID (default: active event)"},
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "Team Registration ID"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.TeamEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid ID"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Event or deleted team registration not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Team name taken since, or event is read-only"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/trash/users",
+		Handler:     "handlers.TrashHandler.GetDeletedUsers",
+		Summary:     "List deleted users",
+		Description: "List the users in the trash, most recently deleted first by default (admin only)",
+		Tags:        []string{"trash"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: -deletedAt)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.DeletedUserListResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/trash/users/{id}/restore",
+		Handler:     "handlers.TrashHandler.RestoreUser",
+		Summary:     "Restore user",
+		Description: "Restore a deleted user, who can log in again (admin only)",
+		Tags:        []string{"trash"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.UserEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid ID"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: "Deleted user not found"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Username taken since"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/users/",
+		Handler:     "handlers.UserHandler.GetAllUsers",
+		Summary:     "Get all users",
+		Description: "Get all users with optional pagination",
+		Tags:        []string{"users"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "limit", In: "query", Type: "int", Required: false, Description: "Items per page (default: 10, configurable)"},
+			{Name: "sort", In: "query", Type: "string", Required: false, Description: "Sort field, prefixed with - for descending order (default: username)"},
+			{Name: "cursor", In: "query", Type: "string", Required: false, Description: "nextCursor of the previous page"},
+			{Name: "page", In: "query", Type: "int", Required: false, Description: "Page number, when not using cursors (default: 1; numbered pages reach at most 100000 records deep)"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.UserListResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: "Invalid paging parameters"},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/v1/users/",
+		Handler:     "handlers.UserHandler.CreateUser",
+		Summary:     "Create a new user",
+		Description: "Create an admin or judge account",
+		Tags:        []string{"users"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "userData", In: "body", Type: "handlers.UnifiedCreateUserRequest", Required: true, Description: "User data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 201, Kind: "object", Type: "handlers.UserEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Username already exists"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "DELETE",
+		Path:        "/api/v1/users/{id}",
+		Handler:     "handlers.UserHandler.DeleteUser",
+		Summary:     "Delete user",
+		Description: "Move a user to the trash, from where it can be restored until the retention period ends; a deleted user can't log in",
+		Tags:        []string{"users"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.MessageResponse", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/users/{id}",
+		Handler:     "handlers.UserHandler.GetUser",
+		Summary:     "Get user by ID",
+		Description: "Get user information by user ID. The response carries the user's ETag, to send as If-Match when changing it.",
+		Tags:        []string{"users"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.UserEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "PUT",
+		Path:        "/api/v1/users/{id}",
+		Handler:     "handlers.UserHandler.UpdateUser",
+		Summary:     "Update user",
+		Description: "Update user information. Send the ETag of the user the changes are based on as If-Match; the response carries the ETag of the updated user.",
+		Tags:        []string{"users"},
+		Accept:      []string{"application/json"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "id", In: "path", Type: "string", Required: true, Description: "User ID"},
+			{Name: "If-Match", In: "header", Type: "string", Required: false, Description: "ETag of the user the changes are based on"},
+			{Name: "userData", In: "body", Type: "handlers.UpdateUserRequest", Required: true, Description: "Updated user data"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.UserEnvelope", Description: ""},
+			{Status: 400, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Missing or invalid token"},
+			{Status: 403, Kind: "object", Type: "handlers.Problem", Description: "Insufficient permissions"},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 409, Kind: "object", Type: "handlers.Problem", Description: "Username already exists, or the user changed concurrently (current user included)"},
+			{Status: 412, Kind: "object", Type: "handlers.Problem", Description: "If-Match doesn't match the current user (current user included)"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+		Security: []string{"BearerAuth"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/v1/verify/registration/{regNumber}",
+		Handler:     "handlers.VerificationHandler.VerifyRegistration",
+		Summary:     "Verify registration",
+		Description: "Confirm participation by registration number without exposing contact details",
+		Tags:        []string{"verification"},
+		Produce:     []string{"application/json"},
+		Params: []annotations.Param{
+			{Name: "regNumber", In: "path", Type: "string", Required: true, Description: "Registration Number"},
+		},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.RegistrationVerificationResponse", Description: ""},
+			{Status: 404, Kind: "object", Type: "handlers.Problem", Description: ""},
+			{Status: 429, Kind: "object", Type: "handlers.Problem", Description: "Rate limit exceeded"},
+			{Status: 500, Kind: "object", Type: "handlers.Problem", Description: ""},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/livez",
+		Handler:     "handlers.HealthHandler.Livez",
+		Summary:     "Liveness probe",
+		Description: "Succeeds while the process can serve requests; doesn't check dependencies",
+		Tags:        []string{"health"},
+		Produce:     []string{"application/json"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.LivenessResponse", Description: ""},
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/metrics",
+		Handler:     "metrics.Handler",
+		Summary:     "Prometheus metrics",
+		Description: "HTTP, database and registration metrics in the Prometheus text format",
+		Tags:        []string{"health"},
+		Produce:     []string{"text/plain"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "string", Type: "string", Description: ""},
+			{Status: 401, Kind: "object", Type: "handlers.Problem", Description: "Invalid metrics token"},
+		},
+		Security: []string{"MetricsToken"},
+	},
+	{
+		Method:      "GET",
+		Path:        "/readyz",
+		Handler:     "handlers.HealthHandler.Readyz",
+		Summary:     "Readiness probe",
+		Description: "Pings MongoDB, checks the required indexes and background worker status",
+		Tags:        []string{"health"},
+		Produce:     []string{"application/json"},
+		Responses: []annotations.Response{
+			{Status: 200, Kind: "object", Type: "handlers.ReadinessResponse", Description: ""},
+			{Status: 503, Kind: "object", Type: "handlers.ReadinessResponse", Description: "Not ready"},
+		},
+	},
+}
+
+var schemaTypes = map[string]reflect.Type{
+	"handlers.ApproveRejectRequest":             typeOf[handlers.ApproveRejectRequest](),
+	"handlers.AuditLogResponse":                 typeOf[handlers.AuditLogResponse](),
+	"handlers.BulkCertificateRequest":           typeOf[handlers.BulkCertificateRequest](),
+	"handlers.BulkCertificateResponse":          typeOf[handlers.BulkCertificateResponse](),
+	"handlers.CatalogEntryEnvelope":             typeOf[handlers.CatalogEntryEnvelope](),
+	"handlers.CatalogEntryRequest":              typeOf[handlers.CatalogEntryRequest](),
+	"handlers.CatalogListResponse":              typeOf[handlers.CatalogListResponse](),
+	"handlers.CertificateListResponse":          typeOf[handlers.CertificateListResponse](),
+	"handlers.CertificateVerificationResponse":  typeOf[handlers.CertificateVerificationResponse](),
+	"handlers.ChangeRequestEnvelope":            typeOf[handlers.ChangeRequestEnvelope](),
+	"handlers.ChangeRequestListResponse":        typeOf[handlers.ChangeRequestListResponse](),
+	"handlers.CreateTeamRegistrationRequest":    typeOf[handlers.CreateTeamRegistrationRequest](),
+	"handlers.DeletedUserListResponse":          typeOf[handlers.DeletedUserListResponse](),
+	"handlers.EvaluateTeamRequest":              typeOf[handlers.EvaluateTeamRequest](),
+	"handlers.EvaluationListResponse":           typeOf[handlers.EvaluationListResponse](),
+	"handlers.EvaluationResultResponse":         typeOf[handlers.EvaluationResultResponse](),
+	"handlers.EventConfigResponse":              typeOf[handlers.EventConfigResponse](),
+	"handlers.EventConfigUpdateResponse":        typeOf[handlers.EventConfigUpdateResponse](),
+	"handlers.EventDetailResponse":              typeOf[handlers.EventDetailResponse](),
+	"handlers.EventEnvelope":                    typeOf[handlers.EventEnvelope](),
+	"handlers.EventListResponse":                typeOf[handlers.EventListResponse](),
+	"handlers.EventRequest":                     typeOf[handlers.EventRequest](),
+	"handlers.IndexResponse":                    typeOf[handlers.IndexResponse](),
+	"handlers.LivenessResponse":                 typeOf[handlers.LivenessResponse](),
+	"handlers.LoginRequest":                     typeOf[handlers.LoginRequest](),
+	"handlers.LoginResponse":                    typeOf[handlers.LoginResponse](),
+	"handlers.MagicLinkRequest":                 typeOf[handlers.MagicLinkRequest](),
+	"handlers.MagicLinkVerifyRequest":           typeOf[handlers.MagicLinkVerifyRequest](),
+	"handlers.MessageResponse":                  typeOf[handlers.MessageResponse](),
+	"handlers.ParticipantChangeRequestPayload":  typeOf[handlers.ParticipantChangeRequestPayload](),
+	"handlers.ParticipantLoginResponse":         typeOf[handlers.ParticipantLoginResponse](),
+	"handlers.ParticipantRegistrationResponse":  typeOf[handlers.ParticipantRegistrationResponse](),
+	"handlers.Problem":                          typeOf[handlers.Problem](),
+	"handlers.ReadinessResponse":                typeOf[handlers.ReadinessResponse](),
+	"handlers.RegistrationVerificationResponse": typeOf[handlers.RegistrationVerificationResponse](),
+	"handlers.ReviewChangeRequestRequest":       typeOf[handlers.ReviewChangeRequestRequest](),
+	"handlers.StatusResponse":                   typeOf[handlers.StatusResponse](),
+	"handlers.TeamAllocationRequest":            typeOf[handlers.TeamAllocationRequest](),
+	"handlers.TeamEnvelope":                     typeOf[handlers.TeamEnvelope](),
+	"handlers.TeamListResponse":                 typeOf[handlers.TeamListResponse](),
+	"handlers.TeamSearchResponse":               typeOf[handlers.TeamSearchResponse](),
+	"handlers.TeamStatsResponse":                typeOf[handlers.TeamStatsResponse](),
+	"handlers.UnifiedCreateUserRequest":         typeOf[handlers.UnifiedCreateUserRequest](),
+	"handlers.UpdateEventConfigRequest":         typeOf[handlers.UpdateEventConfigRequest](),
+	"handlers.UpdateTeamRegistrationRequest":    typeOf[handlers.UpdateTeamRegistrationRequest](),
+	"handlers.UpdateUserRequest":                typeOf[handlers.UpdateUserRequest](),
+	"handlers.UserEnvelope":                     typeOf[handlers.UserEnvelope](),
+	"handlers.UserListResponse":                 typeOf[handlers.UserListResponse](),
+	"models.DuplicateReport":                    typeOf[models.DuplicateReport](),
+	"models.RegistrationAnalytics":              typeOf[models.RegistrationAnalytics](),
+}
@@ -0,0 +1,306 @@
+// Package openapi builds the OpenAPI 3 document of the API from the swag-style
+// annotations on the handlers. The annotations are parsed at development time into
+// operations_gen.go (run `go generate ./openapi` after changing them); request and
+// response schemas are reflected from the annotated Go types when the document is built.
+package openapi
+
+//go:generate go run gen.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Mastermind730/igc-admin-backend/openapi/annotations"
+)
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Servers    []Server                        `json:"servers,omitempty"`
+	Tags       []Tag                           `json:"tags,omitempty"`
+	Paths      map[string]map[string]*Endpoint `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL the paths are relative to
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag groups operations in the docs page
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Endpoint is one operation on a path
+type Endpoint struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Reply     `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body an operation accepts
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Reply is a response of an operation
+type Reply struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas and the security schemes
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme describes how a client authenticates
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// securitySchemes are the schemes @Security annotations can name
+var securitySchemes = map[string]*SecurityScheme{
+	"BearerAuth": {
+		Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+		Description: "Admin or judge token returned by POST /api/v1/auth/login",
+	},
+	"ParticipantAuth": {
+		Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+		Description: "Team leader token returned by POST /api/v1/participant/auth/verify",
+	},
+	"MetricsToken": {
+		Type: "http", Scheme: "bearer",
+		Description: "Static token configured with METRICS_TOKEN; not required when unset",
+	},
+}
+
+// problemType is the error body written by middleware.ErrorHandler
+const problemType = "handlers.Problem"
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Operations returns the operations generated from the handler annotations
+func Operations() []annotations.Operation {
+	return operations
+}
+
+// Build assembles the document for the given API version
+func Build(version string) (*Document, error) {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "IGC Admin Backend API",
+			Description: "Registration, judging and certification backend of the Innovation & Green Computing challenge.",
+			Version:     version,
+		},
+		Servers: []Server{{URL: "/"}},
+		Paths:   map[string]map[string]*Endpoint{},
+		Components: Components{
+			Schemas:         map[string]*Schema{},
+			SecuritySchemes: securitySchemes,
+		},
+	}
+	b := &schemaBuilder{components: doc.Components.Schemas}
+
+	seenTags := map[string]bool{}
+	for _, op := range operations {
+		item, err := buildOperation(b, op)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s (%s): %w", op.Method, op.Path, op.Handler, err)
+		}
+		if doc.Paths[op.Path] == nil {
+			doc.Paths[op.Path] = map[string]*Endpoint{}
+		}
+		doc.Paths[op.Path][strings.ToLower(op.Method)] = item
+
+		for _, tag := range op.Tags {
+			if !seenTags[tag] {
+				seenTags[tag] = true
+				doc.Tags = append(doc.Tags, Tag{Name: tag})
+			}
+		}
+	}
+	return doc, nil
+}
+
+// JSON builds the document for the given API version and encodes it
+func JSON(version string) ([]byte, error) {
+	doc, err := Build(version)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func buildOperation(b *schemaBuilder, op annotations.Operation) (*Endpoint, error) {
+	item := &Endpoint{
+		OperationID: operationID(op),
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Responses:   map[string]*Reply{},
+	}
+
+	declared := map[string]bool{}
+	for _, p := range op.Params {
+		if p.In == "body" {
+			schema, err := typeSchema(b, p.Type)
+			if err != nil {
+				return nil, err
+			}
+			item.RequestBody = &RequestBody{
+				Description: p.Description,
+				Required:    p.Required,
+				Content:     content(op.Accept, "application/json", schema),
+			}
+			continue
+		}
+		schema := primitiveSchemas[p.Type]
+		item.Parameters = append(item.Parameters, Parameter{
+			Name:        p.Name,
+			In:          p.In,
+			Description: p.Description,
+			Required:    p.Required || p.In == "path",
+			Schema:      &schema,
+		})
+		if p.In == "path" {
+			declared[p.Name] = true
+		}
+	}
+	for _, m := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		if !declared[m[1]] {
+			return nil, fmt.Errorf("path parameter %s has no @Param", m[1])
+		}
+	}
+
+	for _, r := range op.Responses {
+		reply, err := buildReply(b, op, r)
+		if err != nil {
+			return nil, err
+		}
+		item.Responses[strconv.Itoa(r.Status)] = reply
+	}
+
+	for _, name := range op.Security {
+		if securitySchemes[name] == nil {
+			return nil, fmt.Errorf("unknown security scheme %s", name)
+		}
+		item.Security = append(item.Security, map[string][]string{name: {}})
+	}
+	return item, nil
+}
+
+func buildReply(b *schemaBuilder, op annotations.Operation, r annotations.Response) (*Reply, error) {
+	reply := &Reply{Description: r.Description}
+	if reply.Description == "" {
+		reply.Description = http.StatusText(r.Status)
+	}
+	// Failures are always JSON, whatever the operation produces on success
+	produce, jsonType := op.Produce, "application/json"
+	if r.Status >= 400 {
+		produce = nil
+	}
+	if r.Type == problemType {
+		jsonType = "application/problem+json"
+	}
+
+	switch r.Kind {
+	case "file":
+		reply.Content = content(produce, "application/octet-stream", &Schema{Type: "string", Format: "binary"})
+	case "string":
+		reply.Content = content(produce, "text/plain", &Schema{Type: "string"})
+	case "object", "array":
+		schema, err := typeSchema(b, r.Type)
+		if err != nil {
+			return nil, err
+		}
+		if r.Kind == "array" {
+			schema = &Schema{Type: "array", Items: schema}
+		}
+		reply.Content = content(produce, jsonType, schema)
+	}
+	return reply, nil
+}
+
+// typeSchema returns the schema of an annotated type name
+func typeSchema(b *schemaBuilder, name string) (*Schema, error) {
+	if name == "object" {
+		return &Schema{Type: "object"}, nil
+	}
+	t, ok := schemaTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s (run go generate ./openapi)", name)
+	}
+	return b.schema(t), nil
+}
+
+func content(types []string, fallback string, schema *Schema) map[string]MediaType {
+	if len(types) == 0 {
+		types = []string{fallback}
+	}
+	c := make(map[string]MediaType, len(types))
+	for _, t := range types {
+		c[t] = MediaType{Schema: schema}
+	}
+	return c
+}
+
+// operationID derives a stable ID from the method and path, e.g. getApiV1UsersId
+func operationID(op annotations.Operation) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(op.Method))
+	for _, part := range strings.FieldsFunc(op.Path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '{' || r == '}' || r == '.'
+	}) {
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if op.Path == "/" {
+		sb.WriteString("Root")
+	}
+	return sb.String()
+}
+
+// typeOf is used by the generated code to register annotated types
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -1,12 +1,52 @@
 package routes
 
 import (
+	"github.com/Mastermind730/igc-admin-backend/config"
 	"github.com/Mastermind730/igc-admin-backend/handlers"
 	"github.com/gin-gonic/gin"
 )
 
+// Handlers groups the handlers and shared middleware wired into the router
+type Handlers struct {
+	User         *handlers.UserHandler
+	Team         *handlers.TeamRegistrationHandler
+	Certificate  *handlers.CertificateHandler
+	Verification *handlers.VerificationHandler
+	Participant  *handlers.ParticipantHandler
+	EventConfig  *handlers.EventConfigHandler
+	Event        *handlers.EventHandler
+	Tracks       *handlers.CatalogHandler
+	Programs     *handlers.CatalogHandler
+	Audit        *handlers.AuditHandler
+	Trash        *handlers.TrashHandler
+	Health       *handlers.HealthHandler
+	Docs         *handlers.DocsHandler
+	// EventScope resolves the event (edition) a request targets
+	EventScope gin.HandlerFunc
+	// PublicRateLimit throttles unauthenticated public endpoints per client IP
+	PublicRateLimit gin.HandlerFunc
+	// Metrics serves the Prometheus metrics
+	Metrics gin.HandlerFunc
+	// Features switches optional route groups on or off
+	Features config.Features
+}
+
 // SetupRoutes configures all API routes
-func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, teamHandler *handlers.TeamRegistrationHandler) {
+func SetupRoutes(router *gin.Engine, h Handlers) {
+	userHandler := h.User
+	teamHandler := h.Team
+	certHandler := h.Certificate
+	verifyHandler := h.Verification
+	participantHandler := h.Participant
+	eventConfigHandler := h.EventConfig
+	eventHandler := h.Event
+	eventScope := h.EventScope
+	trackHandler := h.Tracks
+	programHandler := h.Programs
+	auditHandler := h.Audit
+	trashHandler := h.Trash
+	verifyLimiter := h.PublicRateLimit
+
 	// API version 1
 	api := router.Group("/api/v1")
 	{
@@ -16,9 +56,9 @@ func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, teamHand
 			auth.POST("/login", userHandler.Login)
 		}
 
-		// User routes (admin only - should be protected with middleware)
-		// users.Use(handlers.JWTAuthMiddleware())
+		// User routes (admin only)
 		users := api.Group("/users")
+		users.Use(handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"))
 		{
 			users.POST("/", userHandler.CreateUser)           // Create new admin user
 			users.GET("/", userHandler.GetAllUsers)           // Get all users with pagination
@@ -29,11 +69,15 @@ func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, teamHand
 
 		// Team registration routes
 		teams := api.Group("/team-registrations")
-		teams.Use(handlers.JWTAuthMiddleware())
+		teams.Use(handlers.JWTAuthMiddleware(), eventScope)
 		{
 			teams.POST("/", teamHandler.CreateTeamRegistration)              // Create new team registration
 			teams.GET("/", teamHandler.GetAllTeamRegistrations)              // Get all teams with filters
 			teams.GET("/stats", teamHandler.GetTeamRegistrationStats)        // Get registration statistics
+			teams.GET("/duplicates", handlers.RequireRole("admin"), teamHandler.GetDuplicateReport) // Participants registered in several teams (admin)
+			teams.GET("/analytics", handlers.RequireRole("admin"), teamHandler.GetRegistrationAnalytics) // Registration breakdowns (admin)
+			teams.GET("/analytics/export", handlers.RequireRole("admin"), teamHandler.ExportRegistrationAnalytics) // Registration breakdowns as CSV (admin)
+			teams.GET("/search", handlers.RequireRole("admin"), teamHandler.SearchTeams) // Full-text team search with facets (admin)
 			teams.GET("/:id", teamHandler.GetTeamRegistration)               // Get team by ID
 			teams.PUT("/:id", teamHandler.UpdateTeamRegistration)            // Update team registration
 			teams.DELETE("/:id", teamHandler.DeleteTeamRegistration)         // Delete team registration (admin)
@@ -46,22 +90,125 @@ func SetupRoutes(router *gin.Engine, userHandler *handlers.UserHandler, teamHand
 			teams.PUT("/:id/evaluate", userHandler.JudgeEvaluateTeam) // Judge approves/rejects team
 		}
 
+		// Certificate routes (verification is public, everything else admin only)
+		certs := api.Group("/certificates")
+		{
+			if h.Features.PublicVerification {
+				certs.GET("/verify/:code", verifyLimiter, verifyHandler.VerifyCertificate) // Verify a certificate code
+			}
+
+			adminCerts := certs.Group("", handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"), eventScope)
+			adminCerts.POST("/bulk", certHandler.GenerateCertificates)         // Bulk generate for a stage result
+			adminCerts.GET("/", certHandler.GetCertificates)                   // List issued certificates
+			adminCerts.GET("/zip", certHandler.DownloadCertificatesZIP)        // Download matching certificates as ZIP
+			adminCerts.GET("/:code/pdf", certHandler.DownloadCertificatePDF)   // Download a single certificate
+		}
+
+		// Public verification routes (rate limited, no contact details)
+		if h.Features.PublicVerification {
+			verify := api.Group("/verify")
+			verify.Use(verifyLimiter)
+			verify.GET("/registration/:regNumber", verifyHandler.VerifyRegistration) // Verify participation by registration number
+		}
+
+		// Participant portal routes (team leaders, magic-link login)
+		if h.Features.ParticipantPortal {
+			participant := api.Group("/participant")
+			participantAuth := participant.Group("/auth", verifyLimiter)
+			participantAuth.POST("/request-link", participantHandler.RequestMagicLink) // Email a one-time login link
+			participantAuth.POST("/verify", participantHandler.VerifyMagicLink)        // Exchange link token for a session
+
+			portal := participant.Group("", handlers.ParticipantAuthMiddleware())
+			portal.GET("/registration", participantHandler.GetMyRegistration)       // View own registration and status
+			portal.GET("/change-requests", participantHandler.GetMyChangeRequests)  // List own change requests
+			portal.POST("/change-requests", participantHandler.CreateChangeRequest) // Request a registration change
+		}
+
+		// Change request review routes (admin only)
+		changeRequests := api.Group("/change-requests", handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"), eventScope)
+		{
+			changeRequests.GET("/", participantHandler.GetChangeRequests)               // List change requests
+			changeRequests.PUT("/:id/action", participantHandler.ReviewChangeRequest) // Approve/Reject change request
+		}
+
+		// Event configuration routes (schedule is public, changes admin only)
+		eventConfig := api.Group("/event-config")
+		{
+			eventConfig.GET("", eventScope, eventConfigHandler.GetEventConfig)                                                          // Get registration windows and capacity
+			eventConfig.PUT("", handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"), eventScope, eventConfigHandler.UpdateEventConfig) // Update configuration
+		}
+
+		// Event (edition) routes (listing is public, changes admin only)
+		events := api.Group("/events")
+		{
+			events.GET("/", eventHandler.GetEvents)      // List events
+			events.GET("/:event", eventHandler.GetEvent) // Get event by slug or ID
+
+			adminEvents := events.Group("", handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"))
+			adminEvents.POST("/", eventHandler.CreateEvent)                  // Create event
+			adminEvents.PUT("/:event", eventHandler.UpdateEvent)             // Update tracks, rubric, deadlines, judges
+			adminEvents.POST("/:event/activate", eventHandler.ActivateEvent) // Make event the default
+		}
+
+		// Evaluation routes (admin only)
+		evaluations := api.Group("/evaluations", handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"), eventScope)
+		{
+			evaluations.GET("/", eventHandler.GetEvaluations) // List judge evaluations and scores
+		}
+
+		// Audit log routes (admin only)
+		auditLog := api.Group("/audit-log", handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"))
+		{
+			auditLog.GET("/", auditHandler.GetAuditLog)          // List recorded actions
+			auditLog.GET("/export", auditHandler.ExportAuditLog) // Export recorded actions as CSV
+		}
+
+		// Trash routes (admin only): deleted users and teams until the retention purge
+		trash := api.Group("/trash", handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"))
+		{
+			trash.GET("/teams", eventScope, trashHandler.GetDeletedTeams)           // List deleted teams of the event
+			trash.POST("/teams/:id/restore", eventScope, trashHandler.RestoreTeam)  // Restore a deleted team
+			trash.GET("/users", trashHandler.GetDeletedUsers)                       // List deleted users
+			trash.POST("/users/:id/restore", trashHandler.RestoreUser)              // Restore a deleted user
+		}
+
+		// Track and program catalogue routes (listing is public, changes admin only)
+		tracks := api.Group("/tracks")
+		{
+			tracks.GET("/", trackHandler.GetEntries)   // List active tracks
+			tracks.GET("/:slug", trackHandler.GetEntry) // Get track by slug
+
+			adminTracks := tracks.Group("", handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"))
+			adminTracks.POST("/", trackHandler.CreateEntry)        // Create track
+			adminTracks.PUT("/:slug", trackHandler.UpdateEntry)    // Update track (renames migrate teams)
+			adminTracks.DELETE("/:slug", trackHandler.DeleteEntry) // Delete unused track
+		}
+
+		programs := api.Group("/programs")
+		{
+			programs.GET("/", programHandler.GetEntries)   // List active programs
+			programs.GET("/:slug", programHandler.GetEntry) // Get program by slug
+
+			adminPrograms := programs.Group("", handlers.JWTAuthMiddleware(), handlers.RequireRole("admin"))
+			adminPrograms.POST("/", programHandler.CreateEntry)        // Create program
+			adminPrograms.PUT("/:slug", programHandler.UpdateEntry)    // Update program (renames migrate teams)
+			adminPrograms.DELETE("/:slug", programHandler.DeleteEntry) // Delete unused program
+		}
+
 		// Health check route
-		api.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{
-				"status":  "healthy",
-				"message": "IGC Admin Backend API is running",
-				"version": "1.0.0",
-			})
-		})
+		api.GET("/health", h.Health.Status)
+
+		// API documentation
+		api.GET("/openapi.json", h.Docs.OpenAPI) // OpenAPI 3 document
+		api.GET("/docs", h.Docs.UI)              // Interactive docs page
+	}
+	// Probes
+	router.GET("/livez", h.Health.Livez)   // Liveness: process is serving
+	router.GET("/readyz", h.Health.Readyz) // Readiness: MongoDB, indexes and workers
+	// Prometheus metrics
+	if h.Features.Metrics {
+		router.GET("/metrics", h.Metrics)
 	}
-	router.POST("/api/v1/create-default-admin", userHandler.CreateDefaultAdmin)
 	// Root health check
-	router.GET("/", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "Welcome to IGC Admin Backend API",
-			"version": "1.0.0",
-			"docs":    "/api/v1/health",
-		})
-	})
+	router.GET("/", h.Health.Index)
 }
\ No newline at end of file
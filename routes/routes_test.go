@@ -0,0 +1,192 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/config"
+	"github.com/Mastermind730/igc-admin-backend/handlers"
+	"github.com/Mastermind730/igc-admin-backend/middleware"
+	"github.com/Mastermind730/igc-admin-backend/models"
+	"github.com/Mastermind730/igc-admin-backend/openapi"
+	"github.com/Mastermind730/igc-admin-backend/openapi/annotations"
+	"github.com/gin-gonic/gin"
+)
+
+// testRouter sets up the router with every optional route group enabled and
+// errors rendered as problem responses
+func testRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	noop := func(c *gin.Context) {}
+	SetupRoutes(router, Handlers{
+		User:            &handlers.UserHandler{},
+		Team:            &handlers.TeamRegistrationHandler{},
+		Certificate:     &handlers.CertificateHandler{},
+		Verification:    &handlers.VerificationHandler{},
+		Participant:     &handlers.ParticipantHandler{},
+		EventConfig:     &handlers.EventConfigHandler{},
+		Event:           &handlers.EventHandler{},
+		Tracks:          &handlers.CatalogHandler{},
+		Programs:        &handlers.CatalogHandler{},
+		Audit:           &handlers.AuditHandler{},
+		Trash:           &handlers.TrashHandler{},
+		Health:          &handlers.HealthHandler{},
+		Docs:            &handlers.DocsHandler{},
+		EventScope:      noop,
+		PublicRateLimit: noop,
+		Metrics:         noop,
+		Features:        config.Features{ParticipantPortal: true, PublicVerification: true, WaitlistReconciler: true, Metrics: true},
+	})
+	return router
+}
+
+// registeredRoutes sets up the router with every optional route group enabled and
+// returns its routes as "METHOD /path/{param}"
+func registeredRoutes(t *testing.T) []string {
+	t.Helper()
+	router := testRouter()
+
+	var routes []string
+	for _, r := range router.Routes() {
+		segments := strings.Split(r.Path, "/")
+		for i, s := range segments {
+			if strings.HasPrefix(s, ":") {
+				segments[i] = "{" + s[1:] + "}"
+			}
+		}
+		routes = append(routes, r.Method+" "+strings.Join(segments, "/"))
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+func documentedRoutes() []string {
+	var routes []string
+	for _, op := range openapi.Operations() {
+		routes = append(routes, op.Method+" "+op.Path)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// TestOpenAPIMatchesRoutes fails when a route is added, removed or moved without
+// updating the @Router annotation of its handler (or vice versa)
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	registered := registeredRoutes(t)
+	documented := documentedRoutes()
+
+	for _, r := range difference(registered, documented) {
+		t.Errorf("route %s is not in the OpenAPI document; annotate its handler and run go generate ./openapi", r)
+	}
+	for _, r := range difference(documented, registered) {
+		t.Errorf("OpenAPI document lists %s, which SetupRoutes doesn't register", r)
+	}
+}
+
+// TestOpenAPIUpToDate fails when the handler annotations changed without regenerating
+func TestOpenAPIUpToDate(t *testing.T) {
+	parsed, err := annotations.Parse("..")
+	if err != nil {
+		t.Fatalf("parse annotations: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, openapi.Operations()) {
+		t.Error("openapi/operations_gen.go is out of date; run go generate ./openapi")
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	spec, err := openapi.JSON("test")
+	if err != nil {
+		t.Fatalf("build document: %v", err)
+	}
+	var doc struct {
+		OpenAPI    string                                `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("openapi = %q, want 3.0.3", doc.OpenAPI)
+	}
+
+	// Every reference must resolve to a component schema
+	for _, ref := range strings.Split(string(spec), `"$ref":"#/components/schemas/`)[1:] {
+		name := ref[:strings.Index(ref, `"`)]
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("unresolved schema reference %s", name)
+		}
+	}
+	for _, name := range []string{"handlers.Problem", "models.TeamRegistration", "models.Event"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("schema %s is missing", name)
+		}
+	}
+}
+
+// TestUserRoutesRequireAdmin makes sure user management is never reachable
+// without an admin session
+func TestUserRoutesRequireAdmin(t *testing.T) {
+	handlers.Configure(handlers.Settings{JWTSecret: "test-secret", AdminTokenTTL: time.Hour})
+	judge, err := handlers.GenerateJWT(&models.User{Username: "judge", Role: "judge"})
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+
+	router := testRouter()
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"create without token", http.MethodPost, "/api/v1/users/", "", http.StatusUnauthorized},
+		{"list without token", http.MethodGet, "/api/v1/users/", "", http.StatusUnauthorized},
+		{"get without token", http.MethodGet, "/api/v1/users/abc", "", http.StatusUnauthorized},
+		{"update without token", http.MethodPut, "/api/v1/users/abc", "", http.StatusUnauthorized},
+		{"delete without token", http.MethodDelete, "/api/v1/users/abc", "", http.StatusUnauthorized},
+		{"invalid token", http.MethodGet, "/api/v1/users/", "not-a-token", http.StatusUnauthorized},
+		{"create as judge", http.MethodPost, "/api/v1/users/", judge, http.StatusForbidden},
+		{"delete as judge", http.MethodDelete, "/api/v1/users/abc", judge, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, w.Code, tt.want)
+			}
+		})
+	}
+}
+
+// difference returns the items of a that aren't in b
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+	var diff []string
+	for _, s := range a {
+		if !in[s] {
+			diff = append(diff, s)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+)
+
+// Fake data for seeded teams; every email uses the reserved example.com domain
+var (
+	seedFirstNames   = []string{"Aarav", "Diya", "Ishaan", "Ananya", "Kabir", "Meera", "Rohan", "Saanvi", "Vihaan", "Tara", "Arjun", "Nisha"}
+	seedLastNames    = []string{"Patil", "Sharma", "Kulkarni", "Deshpande", "Iyer", "Joshi", "Gupta", "Nair", "Rao", "Mehta"}
+	seedInstitutions = []string{"PCCOE Pune", "COEP Technological University", "VIT Pune", "MIT World Peace University", "IIIT Pune", "Cummins College of Engineering"}
+	seedAdjectives   = []string{"Green", "Carbon", "Blue", "Solar", "Terra", "Eco", "Rain", "Clear"}
+	seedNouns        = []string{"Pioneers", "Sentinels", "Coders", "Wave", "Minds", "Labs", "Builders", "Squad"}
+	seedGenders      = []models.Gender{models.GenderMale, models.GenderFemale, models.GenderOther}
+)
+
+func runSeed(args []string) int {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	eventRef := fs.String("event", "", "Slug or ID of the event to seed (default: the active event)")
+	teams := fs.Int("teams", 20, "Number of fake teams to register")
+	judges := fs.Int("judges", 3, "Number of fake judges to create and assign to the event")
+	fs.Parse(args)
+
+	if *teams < 0 || *judges < 0 {
+		fmt.Fprintln(os.Stderr, "seed: -teams and -judges cannot be negative")
+		return 2
+	}
+
+	db, err := connectDatabase()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to connect to MongoDB:", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	var event *models.Event
+	if *eventRef == "" {
+		event, err = db.GetActiveEvent(ctx)
+	} else {
+		event, err = db.GetEvent(ctx, *eventRef)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load event (run migrate first?):", err)
+		return 1
+	}
+
+	tracks := event.Tracks
+	if len(tracks) == 0 {
+		tracks = models.DefaultTracks
+	}
+	programs, err := db.GetCatalogEntries(ctx, models.CatalogProgram, false)
+	if err != nil || len(programs) == 0 {
+		fmt.Fprintln(os.Stderr, "Failed to load programs (run migrate first?):", err)
+		return 1
+	}
+
+	// A suffix keeps repeated runs from colliding on team names and usernames
+	run := fmt.Sprintf("%04d", rand.IntN(10000))
+
+	for i := 1; i <= *judges; i++ {
+		judge := models.NewUser(fmt.Sprintf("judge%s-%d@example.com", run, i), "judge-"+run)
+		judge.Role = "judge"
+		created, err := db.CreateUser(ctx, judge)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to create judge:", err)
+			return 1
+		}
+		event.JudgeIDs = append(event.JudgeIDs, created.ID)
+	}
+	if *judges > 0 {
+		if _, err := db.UpdateEvent(ctx, event); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to assign judges to the event:", err)
+			return 1
+		}
+	}
+
+	for i := 1; i <= *teams; i++ {
+		team := fakeTeam(run, i, tracks, programs)
+		if _, err := db.CreateTeamRegistration(ctx, team, event); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to register team:", err)
+			return 1
+		}
+	}
+
+	fmt.Printf("✅ Seeded event %s with %d teams and %d judges\n", event.Slug, *teams, *judges)
+	if *judges > 0 {
+		fmt.Printf("   Judges log in as judge%s-<n>@example.com with password judge-%s\n", run, run)
+	}
+	return 0
+}
+
+// fakeTeam builds a plausible pending registration
+func fakeTeam(run string, n int, tracks []models.Track, programs []*models.CatalogEntry) *models.TeamRegistration {
+	pick := func(options []string) string { return options[rand.IntN(len(options))] }
+	person := func() string { return pick(seedFirstNames) + " " + pick(seedLastNames) }
+	email := func(name string, i int) string {
+		return fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(strings.ReplaceAll(name, " ", ".")), run, i)
+	}
+	mobile := func() string { return fmt.Sprintf("+9198%08d", rand.IntN(100000000)) }
+
+	team := models.NewTeamRegistration()
+	team.TeamName = fmt.Sprintf("%s %s %s-%d", pick(seedAdjectives), pick(seedNouns), run, n)
+	team.LeaderName = person()
+	team.LeaderEmail = email(team.LeaderName, n)
+	team.LeaderMobile = mobile()
+	team.LeaderGender = seedGenders[rand.IntN(len(seedGenders))]
+	team.Institution = pick(seedInstitutions)
+	team.Program = models.Program(programs[rand.IntN(len(programs))].Name)
+	team.Country = "India"
+	team.State = "Maharashtra"
+	for m := 0; m < 1+rand.IntN(3); m++ {
+		name := person()
+		team.Members = append(team.Members, models.TeamMember{
+			FullName: name,
+			Gender:   seedGenders[rand.IntN(len(seedGenders))],
+			MobileNo: mobile(),
+			Email:    email(name, n*10+m),
+		})
+	}
+	team.MentorName = "Dr. " + person()
+	team.MentorEmail = email(team.MentorName[4:], n*10+9)
+	team.MentorMobile = mobile()
+	team.MentorInstitution = team.Institution
+	team.MentorDesignation = "Assistant Professor"
+	team.Track = tracks[rand.IntN(len(tracks))]
+	team.TopicName = "AI for " + string(team.Track)
+	team.TopicDescription = "A seeded test project exploring " + strings.ToLower(string(team.Track)) + "."
+	team.PresentationPPT = models.DriveFile{FileURL: "https://example.com/presentations/" + run + ".pptx"}
+	return team
+}
@@ -0,0 +1,24 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+)
+
+// PurgeTrash permanently removes the users and team registrations that have been in
+// the trash for longer than retention
+func PurgeTrash(db *models.DatabaseService, retention time.Duration) Job {
+	return func(ctx context.Context) error {
+		report, err := db.PurgeDeleted(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if report.Teams > 0 || report.Users > 0 {
+			slog.InfoContext(ctx, "purged deleted records", "teams", report.Teams, "users", report.Users, "retention", retention.String())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Mastermind730/igc-admin-backend/models"
+)
+
+// ReconcileWaitlists promotes waitlisted teams into free track capacity in every
+// event. Promotion normally happens when a slot is released; this catches slots
+// whose promotion failed or were freed outside the API.
+func ReconcileWaitlists(db *models.DatabaseService) Job {
+	return func(ctx context.Context) error {
+		events, err := db.GetEvents(ctx)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if event.ReadOnly {
+				continue
+			}
+			promoted, err := db.PromoteAllWaitlisted(ctx, event.ID)
+			if err != nil {
+				return err
+			}
+			if len(promoted) > 0 {
+				slog.InfoContext(ctx, "promoted waitlisted teams", "count", len(promoted), "event", event.Slug)
+			}
+		}
+		return nil
+	}
+}
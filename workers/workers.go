@@ -0,0 +1,167 @@
+// Package workers runs periodic background jobs and tracks their health for the
+// readiness probe.
+package workers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var errPanic = errors.New("worker panicked")
+
+// Job is one run of a background worker
+type Job func(ctx context.Context) error
+
+// Status describes a background worker
+type Status struct {
+	Name     string `json:"name"`
+	Interval string `json:"interval"`
+	Running  bool   `json:"running"`
+	// Healthy is false once the worker stopped or hasn't completed a run for three intervals
+	Healthy   bool      `json:"healthy"`
+	Runs      int64     `json:"runs"`
+	LastRun   time.Time `json:"lastRun,omitempty"`
+	LastError string    `json:"lastError,omitempty"`
+}
+
+type worker struct {
+	name     string
+	interval time.Duration
+	job      Job
+
+	mu        sync.Mutex
+	running   bool
+	started   time.Time
+	runs      int64
+	lastRun   time.Time
+	lastError string
+}
+
+// Manager runs the registered workers
+type Manager struct {
+	workers []*worker
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewManager creates an empty Manager
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Add registers a job run every interval once the manager is started
+func (m *Manager) Add(name string, interval time.Duration, job Job) {
+	m.workers = append(m.workers, &worker{name: name, interval: interval, job: job})
+}
+
+// Start runs every worker in its own goroutine until ctx is cancelled or Stop is
+// called. Each job runs once immediately, then every interval.
+func (m *Manager) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	for _, w := range m.workers {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			w.loop(ctx)
+		}()
+	}
+}
+
+// Stop cancels the workers and waits for running jobs to return
+func (m *Manager) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+}
+
+// Status reports the state of every worker
+func (m *Manager) Status() []Status {
+	statuses := make([]Status, 0, len(m.workers))
+	for _, w := range m.workers {
+		statuses = append(statuses, w.status())
+	}
+	return statuses
+}
+
+// Healthy reports whether every worker is running and up to date
+func (m *Manager) Healthy() bool {
+	for _, w := range m.workers {
+		if !w.status().Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *worker) loop(ctx context.Context) {
+	w.mu.Lock()
+	w.running = true
+	w.started = time.Now()
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		w.running = false
+		w.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.run(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run executes the job once, recovering from panics so one bad run doesn't stop the worker
+func (w *worker) run(ctx context.Context) {
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.ErrorContext(ctx, "background worker panicked", "worker", w.name, "panic", r)
+				err = errPanic
+			}
+		}()
+		err = w.job(ctx)
+	}()
+	if err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "background worker failed", "worker", w.name, "error", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.runs++
+	w.lastRun = time.Now()
+	w.lastError = ""
+	if err != nil {
+		w.lastError = err.Error()
+	}
+}
+
+func (w *worker) status() Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// A worker that hasn't finished a run since starting is measured from its start
+	last := w.lastRun
+	if last.IsZero() {
+		last = w.started
+	}
+	return Status{
+		Name:      w.name,
+		Interval:  w.interval.String(),
+		Running:   w.running,
+		Healthy:   w.running && time.Since(last) < 3*w.interval,
+		Runs:      w.runs,
+		LastRun:   w.lastRun,
+		LastError: w.lastError,
+	}
+}